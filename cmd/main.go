@@ -11,7 +11,9 @@ import (
 func main() {
 	startCmd := streamweaverbroker.NewStartCmd()
 	simulateCmd := streamweaverbroker.NewSimulateCmd()
-	rootCmd := streamweaverbroker.NewBaseCommand([]*cobra.Command{startCmd, simulateCmd})
+	archiveCmd := streamweaverbroker.NewArchiveCmd()
+	retentionCmd := streamweaverbroker.NewRetentionCmd()
+	rootCmd := streamweaverbroker.NewBaseCommand([]*cobra.Command{startCmd, simulateCmd, archiveCmd, retentionCmd})
 
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
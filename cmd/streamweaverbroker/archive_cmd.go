@@ -0,0 +1,158 @@
+package streamweaverbroker
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/streamweaverio/broker/internal/archiver"
+	"github.com/streamweaverio/broker/internal/config"
+	"github.com/streamweaverio/broker/internal/logging"
+	"github.com/streamweaverio/broker/internal/redis"
+	"go.uber.org/zap"
+)
+
+func NewArchiveCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "archive",
+		Short: "Manage messages archived to storage",
+		Run: func(cmd *cobra.Command, args []string) {
+			err := cmd.Help()
+			if err != nil {
+				panic(err)
+			}
+		},
+	}
+
+	cmd.AddCommand(NewArchiveRestoreCmd())
+
+	return cmd
+}
+
+func NewArchiveRestoreCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore",
+		Short: "Restore archived messages back into a Redis stream",
+		Long: `Restore archived messages of a stream within a time window back into a Redis stream.
+
+The target stream must already exist. Original message IDs are kept when the target stream allows it,
+messages older than the target stream's last entry are appended with new IDs. Restored messages keep
+their original timestamps, so make sure the target stream's max_age covers the restored window or
+use --new-ids, otherwise retention removes them again.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			streamName, _ := cmd.Flags().GetString("stream")
+			targetStream, _ := cmd.Flags().GetString("target")
+			from, _ := cmd.Flags().GetString("from")
+			to, _ := cmd.Flags().GetString("to")
+			newIds, _ := cmd.Flags().GetBool("new-ids")
+
+			startTimestamp, err := ParseRestoreTime(from)
+			if err != nil {
+				fmt.Printf("Invalid --from value: %v\n", err)
+				os.Exit(1)
+			}
+
+			endTimestamp, err := ParseRestoreTime(to)
+			if err != nil {
+				fmt.Printf("Invalid --to value: %v\n", err)
+				os.Exit(1)
+			}
+
+			configFile, _ := cmd.Flags().GetString("config")
+			cfg, err := config.ReadConfiguration(configFile)
+			if err != nil {
+				fmt.Printf("Error reading configuration: %v\n", err)
+				os.Exit(1)
+			}
+
+			logger, err := logging.NewLogger(&logging.LoggerOptions{
+				LogLevel:      cfg.Logging.LogLevel,
+				LogOutput:     cfg.Logging.LogOutput,
+				LogFormat:     cfg.Logging.LogFormat,
+				LogFilePrefix: cfg.Logging.LogFilePrefix,
+				LogDirectory:  cfg.Logging.LogDirectory,
+			})
+			if err != nil {
+				fmt.Printf("Error creating logger: %v\n", err)
+				os.Exit(1)
+			}
+
+			redisClient, err := redis.NewClusterClient(&redis.ClusterClientOptions{
+				Ctx:            ctx,
+				Nodes:          MakeRedisNodeAddresses(cfg.Redis.Hosts),
+				Password:       cfg.Redis.Password,
+				DB:             cfg.Redis.DB,
+				MaxPingRetries: 10,
+			}, logger)
+			if err != nil {
+				logger.Fatal("Error creating Redis cluster client", zap.Error(err))
+				os.Exit(1)
+			}
+
+			storageDriver, err := GetStorage(cfg, logger)
+			if err != nil {
+				logger.Fatal("error creating storage", zap.Error(err))
+				os.Exit(1)
+			}
+
+			restorer := archiver.NewRestorer(&archiver.RestorerOptions{
+				Reader: archiver.NewReader(&archiver.ArchiveReaderOptions{
+					Storage: storageDriver,
+				}, logger),
+				StreamService: redis.NewRedisStreamService(&redis.RedisStreamServiceOptions{
+					Ctx:                    ctx,
+					MetadataService:        redis.NewStreamMetadataService(ctx, redisClient, logger),
+					RedisClient:            redisClient,
+					GlobalRetentionOptions: cfg.Retention,
+				}, logger),
+			}, logger)
+
+			result, err := restorer.Restore(ctx, &archiver.RestoreParameters{
+				StreamName:     streamName,
+				TargetStream:   targetStream,
+				StartTimestamp: startTimestamp,
+				EndTimestamp:   endTimestamp,
+				KeepIDs:        !newIds,
+			})
+			if err != nil {
+				logger.Fatal("error restoring archived messages", zap.Error(err))
+				os.Exit(1)
+			}
+
+			fmt.Printf("Restored %d messages, %d with new IDs\n", result.Restored, result.Reassigned)
+		},
+	}
+
+	cmd.Flags().StringP("stream", "s", "", "Name of the archived stream")
+	cmd.Flags().StringP("target", "t", "", "Stream to restore the messages into, defaults to the archived stream")
+	cmd.Flags().String("from", "", "Start of the time window, RFC3339 or Unix milliseconds")
+	cmd.Flags().String("to", "", "End of the time window, RFC3339 or Unix milliseconds")
+	cmd.Flags().Bool("new-ids", false, "Assign new IDs instead of keeping the original ones")
+	_ = cmd.MarkFlagRequired("stream")
+
+	return cmd
+}
+
+// Parses a time given as RFC3339 or Unix milliseconds into Unix milliseconds, an empty value is 0
+func ParseRestoreTime(value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	if ms, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return ms, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return 0, fmt.Errorf("expected RFC3339 or Unix milliseconds: %w", err)
+	}
+
+	return t.UnixMilli(), nil
+}
@@ -0,0 +1,128 @@
+package streamweaverbroker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/streamweaverio/broker/internal/config"
+	"github.com/streamweaverio/broker/internal/logging"
+	"github.com/streamweaverio/broker/internal/redis"
+	"github.com/streamweaverio/broker/internal/retention"
+	"go.uber.org/zap"
+)
+
+func NewRetentionCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "retention",
+		Short: "Inspect retention policies",
+		Run: func(cmd *cobra.Command, args []string) {
+			err := cmd.Help()
+			if err != nil {
+				panic(err)
+			}
+		},
+	}
+
+	cmd.AddCommand(NewRetentionPlanCmd())
+
+	return cmd
+}
+
+func NewRetentionPlanCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "plan",
+		Short: "Report what the retention policies would do to every stream without modifying anything",
+		Long: `Report what the retention policies of the configuration would do to every registered stream.
+
+For every policy and stream the computed min ID, the number of messages that would be deleted
+or archived, the number of storage blocks they would be archived to and the stream's cleanup
+policy are printed. Nothing is deleted, archived or written to storage.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			configFile, _ := cmd.Flags().GetString("config")
+			cfg, err := config.ReadConfiguration(configFile)
+			if err != nil {
+				fmt.Printf("Error reading configuration: %v\n", err)
+				os.Exit(1)
+			}
+
+			logger, err := logging.NewLogger(&logging.LoggerOptions{
+				LogLevel:      cfg.Logging.LogLevel,
+				LogOutput:     cfg.Logging.LogOutput,
+				LogFormat:     cfg.Logging.LogFormat,
+				LogFilePrefix: cfg.Logging.LogFilePrefix,
+				LogDirectory:  cfg.Logging.LogDirectory,
+			})
+			if err != nil {
+				fmt.Printf("Error creating logger: %v\n", err)
+				os.Exit(1)
+			}
+
+			redisClient, err := redis.NewClusterClient(&redis.ClusterClientOptions{
+				Ctx:            ctx,
+				Nodes:          MakeRedisNodeAddresses(cfg.Redis.Hosts),
+				Password:       cfg.Redis.Password,
+				DB:             cfg.Redis.DB,
+				MaxPingRetries: 10,
+			}, logger)
+			if err != nil {
+				logger.Fatal("Error creating Redis cluster client", zap.Error(err))
+				os.Exit(1)
+			}
+
+			metadataService := redis.NewStreamMetadataService(ctx, redisClient, logger)
+			streamService := redis.NewRedisStreamService(&redis.RedisStreamServiceOptions{
+				Ctx:                    ctx,
+				MetadataService:        metadataService,
+				RedisClient:            redisClient,
+				GlobalRetentionOptions: cfg.Retention,
+			}, logger)
+
+			retentionManager, err := retention.NewRetentionManager(&retention.RetentionManagerOptions{
+				Interval:        cfg.Retention.Interval,
+				ShutdownTimeout: cfg.Retention.ShutdownTimeout,
+			}, logger)
+			if err != nil {
+				logger.Fatal("error creating retention manager", zap.Error(err))
+				os.Exit(1)
+			}
+
+			// Planning never archives, so no storage is needed
+			RegisterRetentionPolicies(retentionManager, cfg, metadataService, streamService, nil, logger)
+
+			plans, err := retentionManager.Plan(ctx)
+			if err != nil {
+				logger.Fatal("error planning retention policies", zap.Error(err))
+				os.Exit(1)
+			}
+
+			PrintRetentionPlans(os.Stdout, plans)
+		},
+	}
+}
+
+// Prints the retention plans as a table
+func PrintRetentionPlans(out io.Writer, plans []*retention.StreamPlan) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "POLICY\tSTREAM\tCLEANUP POLICY\tMIN ID\tDELETED\tARCHIVED\tBLOCKS\tERROR")
+	for _, plan := range plans {
+		minID := plan.MinID
+		if minID == "" {
+			minID = "-"
+		}
+
+		planErr := "-"
+		if plan.Err != nil {
+			planErr = plan.Err.Error()
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%d\t%d\t%s\n", plan.Policy, plan.Stream, plan.CleanupPolicy, minID, plan.Deleted, plan.Archived, plan.Blocks, planErr)
+	}
+	w.Flush()
+}
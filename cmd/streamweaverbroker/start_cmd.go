@@ -6,12 +6,15 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/streamweaverio/broker/internal/archiver"
 	"github.com/streamweaverio/broker/internal/broker"
 	"github.com/streamweaverio/broker/internal/config"
+	"github.com/streamweaverio/broker/internal/leader"
 	"github.com/streamweaverio/broker/internal/logging"
+	"github.com/streamweaverio/broker/internal/reclaimer"
 	"github.com/streamweaverio/broker/internal/redis"
 	"github.com/streamweaverio/broker/internal/retention"
 	"github.com/streamweaverio/broker/internal/s3"
@@ -64,17 +67,26 @@ func NewStartCmd() *cobra.Command {
 			}
 
 			metadataService := redis.NewStreamMetadataService(ctx, redisClient, logger)
+			schemaRegistry := redis.NewSchemaRegistryService(ctx, redisClient, logger)
 
 			redisStreamService := redis.NewRedisStreamService(&redis.RedisStreamServiceOptions{
 				Ctx:                    ctx,
 				MetadataService:        metadataService,
 				RedisClient:            redisClient,
 				GlobalRetentionOptions: cfg.Retention,
+				MaxPublishBatchSize:    cfg.Publish.MaxBatchSize,
+				DedupWindow:            cfg.Publish.DedupWindow,
+				DedupMaxEntries:        cfg.Publish.DedupMaxEntries,
+				DefaultMessageFormat:   cfg.Publish.MessageFormat,
+				SchemaRegistry:         schemaRegistry,
 			}, logger)
 
-			grpcServer := grpc.NewServer()
-			// RPC Handler for broker
-			rpcHandler := broker.NewRPCHandler(redisStreamService, logger)
+			consumerGroupService := redis.NewConsumerGroupService(&redis.ConsumerGroupServiceOptions{
+				Ctx:                    ctx,
+				RedisClient:            redisClient,
+				MetadataService:        metadataService,
+				DefaultReclaimIdleTime: time.Duration(cfg.ConsumerGroups.ReclaimIdleTime) * time.Millisecond,
+			}, logger)
 
 			// Create storage from storage config
 			storageDriver, err := GetStorage(cfg, logger)
@@ -83,6 +95,30 @@ func NewStartCmd() *cobra.Command {
 				os.Exit(1)
 			}
 
+			// Reader for messages that were moved to storage
+			archiveReader := archiver.NewReader(&archiver.ArchiveReaderOptions{
+				Storage: storageDriver,
+			}, logger)
+
+			// Elects the only instance running retention policies when several brokers share the Redis cluster
+			retentionElector := leader.New(&leader.ElectorOptions{
+				Name:          "retention",
+				InstanceId:    GetInstanceId(cfg),
+				LeaseTime:     cfg.LeaderElection.LeaseTime,
+				RenewInterval: cfg.LeaderElection.RenewInterval,
+				LeaseService:  redis.NewLeaseService(ctx, redisClient, logger),
+			}, logger)
+
+			grpcServer := grpc.NewServer()
+			// RPC Handler for broker
+			rpcHandler := broker.NewRPCHandler(&broker.RPCHandlerOptions{
+				StreamService:        redisStreamService,
+				ConsumerGroupService: consumerGroupService,
+				ArchiveReader:        archiveReader,
+				RetentionElector:     retentionElector,
+				SchemaRegistry:       schemaRegistry,
+			}, logger)
+
 			// Create archiver instance with storage driver
 			archiver := archiver.New(&archiver.ArchiverOptions{
 				Storage: storageDriver,
@@ -90,7 +126,12 @@ func NewStartCmd() *cobra.Command {
 
 			// Retention Manager
 			retentionManager, err := retention.NewRetentionManager(&retention.RetentionManagerOptions{
-				Interval: 30,
+				Interval:        cfg.Retention.Interval,
+				ShutdownTimeout: cfg.Retention.ShutdownTimeout,
+				Elector:         retentionElector,
+				Scheduler: retention.NewStreamScheduler(&retention.StreamSchedulerOptions{
+					StreamMetadataservice: metadataService,
+				}, logger),
 			}, logger)
 			if err != nil {
 				logger.Fatal("error creating retention manager", zap.Error(err))
@@ -98,16 +139,23 @@ func NewStartCmd() *cobra.Command {
 			}
 
 			// Register retention policies
-			// Time Retention Policy (default)
-			timeRetentionPolicy := retention.NewTimeRetentionPolicy(&retention.TimeRetentionPolicyOpts{
-				CancelCtx:             ctx,
-				StreamMetadataservice: metadataService,
-				Streamservice:         redisStreamService,
-				RegistryKey:           redis.STREAM_REGISTRY_KEY,
-				Archiver:              archiver,
-				MessageBatchSize:      10000,
+			RegisterRetentionPolicies(retentionManager, cfg, metadataService, redisStreamService, archiver, logger)
+
+			// Elects the only instance reclaiming pending messages, separate from retention so both can run on different instances
+			reclaimerElector := leader.New(&leader.ElectorOptions{
+				Name:          "reclaimer",
+				InstanceId:    GetInstanceId(cfg),
+				LeaseTime:     cfg.LeaderElection.LeaseTime,
+				RenewInterval: cfg.LeaderElection.RenewInterval,
+				LeaseService:  redis.NewLeaseService(ctx, redisClient, logger),
+			}, logger)
+
+			// Reclaimer for pending consumer group messages
+			pendingReclaimer := reclaimer.New(&reclaimer.ReclaimerOptions{
+				Interval:             cfg.ConsumerGroups.ReclaimInterval,
+				ConsumerGroupService: consumerGroupService,
+				Elector:              reclaimerElector,
 			}, logger)
-			retentionManager.RegisterPolicy(&retention.RetentionPolicy{Name: "time", Rule: timeRetentionPolicy})
 
 			// Create broker
 			b := broker.New(&broker.Options{
@@ -116,6 +164,7 @@ func NewStartCmd() *cobra.Command {
 				Logger: logger,
 				Server: grpcServer,
 				RPC:    rpcHandler,
+				RPCV1:  rpcHandler,
 			})
 
 			if err := process.CreatePIDFile(processPIDFile, os.Getpid()); err != nil {
@@ -131,18 +180,42 @@ func NewStartCmd() *cobra.Command {
 			}()
 
 			go func() {
-				if err := retentionManager.Start(); err != nil {
+				if err := retentionElector.Start(); err != nil {
+					logger.Fatal("error starting retention leader election", zap.Error(err))
+					cancel()
+				}
+			}()
+
+			go func() {
+				if err := retentionManager.Start(ctx); err != nil {
 					logger.Fatal("error starting retention manager", zap.Error(err))
 					cancel()
 				}
 			}()
 
+			go func() {
+				if err := reclaimerElector.Start(); err != nil {
+					logger.Fatal("error starting reclaimer leader election", zap.Error(err))
+					cancel()
+				}
+			}()
+
+			go func() {
+				if err := pendingReclaimer.Start(); err != nil {
+					logger.Fatal("error starting pending message reclaimer", zap.Error(err))
+					cancel()
+				}
+			}()
+
 			quit := make(chan os.Signal, 1)
 			signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 			<-quit
 
 			b.Stop()
 			retentionManager.Stop()
+			retentionElector.Stop()
+			pendingReclaimer.Stop()
+			reclaimerElector.Stop()
 
 			if err := process.RemovePIDFile(processPIDFile); err != nil {
 				logger.Error("error removing PID file", zap.Error(err))
@@ -152,6 +225,48 @@ func NewStartCmd() *cobra.Command {
 	}
 }
 
+// Registers the time, size and count retention policies with the retention manager
+func RegisterRetentionPolicies(manager retention.RetentionManager, cfg *config.StreamWeaverConfig, metadataService redis.StreamMetadataService, streamService redis.RedisStreamService, archiver archiver.Archiver, logger logging.LoggerContract) {
+	// Shared by the retention policies to apply them to several streams at once
+	workerPool := &retention.WorkerPoolOptions{
+		Workers:       cfg.Retention.Workers,
+		StreamTimeout: time.Duration(cfg.Retention.StreamTimeout) * time.Millisecond,
+		Jitter:        time.Duration(cfg.Retention.Jitter) * time.Millisecond,
+	}
+
+	// Time Retention Policy (default)
+	timeRetentionPolicy := retention.NewTimeRetentionPolicy(&retention.TimeRetentionPolicyOpts{
+		StreamMetadataservice: metadataService,
+		Streamservice:         streamService,
+		RegistryKey:           redis.STREAM_REGISTRY_KEY,
+		Archiver:              archiver,
+		MessageBatchSize:      10000,
+		WorkerPool:            workerPool,
+	}, logger)
+	manager.RegisterPolicy(&retention.RetentionPolicy{Name: "time", Rule: timeRetentionPolicy})
+
+	// Size Retention Policy
+	sizeRetentionPolicy := retention.NewSizeRetentionPolicy(&retention.SizeRetentionPolicyOpts{
+		StreamMetadataservice: metadataService,
+		Streamservice:         streamService,
+		MaxSize:               cfg.Retention.MaxSize,
+		Archiver:              archiver,
+		MessageBatchSize:      10000,
+		WorkerPool:            workerPool,
+	}, logger)
+	manager.RegisterPolicy(&retention.RetentionPolicy{Name: "size", Rule: sizeRetentionPolicy})
+
+	// Count Retention Policy
+	countRetentionPolicy := retention.NewCountRetentionPolicy(&retention.CountRetentionPolicyOpts{
+		StreamMetadataservice: metadataService,
+		Streamservice:         streamService,
+		Archiver:              archiver,
+		MessageBatchSize:      10000,
+		WorkerPool:            workerPool,
+	}, logger)
+	manager.RegisterPolicy(&retention.RetentionPolicy{Name: "count", Rule: countRetentionPolicy})
+}
+
 // Create a list of redis node addresses
 func MakeRedisNodeAddresses(hosts []*config.RedisHostConfig) []string {
 	var nodes []string
@@ -161,13 +276,31 @@ func MakeRedisNodeAddresses(hosts []*config.RedisHostConfig) []string {
 	return nodes
 }
 
+// Gets the ID of this broker instance, defaults to the hostname and process ID
+func GetInstanceId(cfg *config.StreamWeaverConfig) string {
+	if cfg.LeaderElection.InstanceId != "" {
+		return cfg.LeaderElection.InstanceId
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+
+	return fmt.Sprintf("%s-%d", hostname, os.Getpid())
+}
+
 // Creates a storage manager
 func GetStorage(cfg *config.StreamWeaverConfig, logger logging.LoggerContract) (storage.Storage, error) {
 	if cfg.Storage.Provider == "s3" {
 		client, err := s3.NewClient(&s3.S3ClientOptions{
 			AccessKeyId:     cfg.Storage.S3.AccessKeyId,
 			AccessKeySecret: cfg.Storage.S3.SecretAccessKey,
+			SessionToken:    cfg.Storage.S3.SessionToken,
 			Region:          cfg.Storage.S3.Region,
+			Endpoint:        cfg.Storage.S3.Endpoint,
+			ForcePathStyle:  cfg.Storage.S3.ForcePathStyle,
+			DisableSSL:      cfg.Storage.S3.DisableSSL,
 		})
 		if err != nil {
 			return nil, err
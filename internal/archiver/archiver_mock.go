@@ -0,0 +1,21 @@
+package archiver
+
+import (
+	"context"
+
+	rdb "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/mock"
+)
+
+type ArchiverMock struct {
+	mock.Mock
+}
+
+func NewArchiverMock() *ArchiverMock {
+	return &ArchiverMock{}
+}
+
+func (m *ArchiverMock) Archive(ctx context.Context, streamName string, messages []rdb.XMessage) error {
+	args := m.Called(ctx, streamName, messages)
+	return args.Error(0)
+}
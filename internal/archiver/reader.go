@@ -0,0 +1,232 @@
+package archiver
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	rdb "github.com/redis/go-redis/v9"
+	"github.com/streamweaverio/broker/internal/block"
+	"github.com/streamweaverio/broker/internal/logging"
+	"github.com/streamweaverio/broker/internal/storage"
+	"github.com/streamweaverio/broker/pkg/utils"
+	"github.com/xitongsys/parquet-go-source/buffer"
+	"github.com/xitongsys/parquet-go/reader"
+	"go.uber.org/zap"
+)
+
+type ArchiveReader interface {
+	// Read the archived messages of a stream in ID order, handler is called with the matching messages of each block
+	ReadMessages(ctx context.Context, params *ReadArchiveParameters, handler func(messages []rdb.XMessage) error) error
+}
+
+type ReadArchiveParameters struct {
+	StreamName string
+	// Time range of the messages in milliseconds, inclusive, zero leaves a bound open
+	StartTimestamp int64
+	EndTimestamp   int64
+	// Message ID range, inclusive, an empty ID leaves a bound open
+	StartID string
+	EndID   string
+}
+
+type ArchiveReaderOptions struct {
+	Storage storage.Storage
+}
+
+type ArchiveReaderImpl struct {
+	Storage storage.Storage
+	Logger  logging.LoggerContract
+}
+
+// Create a new ArchiveReader instance
+func NewReader(opts *ArchiveReaderOptions, logger logging.LoggerContract) ArchiveReader {
+	return &ArchiveReaderImpl{
+		Storage: opts.Storage,
+		Logger:  logger,
+	}
+}
+
+func (p *ReadArchiveParameters) Validate() error {
+	if p.StreamName == "" {
+		return fmt.Errorf("stream name is required")
+	}
+
+	if (p.StartTimestamp != 0 || p.EndTimestamp != 0) && (p.StartID != "" || p.EndID != "") {
+		return fmt.Errorf("either a time range or a message ID range can be given, not both")
+	}
+
+	if p.EndTimestamp != 0 && p.StartTimestamp > p.EndTimestamp {
+		return fmt.Errorf("start timestamp must not be after end timestamp")
+	}
+
+	for _, id := range []string{p.StartID, p.EndID} {
+		if id == "" {
+			continue
+		}
+
+		if _, _, err := utils.ParseStreamMessageID(id); err != nil {
+			return err
+		}
+	}
+
+	if p.StartID != "" && p.EndID != "" && utils.CompareStreamMessageIDs(p.StartID, p.EndID) > 0 {
+		return fmt.Errorf("start message ID must not be after end message ID")
+	}
+
+	return nil
+}
+
+// Gets the time range blocks are selected by, message ID ranges are narrowed down to the timestamps of their IDs
+func (p *ReadArchiveParameters) BlockFilter() *storage.BlockFilter {
+	filter := &storage.BlockFilter{
+		StartTimestamp: p.StartTimestamp,
+		EndTimestamp:   p.EndTimestamp,
+	}
+
+	if p.StartID != "" {
+		filter.StartTimestamp, _ = utils.SplitStreamMessageID(p.StartID)
+	}
+
+	if p.EndID != "" {
+		filter.EndTimestamp, _ = utils.SplitStreamMessageID(p.EndID)
+	}
+
+	return filter
+}
+
+// Checks if a message ID falls within the requested range
+func (p *ReadArchiveParameters) Matches(id string) bool {
+	if p.StartID != "" && utils.CompareStreamMessageIDs(id, p.StartID) < 0 {
+		return false
+	}
+
+	if p.EndID != "" && utils.CompareStreamMessageIDs(id, p.EndID) > 0 {
+		return false
+	}
+
+	timestamp, _ := utils.SplitStreamMessageID(id)
+	if p.StartTimestamp != 0 && timestamp < p.StartTimestamp {
+		return false
+	}
+
+	if p.EndTimestamp != 0 && timestamp > p.EndTimestamp {
+		return false
+	}
+
+	return true
+}
+
+func (r *ArchiveReaderImpl) ReadMessages(ctx context.Context, params *ReadArchiveParameters, handler func(messages []rdb.XMessage) error) error {
+	if err := params.Validate(); err != nil {
+		return err
+	}
+
+	blocks, err := r.Storage.ListBlocks(ctx, params.StreamName, params.BlockFilter())
+	if err != nil {
+		return fmt.Errorf("failed to list blocks: %w", err)
+	}
+
+	// Blocks overlap when a range was archived more than once, messages at or before the last one handled are skipped
+	lastId := ""
+
+	for _, meta := range blocks {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if lastId != "" && utils.CompareStreamMessageIDs(meta.BlockEndId, lastId) <= 0 {
+			continue
+		}
+
+		rows, err := r.ReadBlock(ctx, params.StreamName, meta.BlockID)
+		if err != nil {
+			return err
+		}
+
+		messages := make([]rdb.XMessage, 0, len(rows))
+		for _, row := range rows {
+			if lastId != "" && utils.CompareStreamMessageIDs(row.MessageID, lastId) <= 0 {
+				continue
+			}
+
+			if !params.Matches(row.MessageID) {
+				continue
+			}
+
+			message, err := MessageFromBlockParquet(row)
+			if err != nil {
+				return err
+			}
+
+			messages = append(messages, message)
+		}
+
+		if len(messages) == 0 {
+			continue
+		}
+
+		if err := handler(messages); err != nil {
+			return err
+		}
+
+		lastId = messages[len(messages)-1].ID
+	}
+
+	r.Logger.Debug("Read archived messages", zap.String("stream", params.StreamName), zap.Int("blocks", len(blocks)), zap.String("last_id", lastId))
+	return nil
+}
+
+// Reads the rows of a block's parquet file
+func (r *ArchiveReaderImpl) ReadBlock(ctx context.Context, streamName string, blockId string) ([]block.BlockParquet, error) {
+	b, err := r.Storage.GetBlock(ctx, streamName, blockId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block %s: %w", blockId, err)
+	}
+	defer b.Parquet.Close()
+	defer b.Bloom.Close()
+
+	// The parquet reader needs random access, so the file is read into memory
+	data, err := io.ReadAll(b.Parquet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read parquet file of block %s: %w", blockId, err)
+	}
+
+	rows, err := DeserializeFromParquet(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode block %s: %w", blockId, err)
+	}
+
+	return rows, nil
+}
+
+// Decodes the rows of a parquet file written by SerializeToParquet
+func DeserializeFromParquet(data []byte) ([]block.BlockParquet, error) {
+	file, err := buffer.NewBufferFile(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open parquet file: %w", err)
+	}
+
+	pr, err := reader.NewParquetReader(file, new(block.BlockParquet), 4)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Parquet reader: %w", err)
+	}
+	defer pr.ReadStop()
+
+	rows := make([]block.BlockParquet, pr.GetNumRows())
+	if err := pr.Read(&rows); err != nil {
+		return nil, fmt.Errorf("failed to read Parquet rows: %w", err)
+	}
+
+	return rows, nil
+}
+
+// Converts an archived row back into a stream message
+func MessageFromBlockParquet(row block.BlockParquet) (rdb.XMessage, error) {
+	values, err := utils.DeserializeStreamMessageValues(row.Data)
+	if err != nil {
+		return rdb.XMessage{}, fmt.Errorf("failed to decode values of message %s: %w", row.MessageID, err)
+	}
+
+	return rdb.XMessage{ID: row.MessageID, Values: values}, nil
+}
@@ -0,0 +1,27 @@
+package archiver
+
+import (
+	"context"
+
+	rdb "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/mock"
+)
+
+// Mock ArchiveReader which passes the messages returned by the expectation to the handler as a single batch
+type ArchiveReaderMock struct {
+	mock.Mock
+}
+
+func NewArchiveReaderMock() *ArchiveReaderMock {
+	return &ArchiveReaderMock{}
+}
+
+func (m *ArchiveReaderMock) ReadMessages(ctx context.Context, params *ReadArchiveParameters, handler func(messages []rdb.XMessage) error) error {
+	args := m.Called(ctx, params)
+	if messages, ok := args.Get(0).([]rdb.XMessage); ok && len(messages) > 0 {
+		if err := handler(messages); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
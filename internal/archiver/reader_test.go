@@ -0,0 +1,107 @@
+package archiver
+
+import (
+	"context"
+	"testing"
+
+	rdb "github.com/redis/go-redis/v9"
+	"github.com/streamweaverio/broker/internal/block"
+	"github.com/streamweaverio/broker/internal/storage"
+	"github.com/streamweaverio/broker/internal/testutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func makeMessages(ids ...string) []rdb.XMessage {
+	messages := make([]rdb.XMessage, len(ids))
+	for i, id := range ids {
+		messages[i] = rdb.XMessage{ID: id, Values: map[string]interface{}{"event_name": "event " + id}}
+	}
+	return messages
+}
+
+func setupArchiveReader(t *testing.T) ArchiveReader {
+	logger := testutils.NewMockLogger()
+	store, err := storage.NewLocalFilesystemDriver(t.TempDir())
+	assert.NoError(t, err)
+
+	archiver := New(&ArchiverOptions{Storage: store}, logger)
+	// The blocks overlap on 3-0 as if the range had been archived twice
+	assert.NoError(t, archiver.Archive(context.Background(), "test-stream", makeMessages("1-0", "2-0", "3-0")))
+	assert.NoError(t, archiver.Archive(context.Background(), "test-stream", makeMessages("3-0", "4-0", "5-0")))
+
+	return NewReader(&ArchiveReaderOptions{Storage: store}, logger)
+}
+
+func readIDs(t *testing.T, r ArchiveReader, params *ReadArchiveParameters) []string {
+	ids := make([]string, 0)
+	err := r.ReadMessages(context.Background(), params, func(messages []rdb.XMessage) error {
+		for _, msg := range messages {
+			ids = append(ids, msg.ID)
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	return ids
+}
+
+func TestArchiveReader_ReadMessages(t *testing.T) {
+	r := setupArchiveReader(t)
+
+	t.Run("Read every archived message once in ID order", func(t *testing.T) {
+		assert.Equal(t, []string{"1-0", "2-0", "3-0", "4-0", "5-0"}, readIDs(t, r, &ReadArchiveParameters{StreamName: "test-stream"}))
+	})
+
+	t.Run("Read a message ID range", func(t *testing.T) {
+		assert.Equal(t, []string{"2-0", "3-0", "4-0"}, readIDs(t, r, &ReadArchiveParameters{StreamName: "test-stream", StartID: "2-0", EndID: "4-0"}))
+	})
+
+	t.Run("Read a time range", func(t *testing.T) {
+		assert.Equal(t, []string{"4-0", "5-0"}, readIDs(t, r, &ReadArchiveParameters{StreamName: "test-stream", StartTimestamp: 4, EndTimestamp: 9}))
+	})
+
+	t.Run("Decode the message values", func(t *testing.T) {
+		var values map[string]interface{}
+		err := r.ReadMessages(context.Background(), &ReadArchiveParameters{StreamName: "test-stream", StartID: "1-0", EndID: "1-0"}, func(messages []rdb.XMessage) error {
+			values = messages[0].Values
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "event 1-0", values["event_name"])
+	})
+
+	t.Run("Decode values archived as plain JSON", func(t *testing.T) {
+		msg, err := MessageFromBlockParquet(block.BlockParquet{MessageID: "1-0", Data: `{"event_name":"login"}`})
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"event_name": "login"}, msg.Values)
+	})
+
+	t.Run("Reject a time range combined with a message ID range", func(t *testing.T) {
+		err := r.ReadMessages(context.Background(), &ReadArchiveParameters{StreamName: "test-stream", StartTimestamp: 1, StartID: "1-0"}, func(messages []rdb.XMessage) error {
+			return nil
+		})
+		assert.Error(t, err)
+	})
+}
+
+func TestArchiveReader_ReadBinaryMessages(t *testing.T) {
+	logger := testutils.NewMockLogger()
+	store, err := storage.NewLocalFilesystemDriver(t.TempDir())
+	assert.NoError(t, err)
+
+	// Not valid UTF-8, JSON would replace these bytes with U+FFFD
+	payload := string([]byte{0x00, 0xff, 0xfe, 0x80, 'o', 'k', 0xc3})
+	archiver := New(&ArchiverOptions{Storage: store}, logger)
+	assert.NoError(t, archiver.Archive(context.Background(), "binary-stream", []rdb.XMessage{
+		{ID: "1-0", Values: map[string]interface{}{"payload": payload}},
+	}))
+
+	var values map[string]interface{}
+	r := NewReader(&ArchiveReaderOptions{Storage: store}, logger)
+	err = r.ReadMessages(context.Background(), &ReadArchiveParameters{StreamName: "binary-stream"}, func(messages []rdb.XMessage) error {
+		values = messages[0].Values
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, payload, values["payload"])
+}
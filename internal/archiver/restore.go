@@ -0,0 +1,94 @@
+package archiver
+
+import (
+	"context"
+	"fmt"
+
+	rdb "github.com/redis/go-redis/v9"
+	"github.com/streamweaverio/broker/internal/logging"
+	"github.com/streamweaverio/broker/internal/redis"
+	"go.uber.org/zap"
+)
+
+type Restorer interface {
+	// Append archived messages of a stream to a target stream
+	Restore(ctx context.Context, params *RestoreParameters) (*RestoreResult, error)
+}
+
+type RestoreParameters struct {
+	// Stream the messages were archived from
+	StreamName string
+	// Stream the messages are appended to, defaults to the archived stream
+	TargetStream string
+	// Time range of the messages in milliseconds, inclusive, zero leaves a bound open
+	StartTimestamp int64
+	EndTimestamp   int64
+	// Keep the original message IDs when the target stream allows it, otherwise new IDs are assigned
+	KeepIDs bool
+}
+
+type RestoreResult struct {
+	Restored   int
+	Reassigned int
+}
+
+type RestorerOptions struct {
+	Reader        ArchiveReader
+	StreamService redis.RedisStreamService
+}
+
+type RestorerImpl struct {
+	Reader        ArchiveReader
+	StreamService redis.RedisStreamService
+	Logger        logging.LoggerContract
+}
+
+// Create a new Restorer instance
+func NewRestorer(opts *RestorerOptions, logger logging.LoggerContract) Restorer {
+	return &RestorerImpl{
+		Reader:        opts.Reader,
+		StreamService: opts.StreamService,
+		Logger:        logger,
+	}
+}
+
+func (r *RestorerImpl) Restore(ctx context.Context, params *RestoreParameters) (*RestoreResult, error) {
+	if params.TargetStream == "" {
+		params.TargetStream = params.StreamName
+	}
+
+	// Fail before reading any block when the target stream does not exist
+	if _, err := r.StreamService.GetLastMessageID(params.TargetStream); err != nil {
+		return nil, err
+	}
+
+	result := &RestoreResult{}
+	err := r.Reader.ReadMessages(ctx, &ReadArchiveParameters{
+		StreamName:     params.StreamName,
+		StartTimestamp: params.StartTimestamp,
+		EndTimestamp:   params.EndTimestamp,
+	}, func(messages []rdb.XMessage) error {
+		restored, err := r.StreamService.RestoreMessages(params.TargetStream, messages, params.KeepIDs)
+		if restored != nil {
+			result.Restored += restored.Restored
+			result.Reassigned += restored.Reassigned
+		}
+		if err != nil {
+			return err
+		}
+
+		r.Logger.Debug("Restored messages", zap.String("stream", params.TargetStream), zap.Int("count", restored.Restored), zap.String("last_id", restored.LastId))
+		return nil
+	})
+	if err != nil {
+		return result, fmt.Errorf("failed to restore messages to stream %s: %w", params.TargetStream, err)
+	}
+
+	r.Logger.Info("Restored archived messages",
+		zap.String("stream", params.StreamName),
+		zap.String("target_stream", params.TargetStream),
+		zap.Int("restored", result.Restored),
+		zap.Int("reassigned", result.Reassigned))
+
+	return result, nil
+}
@@ -0,0 +1,56 @@
+package archiver
+
+import (
+	"context"
+	"testing"
+
+	rdb "github.com/redis/go-redis/v9"
+	"github.com/streamweaverio/broker/internal/redis"
+	"github.com/streamweaverio/broker/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRestorer_Restore(t *testing.T) {
+	logger := testutils.NewMockLogger()
+
+	t.Run("Restore archived messages into the target stream", func(t *testing.T) {
+		reader := NewArchiveReaderMock()
+		svc := redis.NewRedisStreamServiceMock()
+		restorer := NewRestorer(&RestorerOptions{Reader: reader, StreamService: svc}, logger)
+		messages := []rdb.XMessage{{ID: "1-0"}, {ID: "2-0"}}
+
+		svc.On("GetLastMessageID", "orders-restored").Return("0-0", nil)
+		reader.On("ReadMessages", mock.Anything, mock.MatchedBy(func(p *ReadArchiveParameters) bool {
+			return p.StreamName == "orders" && p.StartTimestamp == 1 && p.EndTimestamp == 2
+		})).Return(messages, nil)
+		svc.On("RestoreMessages", "orders-restored", messages, true).Return(&redis.StreamRestoreResult{Restored: 2, Reassigned: 1, LastId: "2-0"}, nil)
+
+		result, err := restorer.Restore(context.Background(), &RestoreParameters{
+			StreamName:     "orders",
+			TargetStream:   "orders-restored",
+			StartTimestamp: 1,
+			EndTimestamp:   2,
+			KeepIDs:        true,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, result.Restored)
+		assert.Equal(t, 1, result.Reassigned)
+		svc.AssertExpectations(t)
+		reader.AssertExpectations(t)
+	})
+
+	t.Run("Fail when the target stream does not exist", func(t *testing.T) {
+		reader := NewArchiveReaderMock()
+		svc := redis.NewRedisStreamServiceMock()
+		restorer := NewRestorer(&RestorerOptions{Reader: reader, StreamService: svc}, logger)
+
+		svc.On("GetLastMessageID", "orders").Return("", redis.StreamNotFoundError("orders"))
+
+		_, err := restorer.Restore(context.Background(), &RestoreParameters{StreamName: "orders"})
+
+		assert.IsType(t, &redis.RedisStreamNotFoundError{}, err)
+		reader.AssertNotCalled(t, "ReadMessages", mock.Anything, mock.Anything)
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"net"
 
 	"github.com/streamweaverio/broker/internal/logging"
+	brokerv1 "github.com/streamweaverio/broker/pkg/protos/broker/v1"
 	brokerpb "github.com/streamweaverio/go-protos/broker"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
@@ -17,6 +18,7 @@ type Broker struct {
 	logger logging.LoggerContract
 	server *grpc.Server
 	rpc    brokerpb.StreamWeaverBrokerServer
+	rpcV1  brokerv1.BrokerServiceServer
 }
 
 type Options struct {
@@ -24,6 +26,8 @@ type Options struct {
 	Port   int
 	Logger logging.LoggerContract
 	RPC    brokerpb.StreamWeaverBrokerServer
+	// RPCs defined by the broker's own protos
+	RPCV1  brokerv1.BrokerServiceServer
 	Server *grpc.Server
 }
 
@@ -34,6 +38,7 @@ func New(opts *Options) *Broker {
 		logger: opts.Logger,
 		server: opts.Server,
 		rpc:    opts.RPC,
+		rpcV1:  opts.RPCV1,
 	}
 }
 
@@ -43,6 +48,9 @@ func (b *Broker) Start() error {
 		return fmt.Errorf("failed to listen: %w", err)
 	}
 	brokerpb.RegisterStreamWeaverBrokerServer(b.server, b.rpc)
+	if b.rpcV1 != nil {
+		brokerv1.RegisterBrokerServiceServer(b.server, b.rpcV1)
+	}
 	b.logger.Info("Broker listening on port", zap.Int("port", b.config.Port))
 	return b.server.Serve(lis)
 }
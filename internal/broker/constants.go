@@ -0,0 +1,22 @@
+package broker
+
+import "time"
+
+// Number of messages sent per Subscribe response when the client does not set a batch size
+const DEFAULT_SUBSCRIBE_BATCH_SIZE = 100
+
+// How long a single XREAD blocks waiting for new messages before the subscription loop checks for cancellation
+const DEFAULT_SUBSCRIBE_BLOCK_TIMEOUT = 5 * time.Second
+
+// Number of messages returned by ReadGroup when the client does not set a count
+const DEFAULT_READ_GROUP_COUNT = 100
+
+// Upper bound for how long a ReadGroup call may block waiting for new messages
+const MAX_READ_GROUP_BLOCK_TIMEOUT = 30 * time.Second
+
+// Number of messages sent per ReadArchive response when the client does not set a batch size
+const DEFAULT_READ_ARCHIVE_BATCH_SIZE = 500
+
+// Number of times a subscription replays archived messages while retention trims the stream, the subscription is
+// aborted when the stream's first entry still moves after the last pass
+const MAX_ARCHIVE_REPLAY_PASSES = 3
@@ -2,25 +2,88 @@ package broker
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
 
+	rdb "github.com/redis/go-redis/v9"
+	"github.com/streamweaverio/broker/internal/archiver"
+	"github.com/streamweaverio/broker/internal/config"
+	"github.com/streamweaverio/broker/internal/leader"
 	"github.com/streamweaverio/broker/internal/logging"
 	"github.com/streamweaverio/broker/internal/redis"
+	"github.com/streamweaverio/broker/internal/schema"
+	brokerv1 "github.com/streamweaverio/broker/pkg/protos/broker/v1"
+	"github.com/streamweaverio/broker/pkg/utils"
 	brokerpb "github.com/streamweaverio/go-protos/broker"
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
 type RPCHandler struct {
-	Logger  logging.LoggerContract
-	Service redis.RedisStreamService
+	Logger               logging.LoggerContract
+	Service              redis.RedisStreamService
+	ConsumerGroupService redis.ConsumerGroupService
+	ArchiveReader        archiver.ArchiveReader
+	RetentionElector     leader.Elector
+	SchemaRegistry       redis.SchemaRegistryService
 	brokerpb.UnimplementedStreamWeaverBrokerServer
+	brokerv1.UnimplementedBrokerServiceServer
 }
 
-func NewRPCHandler(svc redis.RedisStreamService, logger logging.LoggerContract) *RPCHandler {
+type RPCHandlerOptions struct {
+	StreamService        redis.RedisStreamService
+	ConsumerGroupService redis.ConsumerGroupService
+	ArchiveReader        archiver.ArchiveReader
+	RetentionElector     leader.Elector
+	SchemaRegistry       redis.SchemaRegistryService
+}
+
+func NewRPCHandler(opts *RPCHandlerOptions, logger logging.LoggerContract) *RPCHandler {
 	return &RPCHandler{
-		Logger:  logger,
-		Service: svc,
+		Logger:               logger,
+		Service:              opts.StreamService,
+		ConsumerGroupService: opts.ConsumerGroupService,
+		ArchiveReader:        opts.ArchiveReader,
+		RetentionElector:     opts.RetentionElector,
+		SchemaRegistry:       opts.SchemaRegistry,
+	}
+}
+
+// Converts an error returned by a service into a gRPC status error
+func ToStatusError(err error) error {
+	switch err.(type) {
+	case *redis.RedisStreamNotFoundError, *redis.RedisConsumerGroupNotFoundError, *redis.RedisSchemaNotFoundError, *redis.RedisSubjectNotFoundError:
+		return status.Error(codes.NotFound, err.Error())
+	case *redis.RedisConsumerGroupExistsError:
+		return status.Error(codes.AlreadyExists, err.Error())
+	case *redis.RedisPublishBatchTooLargeError:
+		return status.Error(codes.InvalidArgument, err.Error())
+	case *redis.RedisSchemaIncompatibleError:
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case *redis.RedisSchemaValidationError:
+		return SchemaValidationStatusError(err.(*redis.RedisSchemaValidationError))
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+// Rejects a publish with the index of the failing message as a bad request field violation
+func SchemaValidationStatusError(err *redis.RedisSchemaValidationError) error {
+	st := status.New(codes.InvalidArgument, err.Error())
+	detailed, detailErr := st.WithDetails(&errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{{
+			Field:       fmt.Sprintf("messages[%d]", err.Index),
+			Description: err.Err.Error(),
+		}},
+	})
+	if detailErr != nil {
+		return st.Err()
 	}
+	return detailed.Err()
 }
 
 // Creates a new stream
@@ -39,6 +102,39 @@ func (h *RPCHandler) CreateStream(ctx context.Context, req *brokerpb.CreateStrea
 	return &brokerpb.CreateStreamResponse{Status: "OK"}, nil
 }
 
+// Creates a stream with its own retention limits and message format, unset fields fall back to the global options
+func (h *RPCHandler) CreateStreamWithOptions(ctx context.Context, req *brokerv1.CreateStreamWithOptionsRequest) (*brokerv1.CreateStreamWithOptionsResponse, error) {
+	if req.StreamName == "" {
+		return nil, status.Error(codes.InvalidArgument, "stream name is required")
+	}
+
+	if req.CleanupPolicy != "" && !slices.Contains(config.VALID_CLEANUP_POLICIES, req.CleanupPolicy) {
+		return nil, status.Errorf(codes.InvalidArgument, "cleanup policy must be one of %v", config.VALID_CLEANUP_POLICIES)
+	}
+
+	if req.RetentionTimeMs < 0 || req.MaxSize < 0 || req.MaxMessages < 0 {
+		return nil, status.Error(codes.InvalidArgument, "retention time, max size and max messages cannot be negative")
+	}
+
+	if req.MessageFormat != "" && !slices.Contains(config.VALID_MESSAGE_FORMATS, req.MessageFormat) {
+		return nil, status.Errorf(codes.InvalidArgument, "message format must be one of %v", config.VALID_MESSAGE_FORMATS)
+	}
+
+	err := h.Service.CreateStream(&redis.CreateStreamParameters{
+		Name:          req.StreamName,
+		MaxAge:        req.RetentionTimeMs,
+		CleanupPolicy: req.CleanupPolicy,
+		MaxSize:       req.MaxSize,
+		MaxMessages:   req.MaxMessages,
+		MessageFormat: req.MessageFormat,
+	})
+	if err != nil {
+		return nil, ToStatusError(err)
+	}
+
+	return &brokerv1.CreateStreamWithOptionsResponse{Status: "OK"}, nil
+}
+
 func (h *RPCHandler) Publish(ctx context.Context, req *brokerpb.PublishRequest) (*brokerpb.PublishResponse, error) {
 	// Prepare messages
 	messages := make([][]byte, len(req.Messages))
@@ -49,17 +145,575 @@ func (h *RPCHandler) Publish(ctx context.Context, req *brokerpb.PublishRequest)
 	// Publish messages
 	result, err := h.Service.PublishMessages(req.StreamName, messages)
 	if err != nil {
-		switch err.(type) {
-		case *redis.RedisStreamNotFoundError:
-			return nil, status.Error(codes.NotFound, err.Error())
+		return nil, ToStatusError(err)
+	}
+
+	res := &brokerpb.PublishResponse{
+		Status:     GetPublishStatus(result),
+		MessageIds: result.MessageIds,
+	}
+	if len(result.Errors) > 0 {
+		res.ErrorMessage = errors.Join(result.Errors...).Error()
+	}
+
+	return res, nil
+}
+
+// Publishes a batch of messages to a stream, atomic batches append every message or none of them and messages with a
+// producer ID or idempotency key are only appended once within the dedup window
+func (h *RPCHandler) PublishBatch(ctx context.Context, req *brokerv1.PublishBatchRequest) (*brokerv1.PublishBatchResponse, error) {
+	params := &redis.PublishBatchParameters{
+		Name:            req.StreamName,
+		Messages:        req.Messages,
+		Atomic:          req.Atomic,
+		ProducerId:      req.ProducerId,
+		FirstSequence:   req.FirstSequence,
+		IdempotencyKeys: req.IdempotencyKeys,
+	}
+
+	for _, headers := range req.Headers {
+		params.Headers = append(params.Headers, headers.GetValues())
+	}
+
+	if err := params.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	result, err := h.Service.PublishBatch(params)
+	if err != nil {
+		return nil, ToStatusError(err)
+	}
+
+	return &brokerv1.PublishBatchResponse{
+		Status:     GetPublishStatus(result),
+		MessageIds: result.MessageIds,
+		Published:  int32(result.Published),
+		Failed:     int32(result.Failed),
+		Errors:     PublishErrorsFromResult(result),
+		Duplicates: int32(result.Duplicates),
+	}, nil
+}
+
+// OK when every message was published, PARTIAL when only some were and ERROR when none were
+func GetPublishStatus(result *redis.StreamPublishResult) string {
+	switch {
+	case result.Failed == 0:
+		return "OK"
+	case result.Published > 0:
+		return "PARTIAL"
+	default:
+		return "ERROR"
+	}
+}
+
+func PublishErrorsFromResult(result *redis.StreamPublishResult) []*brokerv1.PublishError {
+	publishErrors := make([]*brokerv1.PublishError, 0, len(result.Errors))
+	for _, err := range result.Errors {
+		publishError := &brokerv1.PublishError{Index: -1, ErrorMessage: err.Error()}
+		var messageErr *redis.RedisStreamPublishError
+		if errors.As(err, &messageErr) {
+			publishError.Index = int32(messageErr.Index)
+			publishError.ErrorMessage = messageErr.Err.Error()
+		}
+		publishErrors = append(publishErrors, publishError)
+	}
+	return publishErrors
+}
+
+// Streams messages from a stream to the client as they are appended
+func (h *RPCHandler) Subscribe(req *brokerv1.SubscribeRequest, stream brokerv1.BrokerService_SubscribeServer) error {
+	ctx := stream.Context()
+
+	if req.StreamName == "" {
+		return status.Error(codes.InvalidArgument, "stream name is required")
+	}
+
+	lastId, err := h.GetSubscriptionStartID(req)
+	if err != nil {
+		return err
+	}
+
+	batchSize := req.BatchSize
+	if batchSize <= 0 {
+		batchSize = DEFAULT_SUBSCRIBE_BATCH_SIZE
+	}
+
+	h.Logger.Debug("Subscription opened", zap.String("stream", req.StreamName), zap.String("start_id", lastId))
+
+	// Messages older than the stream's first entry may have been moved to storage, they are replayed before reading the live stream
+	if h.ArchiveReader != nil && req.StartPosition != brokerv1.StartPosition_START_POSITION_LATEST {
+		lastId, err = h.ReplayArchive(ctx, req.StreamName, lastId, batchSize, func(entries []*brokerv1.StreamEntry) error {
+			return stream.Send(&brokerv1.SubscribeResponse{Messages: entries})
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			h.Logger.Debug("Subscription closed", zap.String("stream", req.StreamName))
+			return nil
 		default:
-			return nil, status.Error(codes.Internal, err.Error())
 		}
+
+		messages, err := h.Service.ReadMessages(ctx, req.StreamName, lastId, batchSize, DEFAULT_SUBSCRIBE_BLOCK_TIMEOUT)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return status.Error(codes.Internal, err.Error())
+		}
+
+		if len(messages) == 0 {
+			continue
+		}
+
+		err = stream.Send(&brokerv1.SubscribeResponse{
+			Messages: StreamEntriesFromMessages(messages),
+		})
+		if err != nil {
+			return err
+		}
+
+		lastId = messages[len(messages)-1].ID
 	}
+}
 
-	// All messages published successfully
-	return &brokerpb.PublishResponse{
-		Status:     "OK",
-		MessageIds: result.MessageIds,
+// Streams archived messages of a stream to the client in message ID order
+func (h *RPCHandler) ReadArchive(req *brokerv1.ReadArchiveRequest, stream brokerv1.BrokerService_ReadArchiveServer) error {
+	params := &archiver.ReadArchiveParameters{
+		StreamName:     req.StreamName,
+		StartTimestamp: req.StartTimestampMs,
+		EndTimestamp:   req.EndTimestampMs,
+		StartID:        req.StartMessageId,
+		EndID:          req.EndMessageId,
+	}
+
+	if err := params.Validate(); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	batchSize := int(req.BatchSize)
+	if batchSize <= 0 {
+		batchSize = DEFAULT_READ_ARCHIVE_BATCH_SIZE
+	}
+
+	err := h.ArchiveReader.ReadMessages(stream.Context(), params, func(messages []rdb.XMessage) error {
+		for start := 0; start < len(messages); start += batchSize {
+			end := min(start+batchSize, len(messages))
+			err := stream.Send(&brokerv1.ReadArchiveResponse{
+				Messages: StreamEntriesFromMessages(messages[start:end]),
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if stream.Context().Err() != nil {
+			return nil
+		}
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	return nil
+}
+
+// Sets when messages of a stream are moved to a dead letter stream
+func (h *RPCHandler) SetDeadLetterPolicy(ctx context.Context, req *brokerv1.SetDeadLetterPolicyRequest) (*brokerv1.SetDeadLetterPolicyResponse, error) {
+	if req.StreamName == "" {
+		return nil, status.Error(codes.InvalidArgument, "stream name is required")
+	}
+
+	if err := redis.ValidateDeadLetterPolicy(req.StreamName, req.MaxDeliveries, req.DeadLetterStream); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	err := h.Service.SetDeadLetterPolicy(req.StreamName, req.MaxDeliveries, req.DeadLetterStream)
+	if err != nil {
+		return nil, ToStatusError(err)
+	}
+
+	return &brokerv1.SetDeadLetterPolicyResponse{Status: "OK"}, nil
+}
+
+// Changes the retention and cleanup policy of an existing stream
+func (h *RPCHandler) UpdateStream(ctx context.Context, req *brokerv1.UpdateStreamRequest) (*brokerv1.UpdateStreamResponse, error) {
+	params := &redis.UpdateStreamParameters{
+		Name:               req.StreamName,
+		MaxAge:             req.RetentionTimeMs,
+		CleanupPolicy:      req.CleanupPolicy,
+		MaxSize:            req.MaxSize,
+		MaxMessages:        req.MaxMessages,
+		CompactionKeyField: req.CompactionKeyField,
+		CompactionLag:      req.CompactionLagMs,
+		TombstoneRetention: req.TombstoneRetentionMs,
+		Schedule:           req.Schedule,
+		MessageFormat:      req.MessageFormat,
+		SchemaSubject:      req.SchemaSubject,
+	}
+
+	if req.ArchiveWindows != nil {
+		params.ArchiveWindows = req.ArchiveWindows.Windows
+		params.SetArchiveWindows = true
+	}
+
+	if err := params.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	_, err := h.Service.UpdateStream(params)
+	if err != nil {
+		return nil, ToStatusError(err)
+	}
+
+	return &brokerv1.UpdateStreamResponse{Status: "OK"}, nil
+}
+
+// Sends archived messages newer than lastId which are no longer in the stream, returns the ID live reads continue after
+func (h *RPCHandler) ReplayArchive(ctx context.Context, streamName string, lastId string, batchSize int64, send func(entries []*brokerv1.StreamEntry) error) (string, error) {
+	previousFirstId := ""
+
+	for pass := 0; ; pass++ {
+		firstId, err := h.Service.GetFirstMessageID(streamName)
+		if err != nil {
+			return "", ToStatusError(err)
+		}
+
+		// Nothing was trimmed from the stream during the previous pass
+		if pass > 0 && firstId == previousFirstId {
+			return lastId, nil
+		}
+
+		// The stream still holds every message after lastId
+		if firstId != "" && utils.CompareStreamMessageIDs(lastId, firstId) >= 0 {
+			return lastId, nil
+		}
+
+		// Reading the live stream now could skip messages trimmed since the last pass
+		if pass == MAX_ARCHIVE_REPLAY_PASSES {
+			return "", status.Errorf(codes.Aborted, "stream %s kept being trimmed while replaying archived messages, retry the subscription", streamName)
+		}
+
+		startId := lastId
+		err = h.ArchiveReader.ReadMessages(ctx, &archiver.ReadArchiveParameters{
+			StreamName: streamName,
+			StartID:    startId,
+			EndID:      firstId,
+		}, func(messages []rdb.XMessage) error {
+			// Only messages between lastId and the stream's first entry are missing from the stream
+			missing := make([]rdb.XMessage, 0, len(messages))
+			for _, msg := range messages {
+				if utils.CompareStreamMessageIDs(msg.ID, startId) <= 0 {
+					continue
+				}
+				if firstId != "" && utils.CompareStreamMessageIDs(msg.ID, firstId) >= 0 {
+					continue
+				}
+				missing = append(missing, msg)
+			}
+
+			for start := 0; start < len(missing); start += int(batchSize) {
+				end := min(start+int(batchSize), len(missing))
+				if err := send(StreamEntriesFromMessages(missing[start:end])); err != nil {
+					return err
+				}
+				lastId = missing[end-1].ID
+			}
+			return nil
+		})
+		if err != nil {
+			return "", status.Error(codes.Internal, err.Error())
+		}
+
+		previousFirstId = firstId
+	}
+}
+
+// Resolves the ID a subscription starts reading after
+func (h *RPCHandler) GetSubscriptionStartID(req *brokerv1.SubscribeRequest) (string, error) {
+	// Resolve the last ID up front so messages appended between reads are never skipped, this also ensures the stream exists
+	lastGeneratedId, err := h.Service.GetLastMessageID(req.StreamName)
+	if err != nil {
+		return "", ToStatusError(err)
+	}
+
+	switch req.StartPosition {
+	case brokerv1.StartPosition_START_POSITION_EARLIEST:
+		return "0-0", nil
+	case brokerv1.StartPosition_START_POSITION_MESSAGE_ID:
+		if req.StartMessageId == "" {
+			return "", status.Error(codes.InvalidArgument, "start message ID is required when starting from a message ID")
+		}
+		if _, _, err := utils.ParseStreamMessageID(req.StartMessageId); err != nil {
+			return "", status.Error(codes.InvalidArgument, err.Error())
+		}
+		return req.StartMessageId, nil
+	default:
+		return lastGeneratedId, nil
+	}
+}
+
+// Creates a consumer group which receives messages appended to the stream after the group is created
+func (h *RPCHandler) CreateConsumerGroup(ctx context.Context, req *brokerpb.CreateConsumerGroupRequest) (*brokerpb.CreateConsumerGroupResponse, error) {
+	if req.StreamName == "" || req.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "stream name and group name are required")
+	}
+
+	err := h.ConsumerGroupService.CreateGroup(&redis.CreateGroupParameters{
+		StreamName: req.StreamName,
+		GroupName:  req.Name,
+		StartID:    "$",
+	})
+	if err != nil {
+		return nil, ToStatusError(err)
+	}
+
+	return &brokerpb.CreateConsumerGroupResponse{}, nil
+}
+
+// Deletes a consumer group and its pending entries
+func (h *RPCHandler) DeleteConsumerGroup(ctx context.Context, req *brokerv1.DeleteConsumerGroupRequest) (*brokerv1.DeleteConsumerGroupResponse, error) {
+	if req.StreamName == "" || req.GroupName == "" {
+		return nil, status.Error(codes.InvalidArgument, "stream name and group name are required")
+	}
+
+	err := h.ConsumerGroupService.DeleteGroup(req.StreamName, req.GroupName)
+	if err != nil {
+		return nil, ToStatusError(err)
+	}
+
+	return &brokerv1.DeleteConsumerGroupResponse{Status: "OK"}, nil
+}
+
+// Updates the settings of a consumer group
+func (h *RPCHandler) UpdateConsumerGroup(ctx context.Context, req *brokerv1.UpdateConsumerGroupRequest) (*brokerv1.UpdateConsumerGroupResponse, error) {
+	if req.StreamName == "" || req.GroupName == "" {
+		return nil, status.Error(codes.InvalidArgument, "stream name and group name are required")
+	}
+
+	if req.ReclaimIdleMs <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "reclaim idle time must be greater than 0")
+	}
+
+	err := h.ConsumerGroupService.SetReclaimIdleTime(req.StreamName, req.GroupName, time.Duration(req.ReclaimIdleMs)*time.Millisecond)
+	if err != nil {
+		return nil, ToStatusError(err)
+	}
+
+	return &brokerv1.UpdateConsumerGroupResponse{Status: "OK"}, nil
+}
+
+// Reads messages as a named consumer of a consumer group
+func (h *RPCHandler) ReadGroup(ctx context.Context, req *brokerv1.ReadGroupRequest) (*brokerv1.ReadGroupResponse, error) {
+	count := req.Count
+	if count <= 0 {
+		count = DEFAULT_READ_GROUP_COUNT
+	}
+
+	block := time.Duration(req.BlockMs) * time.Millisecond
+	if block > MAX_READ_GROUP_BLOCK_TIMEOUT {
+		block = MAX_READ_GROUP_BLOCK_TIMEOUT
+	}
+
+	params := &redis.ReadGroupParameters{
+		StreamName:   req.StreamName,
+		GroupName:    req.GroupName,
+		ConsumerName: req.ConsumerName,
+		Count:        count,
+		Block:        block,
+	}
+
+	if err := params.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	messages, err := h.ConsumerGroupService.ReadGroup(ctx, params)
+	if err != nil {
+		return nil, ToStatusError(err)
+	}
+
+	return &brokerv1.ReadGroupResponse{
+		Messages: StreamEntriesFromGroupMessages(messages),
+	}, nil
+}
+
+// Acknowledges messages read from a consumer group
+func (h *RPCHandler) Ack(ctx context.Context, req *brokerv1.AckRequest) (*brokerv1.AckResponse, error) {
+	if req.StreamName == "" || req.GroupName == "" {
+		return nil, status.Error(codes.InvalidArgument, "stream name and group name are required")
+	}
+
+	acknowledged, err := h.ConsumerGroupService.Ack(req.StreamName, req.GroupName, req.MessageIds)
+	if err != nil {
+		return nil, ToStatusError(err)
+	}
+
+	return &brokerv1.AckResponse{Acknowledged: acknowledged}, nil
+}
+
+// Negatively acknowledges messages read from a consumer group so they are redelivered
+func (h *RPCHandler) Nack(ctx context.Context, req *brokerv1.NackRequest) (*brokerv1.NackResponse, error) {
+	if req.StreamName == "" || req.GroupName == "" {
+		return nil, status.Error(codes.InvalidArgument, "stream name and group name are required")
+	}
+
+	requeued, err := h.ConsumerGroupService.Nack(req.StreamName, req.GroupName, req.MessageIds)
+	if err != nil {
+		return nil, ToStatusError(err)
+	}
+
+	return &brokerv1.NackResponse{Requeued: requeued}, nil
+}
+
+// Converts Redis stream messages into stream entries
+func StreamEntriesFromMessages(messages []rdb.XMessage) []*brokerv1.StreamEntry {
+	entries := make([]*brokerv1.StreamEntry, len(messages))
+	for i, msg := range messages {
+		entries[i] = NewStreamEntry(msg.ID, msg.Values)
+	}
+
+	return entries
+}
+
+// Converts the fields of a message into a stream entry, moving the payload and headers of raw messages out of its values
+func NewStreamEntry(id string, values map[string]interface{}) *brokerv1.StreamEntry {
+	content := redis.GetStreamMessageContent(values)
+	return &brokerv1.StreamEntry{
+		MessageId: id,
+		Values:    content.Fields,
+		Payload:   content.Payload,
+		Headers:   content.Headers,
+	}
+}
+
+// Converts consumer group messages into stream entries carrying their delivery count
+func StreamEntriesFromGroupMessages(messages []*redis.ConsumerGroupMessage) []*brokerv1.StreamEntry {
+	entries := make([]*brokerv1.StreamEntry, len(messages))
+	for i, msg := range messages {
+		entries[i] = NewStreamEntry(msg.ID, msg.Values)
+		entries[i].DeliveryCount = msg.DeliveryCount
+	}
+
+	return entries
+}
+
+// Gets the broker instance currently running retention policies
+func (h *RPCHandler) GetRetentionLeader(ctx context.Context, req *brokerv1.GetRetentionLeaderRequest) (*brokerv1.GetRetentionLeaderResponse, error) {
+	if h.RetentionElector == nil {
+		return nil, status.Error(codes.Unavailable, "retention leader election is not enabled")
+	}
+
+	leaderStatus, err := h.RetentionElector.Status()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &brokerv1.GetRetentionLeaderResponse{
+		LeaderId:     leaderStatus.LeaderId,
+		FencingToken: leaderStatus.Token,
+		InstanceId:   leaderStatus.InstanceId,
+		IsLeader:     leaderStatus.IsLeader,
 	}, nil
 }
+
+// Registers a schema as the next version of a subject
+func (h *RPCHandler) RegisterSchema(ctx context.Context, req *brokerv1.RegisterSchemaRequest) (*brokerv1.RegisterSchemaResponse, error) {
+	if h.SchemaRegistry == nil {
+		return nil, status.Error(codes.Unavailable, "schema registry is not enabled")
+	}
+
+	params := &redis.RegisterSchemaParameters{
+		Subject:     req.Subject,
+		Type:        req.Type,
+		Definition:  req.Definition,
+		MessageName: req.MessageName,
+	}
+
+	if err := params.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	value, err := h.SchemaRegistry.RegisterSchema(params)
+	if err != nil {
+		return nil, ToStatusError(err)
+	}
+
+	return &brokerv1.RegisterSchemaResponse{Schema: NewSchema(value)}, nil
+}
+
+// Gets a schema by ID, consumers use it to decode messages by their schema ID header
+func (h *RPCHandler) GetSchema(ctx context.Context, req *brokerv1.GetSchemaRequest) (*brokerv1.GetSchemaResponse, error) {
+	if h.SchemaRegistry == nil {
+		return nil, status.Error(codes.Unavailable, "schema registry is not enabled")
+	}
+
+	value, err := h.SchemaRegistry.GetSchema(req.Id)
+	if err != nil {
+		return nil, ToStatusError(err)
+	}
+
+	return &brokerv1.GetSchemaResponse{Schema: NewSchema(value)}, nil
+}
+
+// Gets a version of the schema of a subject and the subject's compatibility mode
+func (h *RPCHandler) GetSubjectSchema(ctx context.Context, req *brokerv1.GetSubjectSchemaRequest) (*brokerv1.GetSubjectSchemaResponse, error) {
+	if h.SchemaRegistry == nil {
+		return nil, status.Error(codes.Unavailable, "schema registry is not enabled")
+	}
+
+	if req.Subject == "" || req.Version < 0 {
+		return nil, status.Error(codes.InvalidArgument, "subject is required and version cannot be negative")
+	}
+
+	value, err := h.SchemaRegistry.GetSubjectSchema(req.Subject, req.Version)
+	if err != nil {
+		return nil, ToStatusError(err)
+	}
+
+	compatibility, err := h.SchemaRegistry.GetCompatibility(req.Subject)
+	if err != nil {
+		return nil, ToStatusError(err)
+	}
+
+	return &brokerv1.GetSubjectSchemaResponse{Schema: NewSchema(value), Compatibility: compatibility}, nil
+}
+
+// Sets the compatibility mode new versions of a subject are checked with
+func (h *RPCHandler) SetSubjectCompatibility(ctx context.Context, req *brokerv1.SetSubjectCompatibilityRequest) (*brokerv1.SetSubjectCompatibilityResponse, error) {
+	if h.SchemaRegistry == nil {
+		return nil, status.Error(codes.Unavailable, "schema registry is not enabled")
+	}
+
+	if req.Subject == "" {
+		return nil, status.Error(codes.InvalidArgument, "subject is required")
+	}
+
+	if !schema.IsValidCompatibility(req.Compatibility) {
+		return nil, status.Errorf(codes.InvalidArgument, "compatibility must be one of %v", schema.VALID_COMPATIBILITY_MODES)
+	}
+
+	if err := h.SchemaRegistry.SetCompatibility(req.Subject, req.Compatibility); err != nil {
+		return nil, ToStatusError(err)
+	}
+
+	return &brokerv1.SetSubjectCompatibilityResponse{Status: "OK"}, nil
+}
+
+func NewSchema(value *redis.Schema) *brokerv1.Schema {
+	return &brokerv1.Schema{
+		Id:          value.Id,
+		Subject:     value.Subject,
+		Version:     value.Version,
+		Type:        value.Type,
+		Definition:  value.Definition,
+		MessageName: value.MessageName,
+		CreatedAtMs: value.CreatedAt,
+	}
+}
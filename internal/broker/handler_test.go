@@ -2,21 +2,75 @@ package broker
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
 
+	rdb "github.com/redis/go-redis/v9"
+	"github.com/streamweaverio/broker/internal/archiver"
+	"github.com/streamweaverio/broker/internal/leader"
 	"github.com/streamweaverio/broker/internal/redis"
+	"github.com/streamweaverio/broker/internal/schema"
 	"github.com/streamweaverio/broker/internal/testutils"
+	brokerv1 "github.com/streamweaverio/broker/pkg/protos/broker/v1"
 	brokerpb "github.com/streamweaverio/go-protos/broker"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
+// Captures the responses sent on a Subscribe stream and cancels the stream after a number of sends
+type MockSubscribeServer struct {
+	grpc.ServerStream
+	Ctx       context.Context
+	Cancel    context.CancelFunc
+	Responses []*brokerv1.SubscribeResponse
+	MaxSends  int
+}
+
+func (m *MockSubscribeServer) Context() context.Context {
+	return m.Ctx
+}
+
+func (m *MockSubscribeServer) Send(res *brokerv1.SubscribeResponse) error {
+	m.Responses = append(m.Responses, res)
+	if len(m.Responses) >= m.MaxSends {
+		m.Cancel()
+	}
+	return nil
+}
+
+func NewMockSubscribeServer(maxSends int) *MockSubscribeServer {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &MockSubscribeServer{
+		Ctx:      ctx,
+		Cancel:   cancel,
+		MaxSends: maxSends,
+	}
+}
+
+// Captures the responses sent on a ReadArchive stream
+type MockReadArchiveServer struct {
+	grpc.ServerStream
+	Responses []*brokerv1.ReadArchiveResponse
+}
+
+func (m *MockReadArchiveServer) Context() context.Context {
+	return context.Background()
+}
+
+func (m *MockReadArchiveServer) Send(res *brokerv1.ReadArchiveResponse) error {
+	m.Responses = append(m.Responses, res)
+	return nil
+}
+
 func TestRPCHandler_CreateStream(t *testing.T) {
 	logger := testutils.NewMockLogger()
 	svc := redis.NewRedisStreamServiceMock()
-	handler := NewRPCHandler(svc, logger)
+	handler := NewRPCHandler(&RPCHandlerOptions{StreamService: svc}, logger)
 
 	ctx := context.Background()
 	req := &brokerpb.CreateStreamRequest{
@@ -40,10 +94,69 @@ func TestRPCHandler_CreateStream(t *testing.T) {
 	svc.AssertExpectations(t)
 }
 
+func TestRPCHandler_CreateStreamWithOptions(t *testing.T) {
+	logger := testutils.NewMockLogger()
+	ctx := context.Background()
+
+	t.Run("Create a key=value stream with a message limit", func(t *testing.T) {
+		svc := redis.NewRedisStreamServiceMock()
+		handler := NewRPCHandler(&RPCHandlerOptions{StreamService: svc}, logger)
+		req := &brokerv1.CreateStreamWithOptionsRequest{
+			StreamName:      "test-stream",
+			RetentionTimeMs: 3600000,
+			CleanupPolicy:   "delete,archive",
+			MaxSize:         1024,
+			MaxMessages:     500,
+			MessageFormat:   redis.MESSAGE_FORMAT_KEY_VALUE,
+		}
+
+		svc.On("CreateStream", mock.MatchedBy(func(p *redis.CreateStreamParameters) bool {
+			return p.Name == req.StreamName && p.MaxAge == req.RetentionTimeMs && p.CleanupPolicy == req.CleanupPolicy &&
+				p.MaxSize == req.MaxSize && p.MaxMessages == req.MaxMessages && p.MessageFormat == req.MessageFormat
+		})).Return(nil)
+
+		resp, err := handler.CreateStreamWithOptions(ctx, req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "OK", resp.Status)
+		svc.AssertExpectations(t)
+	})
+
+	t.Run("Reject a negative message limit", func(t *testing.T) {
+		svc := redis.NewRedisStreamServiceMock()
+		handler := NewRPCHandler(&RPCHandlerOptions{StreamService: svc}, logger)
+
+		_, err := handler.CreateStreamWithOptions(ctx, &brokerv1.CreateStreamWithOptionsRequest{StreamName: "test-stream", MaxMessages: -1})
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		svc.AssertNotCalled(t, "CreateStream", mock.Anything)
+	})
+
+	t.Run("Reject an unknown message format", func(t *testing.T) {
+		svc := redis.NewRedisStreamServiceMock()
+		handler := NewRPCHandler(&RPCHandlerOptions{StreamService: svc}, logger)
+
+		_, err := handler.CreateStreamWithOptions(ctx, &brokerv1.CreateStreamWithOptionsRequest{StreamName: "test-stream", MessageFormat: "json"})
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		svc.AssertNotCalled(t, "CreateStream", mock.Anything)
+	})
+
+	t.Run("Reject an unknown cleanup policy", func(t *testing.T) {
+		svc := redis.NewRedisStreamServiceMock()
+		handler := NewRPCHandler(&RPCHandlerOptions{StreamService: svc}, logger)
+
+		_, err := handler.CreateStreamWithOptions(ctx, &brokerv1.CreateStreamWithOptionsRequest{StreamName: "test-stream", CleanupPolicy: "truncate"})
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		svc.AssertNotCalled(t, "CreateStream", mock.Anything)
+	})
+}
+
 func TestRPCHandler_Publish(t *testing.T) {
 	logger := testutils.NewMockLogger()
 	svc := redis.NewRedisStreamServiceMock()
-	handler := NewRPCHandler(svc, logger)
+	handler := NewRPCHandler(&RPCHandlerOptions{StreamService: svc}, logger)
 	streamName := "test-stream"
 
 	ctx := context.Background()
@@ -99,4 +212,667 @@ func TestRPCHandler_Publish(t *testing.T) {
 		assert.Equal(t, err, status.Error(codes.NotFound, notFoundErr.Error()))
 		svc.AssertExpectations(t)
 	})
+
+	t.Run("Report failed messages", func(t *testing.T) {
+		req := &brokerpb.PublishRequest{
+			StreamName: streamName,
+			Messages: []*brokerpb.StreamMessage{
+				{MessageContent: []byte("event_name=login")},
+				{MessageContent: []byte("event_name=logout")},
+			},
+		}
+
+		result := &redis.StreamPublishResult{
+			MessageIds: []string{"1-0"},
+			Published:  1,
+			Failed:     1,
+			Errors:     []error{redis.StreamPublishError(1, errors.New("OOM command not allowed"))},
+		}
+		svc.On("PublishMessages", streamName, mock.Anything).Return(result, nil).Once()
+
+		resp, err := handler.Publish(ctx, req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "PARTIAL", resp.Status)
+		assert.Equal(t, []string{"1-0"}, resp.MessageIds)
+		assert.Equal(t, "Error publishing message 1: OOM command not allowed", resp.ErrorMessage)
+	})
+
+	t.Run("Reject messages that do not match the stream's schema", func(t *testing.T) {
+		req := &brokerpb.PublishRequest{
+			StreamName: streamName,
+			Messages: []*brokerpb.StreamMessage{
+				{MessageContent: []byte(`{"id": 1}`)},
+				{MessageContent: []byte(`{"id": "1"}`)},
+			},
+		}
+
+		svc.On("PublishMessages", streamName, mock.Anything).Return(nil, redis.SchemaValidationError(1, errors.New("expected integer"))).Once()
+
+		resp, err := handler.Publish(ctx, req)
+
+		assert.Nil(t, resp)
+		st := status.Convert(err)
+		assert.Equal(t, codes.InvalidArgument, st.Code())
+		assert.Len(t, st.Details(), 1)
+		violation := st.Details()[0].(*errdetails.BadRequest).FieldViolations[0]
+		assert.Equal(t, "messages[1]", violation.Field)
+		assert.Equal(t, "expected integer", violation.Description)
+	})
+}
+
+func TestRPCHandler_PublishBatch(t *testing.T) {
+	logger := testutils.NewMockLogger()
+	streamName := "test-stream"
+	messages := [][]byte{[]byte("event_name=login"), []byte("event_name=logout")}
+
+	t.Run("Publish messages with headers", func(t *testing.T) {
+		svc := redis.NewRedisStreamServiceMock()
+		handler := NewRPCHandler(&RPCHandlerOptions{StreamService: svc}, logger)
+		svc.On("PublishBatch", &redis.PublishBatchParameters{
+			Name:     streamName,
+			Messages: messages,
+			Headers:  []map[string]string{{"content-type": "text/plain"}, nil},
+		}).Return(&redis.StreamPublishResult{
+			MessageIds: []string{"1-0", "1-1"},
+			Published:  2,
+		}, nil)
+
+		resp, err := handler.PublishBatch(context.Background(), &brokerv1.PublishBatchRequest{
+			StreamName: streamName,
+			Messages:   messages,
+			Headers: []*brokerv1.MessageHeaders{
+				{Values: map[string]string{"content-type": "text/plain"}},
+				{},
+			},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "OK", resp.Status)
+		svc.AssertExpectations(t)
+	})
+
+	t.Run("Publish an idempotent atomic batch", func(t *testing.T) {
+		svc := redis.NewRedisStreamServiceMock()
+		handler := NewRPCHandler(&RPCHandlerOptions{StreamService: svc}, logger)
+		svc.On("PublishBatch", &redis.PublishBatchParameters{
+			Name:          streamName,
+			Messages:      messages,
+			Atomic:        true,
+			ProducerId:    "producer-1",
+			FirstSequence: 3,
+		}).Return(&redis.StreamPublishResult{
+			MessageIds: []string{"1-0", "1-1"},
+			Published:  1,
+			Duplicates: 1,
+		}, nil)
+
+		resp, err := handler.PublishBatch(context.Background(), &brokerv1.PublishBatchRequest{
+			StreamName:    streamName,
+			Messages:      messages,
+			Atomic:        true,
+			ProducerId:    "producer-1",
+			FirstSequence: 3,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "OK", resp.Status)
+		assert.Equal(t, int32(1), resp.Published)
+		assert.Equal(t, int32(1), resp.Duplicates)
+		assert.Equal(t, []string{"1-0", "1-1"}, resp.MessageIds)
+	})
+
+	t.Run("Return the error of every failed message", func(t *testing.T) {
+		svc := redis.NewRedisStreamServiceMock()
+		handler := NewRPCHandler(&RPCHandlerOptions{StreamService: svc}, logger)
+		svc.On("PublishBatch", mock.Anything).Return(&redis.StreamPublishResult{
+			MessageIds: []string{},
+			Failed:     2,
+			Errors:     []error{redis.StreamPublishError(1, errors.New("OOM command not allowed"))},
+		}, nil)
+
+		resp, err := handler.PublishBatch(context.Background(), &brokerv1.PublishBatchRequest{StreamName: streamName, Messages: messages, Atomic: true})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "ERROR", resp.Status)
+		assert.Equal(t, int32(2), resp.Failed)
+		assert.Len(t, resp.Errors, 1)
+		assert.Equal(t, int32(1), resp.Errors[0].Index)
+		assert.Equal(t, "OOM command not allowed", resp.Errors[0].ErrorMessage)
+	})
+
+	t.Run("Return invalid argument without one idempotency key per message", func(t *testing.T) {
+		svc := redis.NewRedisStreamServiceMock()
+		handler := NewRPCHandler(&RPCHandlerOptions{StreamService: svc}, logger)
+
+		_, err := handler.PublishBatch(context.Background(), &brokerv1.PublishBatchRequest{StreamName: streamName, Messages: messages, IdempotencyKeys: []string{"a"}})
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("Return invalid argument when an atomic batch is too large", func(t *testing.T) {
+		svc := redis.NewRedisStreamServiceMock()
+		handler := NewRPCHandler(&RPCHandlerOptions{StreamService: svc}, logger)
+		svc.On("PublishBatch", mock.Anything).Return(nil, redis.PublishBatchTooLargeError(2, 1))
+
+		_, err := handler.PublishBatch(context.Background(), &brokerv1.PublishBatchRequest{StreamName: streamName, Messages: messages, Atomic: true})
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestRPCHandler_Subscribe(t *testing.T) {
+	logger := testutils.NewMockLogger()
+	streamName := "test-stream"
+
+	t.Run("Streams messages starting from the latest message", func(t *testing.T) {
+		svc := redis.NewRedisStreamServiceMock()
+		handler := NewRPCHandler(&RPCHandlerOptions{StreamService: svc}, logger)
+		server := NewMockSubscribeServer(2)
+
+		svc.On("GetLastMessageID", streamName).Return("5-0", nil).Once()
+		svc.On("ReadMessages", mock.Anything, streamName, "5-0", int64(DEFAULT_SUBSCRIBE_BATCH_SIZE), DEFAULT_SUBSCRIBE_BLOCK_TIMEOUT).
+			Return([]rdb.XMessage{{ID: "6-0", Values: map[string]interface{}{"event_name": "login"}}}, nil).Once()
+		svc.On("ReadMessages", mock.Anything, streamName, "6-0", int64(DEFAULT_SUBSCRIBE_BATCH_SIZE), DEFAULT_SUBSCRIBE_BLOCK_TIMEOUT).
+			Return([]rdb.XMessage{}, nil).Once()
+		svc.On("ReadMessages", mock.Anything, streamName, "6-0", int64(DEFAULT_SUBSCRIBE_BATCH_SIZE), DEFAULT_SUBSCRIBE_BLOCK_TIMEOUT).
+			Return([]rdb.XMessage{{ID: "7-0", Values: map[string]interface{}{"event_name": "logout"}}}, nil).Once()
+
+		err := handler.Subscribe(&brokerv1.SubscribeRequest{StreamName: streamName}, server)
+
+		assert.NoError(t, err)
+		assert.Len(t, server.Responses, 2)
+		assert.Equal(t, "6-0", server.Responses[0].Messages[0].MessageId)
+		assert.Equal(t, "login", server.Responses[0].Messages[0].Values["event_name"])
+		assert.Equal(t, "7-0", server.Responses[1].Messages[0].MessageId)
+		svc.AssertExpectations(t)
+	})
+
+	t.Run("Streams messages starting from the earliest message", func(t *testing.T) {
+		svc := redis.NewRedisStreamServiceMock()
+		handler := NewRPCHandler(&RPCHandlerOptions{StreamService: svc}, logger)
+		server := NewMockSubscribeServer(1)
+
+		svc.On("GetLastMessageID", streamName).Return("5-0", nil).Once()
+		svc.On("ReadMessages", mock.Anything, streamName, "0-0", int64(10), DEFAULT_SUBSCRIBE_BLOCK_TIMEOUT).
+			Return([]rdb.XMessage{{ID: "1-0"}, {ID: "2-0"}}, nil).Once()
+
+		err := handler.Subscribe(&brokerv1.SubscribeRequest{
+			StreamName:    streamName,
+			StartPosition: brokerv1.StartPosition_START_POSITION_EARLIEST,
+			BatchSize:     10,
+		}, server)
+
+		assert.NoError(t, err)
+		assert.Len(t, server.Responses[0].Messages, 2)
+		svc.AssertExpectations(t)
+	})
+
+	t.Run("Replays archived messages before switching to the live stream", func(t *testing.T) {
+		svc := redis.NewRedisStreamServiceMock()
+		reader := archiver.NewArchiveReaderMock()
+		handler := NewRPCHandler(&RPCHandlerOptions{StreamService: svc, ArchiveReader: reader}, logger)
+		server := NewMockSubscribeServer(2)
+
+		svc.On("GetLastMessageID", streamName).Return("11-0", nil).Once()
+		svc.On("GetFirstMessageID", streamName).Return("10-0", nil).Twice()
+		// 10-0 is still in the stream, so it is only sent by the live read
+		reader.On("ReadMessages", mock.Anything, mock.MatchedBy(func(p *archiver.ReadArchiveParameters) bool {
+			return p.StartID == "0-0" && p.EndID == "10-0"
+		})).Return([]rdb.XMessage{{ID: "1-0"}, {ID: "2-0"}, {ID: "10-0"}}, nil).Once()
+		svc.On("ReadMessages", mock.Anything, streamName, "2-0", int64(DEFAULT_SUBSCRIBE_BATCH_SIZE), DEFAULT_SUBSCRIBE_BLOCK_TIMEOUT).
+			Return([]rdb.XMessage{{ID: "10-0"}, {ID: "11-0"}}, nil).Once()
+
+		err := handler.Subscribe(&brokerv1.SubscribeRequest{
+			StreamName:    streamName,
+			StartPosition: brokerv1.StartPosition_START_POSITION_EARLIEST,
+		}, server)
+
+		assert.NoError(t, err)
+		assert.Len(t, server.Responses, 2)
+		assert.Equal(t, []string{"1-0", "2-0"}, []string{server.Responses[0].Messages[0].MessageId, server.Responses[0].Messages[1].MessageId})
+		assert.Equal(t, []string{"10-0", "11-0"}, []string{server.Responses[1].Messages[0].MessageId, server.Responses[1].Messages[1].MessageId})
+		svc.AssertExpectations(t)
+		reader.AssertExpectations(t)
+	})
+
+	t.Run("Abort the subscription when the stream keeps being trimmed during the replay", func(t *testing.T) {
+		svc := redis.NewRedisStreamServiceMock()
+		reader := archiver.NewArchiveReaderMock()
+		handler := NewRPCHandler(&RPCHandlerOptions{StreamService: svc, ArchiveReader: reader}, logger)
+		server := NewMockSubscribeServer(1)
+
+		svc.On("GetLastMessageID", streamName).Return("50-0", nil).Once()
+		for _, firstId := range []string{"10-0", "20-0", "30-0", "40-0"} {
+			svc.On("GetFirstMessageID", streamName).Return(firstId, nil).Once()
+		}
+		reader.On("ReadMessages", mock.Anything, mock.Anything).Return([]rdb.XMessage{}, nil).Times(MAX_ARCHIVE_REPLAY_PASSES)
+
+		err := handler.Subscribe(&brokerv1.SubscribeRequest{
+			StreamName:    streamName,
+			StartPosition: brokerv1.StartPosition_START_POSITION_EARLIEST,
+		}, server)
+
+		assert.Equal(t, codes.Aborted, status.Code(err))
+		svc.AssertExpectations(t)
+		svc.AssertNotCalled(t, "ReadMessages", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Return invalid argument when start message ID is missing", func(t *testing.T) {
+		svc := redis.NewRedisStreamServiceMock()
+		handler := NewRPCHandler(&RPCHandlerOptions{StreamService: svc}, logger)
+		server := NewMockSubscribeServer(1)
+
+		svc.On("GetLastMessageID", streamName).Return("5-0", nil).Once()
+
+		err := handler.Subscribe(&brokerv1.SubscribeRequest{
+			StreamName:    streamName,
+			StartPosition: brokerv1.StartPosition_START_POSITION_MESSAGE_ID,
+		}, server)
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		svc.AssertExpectations(t)
+	})
+
+	t.Run("Return invalid argument when start message ID is malformed", func(t *testing.T) {
+		svc := redis.NewRedisStreamServiceMock()
+		handler := NewRPCHandler(&RPCHandlerOptions{StreamService: svc}, logger)
+		server := NewMockSubscribeServer(1)
+
+		svc.On("GetLastMessageID", streamName).Return("5-0", nil).Once()
+
+		err := handler.Subscribe(&brokerv1.SubscribeRequest{
+			StreamName:     streamName,
+			StartPosition:  brokerv1.StartPosition_START_POSITION_MESSAGE_ID,
+			StartMessageId: "yesterday",
+		}, server)
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		svc.AssertNotCalled(t, "ReadMessages", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Return not found when stream does not exist", func(t *testing.T) {
+		svc := redis.NewRedisStreamServiceMock()
+		handler := NewRPCHandler(&RPCHandlerOptions{StreamService: svc}, logger)
+		server := NewMockSubscribeServer(1)
+
+		svc.On("GetLastMessageID", streamName).Return("", &redis.RedisStreamNotFoundError{Name: streamName}).Once()
+
+		err := handler.Subscribe(&brokerv1.SubscribeRequest{StreamName: streamName}, server)
+
+		assert.Equal(t, codes.NotFound, status.Code(err))
+		assert.Empty(t, server.Responses)
+		svc.AssertExpectations(t)
+	})
+}
+
+func TestRPCHandler_ReadArchive(t *testing.T) {
+	logger := testutils.NewMockLogger()
+
+	t.Run("Stream archived messages in batches", func(t *testing.T) {
+		reader := archiver.NewArchiveReaderMock()
+		handler := NewRPCHandler(&RPCHandlerOptions{ArchiveReader: reader}, logger)
+		server := &MockReadArchiveServer{}
+
+		reader.On("ReadMessages", mock.Anything, mock.MatchedBy(func(p *archiver.ReadArchiveParameters) bool {
+			return p.StreamName == "test-stream" && p.StartID == "1-0" && p.EndID == "3-0"
+		})).Return([]rdb.XMessage{{ID: "1-0"}, {ID: "2-0"}, {ID: "3-0"}}, nil).Once()
+
+		err := handler.ReadArchive(&brokerv1.ReadArchiveRequest{
+			StreamName:     "test-stream",
+			StartMessageId: "1-0",
+			EndMessageId:   "3-0",
+			BatchSize:      2,
+		}, server)
+
+		assert.NoError(t, err)
+		assert.Len(t, server.Responses, 2)
+		assert.Len(t, server.Responses[0].Messages, 2)
+		assert.Equal(t, "3-0", server.Responses[1].Messages[0].MessageId)
+		reader.AssertExpectations(t)
+	})
+
+	t.Run("Return invalid argument for a time range combined with an ID range", func(t *testing.T) {
+		reader := archiver.NewArchiveReaderMock()
+		handler := NewRPCHandler(&RPCHandlerOptions{ArchiveReader: reader}, logger)
+
+		err := handler.ReadArchive(&brokerv1.ReadArchiveRequest{
+			StreamName:       "test-stream",
+			StartTimestampMs: 1,
+			StartMessageId:   "1-0",
+		}, &MockReadArchiveServer{})
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		reader.AssertNotCalled(t, "ReadMessages", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Return invalid argument for a malformed message ID", func(t *testing.T) {
+		reader := archiver.NewArchiveReaderMock()
+		handler := NewRPCHandler(&RPCHandlerOptions{ArchiveReader: reader}, logger)
+
+		for _, req := range []*brokerv1.ReadArchiveRequest{
+			{StreamName: "test-stream", StartMessageId: "abc"},
+			{StreamName: "test-stream", EndMessageId: "1-x"},
+		} {
+			err := handler.ReadArchive(req, &MockReadArchiveServer{})
+
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		}
+		reader.AssertNotCalled(t, "ReadMessages", mock.Anything, mock.Anything)
+	})
+}
+
+func TestRPCHandler_UpdateStream(t *testing.T) {
+	logger := testutils.NewMockLogger()
+	svc := redis.NewRedisStreamServiceMock()
+	handler := NewRPCHandler(&RPCHandlerOptions{StreamService: svc}, logger)
+
+	t.Run("Update the set fields of a stream", func(t *testing.T) {
+		cleanupPolicy := "delete,archive"
+		svc.On("UpdateStream", mock.MatchedBy(func(params *redis.UpdateStreamParameters) bool {
+			return params.Name == "test-stream" && *params.CleanupPolicy == cleanupPolicy && params.MaxAge == nil &&
+				params.SetArchiveWindows && len(params.ArchiveWindows) == 1
+		})).Return(&redis.StreamMetadata{Name: "test-stream"}, nil).Once()
+
+		resp, err := handler.UpdateStream(context.Background(), &brokerv1.UpdateStreamRequest{
+			StreamName:     "test-stream",
+			CleanupPolicy:  &cleanupPolicy,
+			ArchiveWindows: &brokerv1.ArchiveWindows{Windows: []string{"01:00-05:00"}},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "OK", resp.Status)
+		svc.AssertExpectations(t)
+	})
+
+	t.Run("Return invalid argument for an unknown cleanup policy", func(t *testing.T) {
+		cleanupPolicy := "shred"
+
+		_, err := handler.UpdateStream(context.Background(), &brokerv1.UpdateStreamRequest{
+			StreamName:    "test-stream",
+			CleanupPolicy: &cleanupPolicy,
+		})
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("Return not found when the stream does not exist", func(t *testing.T) {
+		maxAge := int64(60000)
+		svc.On("UpdateStream", mock.MatchedBy(func(params *redis.UpdateStreamParameters) bool {
+			return params.Name == "missing-stream"
+		})).Return(nil, redis.StreamNotFoundError("missing-stream")).Once()
+
+		_, err := handler.UpdateStream(context.Background(), &brokerv1.UpdateStreamRequest{
+			StreamName:      "missing-stream",
+			RetentionTimeMs: &maxAge,
+		})
+
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+}
+
+func TestRPCHandler_SetDeadLetterPolicy(t *testing.T) {
+	logger := testutils.NewMockLogger()
+	svc := redis.NewRedisStreamServiceMock()
+	handler := NewRPCHandler(&RPCHandlerOptions{StreamService: svc}, logger)
+
+	t.Run("Set the dead letter policy of a stream", func(t *testing.T) {
+		svc.On("SetDeadLetterPolicy", "test-stream", int64(5), "").Return(nil).Once()
+
+		resp, err := handler.SetDeadLetterPolicy(context.Background(), &brokerv1.SetDeadLetterPolicyRequest{
+			StreamName:    "test-stream",
+			MaxDeliveries: 5,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "OK", resp.Status)
+		svc.AssertExpectations(t)
+	})
+
+	t.Run("Return invalid argument when the stream dead letters into itself", func(t *testing.T) {
+		_, err := handler.SetDeadLetterPolicy(context.Background(), &brokerv1.SetDeadLetterPolicyRequest{
+			StreamName:       "test-stream",
+			MaxDeliveries:    5,
+			DeadLetterStream: "test-stream",
+		})
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("Return invalid argument when the dead letter stream is in another cluster slot", func(t *testing.T) {
+		_, err := handler.SetDeadLetterPolicy(context.Background(), &brokerv1.SetDeadLetterPolicyRequest{
+			StreamName:       "test-stream",
+			MaxDeliveries:    5,
+			DeadLetterStream: "test-stream.dlq",
+		})
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		svc.AssertNotCalled(t, "SetDeadLetterPolicy", "test-stream", int64(5), "test-stream.dlq")
+	})
+}
+
+func TestRPCHandler_CreateConsumerGroup(t *testing.T) {
+	logger := testutils.NewMockLogger()
+	groupSvc := redis.NewConsumerGroupServiceMock()
+	handler := NewRPCHandler(&RPCHandlerOptions{ConsumerGroupService: groupSvc}, logger)
+
+	t.Run("Create a consumer group starting at new messages", func(t *testing.T) {
+		groupSvc.On("CreateGroup", mock.MatchedBy(func(p *redis.CreateGroupParameters) bool {
+			return p.StreamName == "test-stream" && p.GroupName == "workers" && p.StartID == "$"
+		})).Return(nil).Once()
+
+		resp, err := handler.CreateConsumerGroup(context.Background(), &brokerpb.CreateConsumerGroupRequest{
+			Name:       "workers",
+			StreamName: "test-stream",
+		})
+
+		assert.NoError(t, err)
+		assert.NotNil(t, resp)
+		groupSvc.AssertExpectations(t)
+	})
+
+	t.Run("Return already exists when the group exists", func(t *testing.T) {
+		groupSvc.On("CreateGroup", mock.MatchedBy(func(p *redis.CreateGroupParameters) bool {
+			return p.StreamName == "test-stream" && p.GroupName == "workers" && p.StartID == "$"
+		})).Return(redis.ConsumerGroupExistsError("test-stream", "workers")).Once()
+
+		_, err := handler.CreateConsumerGroup(context.Background(), &brokerpb.CreateConsumerGroupRequest{
+			Name:       "workers",
+			StreamName: "test-stream",
+		})
+
+		assert.Equal(t, codes.AlreadyExists, status.Code(err))
+		groupSvc.AssertExpectations(t)
+	})
+}
+
+func TestRPCHandler_UpdateConsumerGroup(t *testing.T) {
+	logger := testutils.NewMockLogger()
+	groupSvc := redis.NewConsumerGroupServiceMock()
+	handler := NewRPCHandler(&RPCHandlerOptions{ConsumerGroupService: groupSvc}, logger)
+
+	t.Run("Update the reclaim idle time of a consumer group", func(t *testing.T) {
+		groupSvc.On("SetReclaimIdleTime", "test-stream", "workers", time.Minute).Return(nil).Once()
+
+		resp, err := handler.UpdateConsumerGroup(context.Background(), &brokerv1.UpdateConsumerGroupRequest{
+			StreamName:    "test-stream",
+			GroupName:     "workers",
+			ReclaimIdleMs: 60000,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "OK", resp.Status)
+		groupSvc.AssertExpectations(t)
+	})
+
+	t.Run("Return invalid argument without a reclaim idle time", func(t *testing.T) {
+		_, err := handler.UpdateConsumerGroup(context.Background(), &brokerv1.UpdateConsumerGroupRequest{
+			StreamName: "test-stream",
+			GroupName:  "workers",
+		})
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestRPCHandler_ReadGroup(t *testing.T) {
+	logger := testutils.NewMockLogger()
+
+	t.Run("Read messages as a named consumer", func(t *testing.T) {
+		groupSvc := redis.NewConsumerGroupServiceMock()
+		handler := NewRPCHandler(&RPCHandlerOptions{ConsumerGroupService: groupSvc}, logger)
+
+		groupSvc.On("ReadGroup", mock.Anything, mock.MatchedBy(func(p *redis.ReadGroupParameters) bool {
+			return p.ConsumerName == "worker-1" && p.Count == DEFAULT_READ_GROUP_COUNT && p.Block == MAX_READ_GROUP_BLOCK_TIMEOUT
+		})).Return([]*redis.ConsumerGroupMessage{
+			{XMessage: rdb.XMessage{ID: "1-0", Values: map[string]interface{}{"event_name": "login"}}, DeliveryCount: 2},
+		}, nil).Once()
+
+		resp, err := handler.ReadGroup(context.Background(), &brokerv1.ReadGroupRequest{
+			StreamName:   "test-stream",
+			GroupName:    "workers",
+			ConsumerName: "worker-1",
+			BlockMs:      60000,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "1-0", resp.Messages[0].MessageId)
+		assert.Equal(t, int64(2), resp.Messages[0].DeliveryCount)
+		groupSvc.AssertExpectations(t)
+	})
+
+	t.Run("Return invalid argument without a consumer name", func(t *testing.T) {
+		groupSvc := redis.NewConsumerGroupServiceMock()
+		handler := NewRPCHandler(&RPCHandlerOptions{ConsumerGroupService: groupSvc}, logger)
+
+		_, err := handler.ReadGroup(context.Background(), &brokerv1.ReadGroupRequest{
+			StreamName: "test-stream",
+			GroupName:  "workers",
+		})
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		groupSvc.AssertNotCalled(t, "ReadGroup", mock.Anything, mock.Anything)
+	})
+}
+
+func TestRPCHandler_AckAndNack(t *testing.T) {
+	logger := testutils.NewMockLogger()
+	groupSvc := redis.NewConsumerGroupServiceMock()
+	handler := NewRPCHandler(&RPCHandlerOptions{ConsumerGroupService: groupSvc}, logger)
+	ids := []string{"1-0", "2-0"}
+
+	groupSvc.On("Ack", "test-stream", "workers", ids).Return(int64(2), nil).Once()
+	groupSvc.On("Nack", "test-stream", "workers", ids).Return(int64(0), redis.ConsumerGroupNotFoundError("test-stream", "workers")).Once()
+
+	ackResp, err := handler.Ack(context.Background(), &brokerv1.AckRequest{StreamName: "test-stream", GroupName: "workers", MessageIds: ids})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), ackResp.Acknowledged)
+
+	_, err = handler.Nack(context.Background(), &brokerv1.NackRequest{StreamName: "test-stream", GroupName: "workers", MessageIds: ids})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	groupSvc.AssertExpectations(t)
+}
+
+func TestRPCHandler_GetRetentionLeader(t *testing.T) {
+	logger := testutils.NewMockLogger()
+	elector := leader.NewElectorMock()
+	handler := NewRPCHandler(&RPCHandlerOptions{RetentionElector: elector}, logger)
+
+	elector.On("Status").Return(&leader.Status{
+		Name:       "retention",
+		InstanceId: "broker-2",
+		LeaderId:   "broker-1",
+		Token:      4,
+	}, nil)
+
+	resp, err := handler.GetRetentionLeader(context.Background(), &brokerv1.GetRetentionLeaderRequest{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "broker-1", resp.LeaderId)
+	assert.Equal(t, int64(4), resp.FencingToken)
+	assert.Equal(t, "broker-2", resp.InstanceId)
+	assert.False(t, resp.IsLeader)
+}
+
+func TestRPCHandler_RegisterSchema(t *testing.T) {
+	logger := testutils.NewMockLogger()
+	definition := []byte(`{"type": "object", "properties": {"id": {"type": "integer"}}}`)
+
+	t.Run("Register a schema", func(t *testing.T) {
+		registry := redis.NewSchemaRegistryServiceMock()
+		handler := NewRPCHandler(&RPCHandlerOptions{SchemaRegistry: registry}, logger)
+		registry.On("RegisterSchema", &redis.RegisterSchemaParameters{Subject: "orders", Type: schema.TYPE_JSON, Definition: definition}).
+			Return(&redis.Schema{Id: 4, Subject: "orders", Version: 2, Type: schema.TYPE_JSON, Definition: definition}, nil)
+
+		resp, err := handler.RegisterSchema(context.Background(), &brokerv1.RegisterSchemaRequest{
+			Subject:    "orders",
+			Type:       schema.TYPE_JSON,
+			Definition: definition,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(4), resp.Schema.Id)
+		assert.Equal(t, int64(2), resp.Schema.Version)
+	})
+
+	t.Run("Reject an invalid schema", func(t *testing.T) {
+		registry := redis.NewSchemaRegistryServiceMock()
+		handler := NewRPCHandler(&RPCHandlerOptions{SchemaRegistry: registry}, logger)
+
+		_, err := handler.RegisterSchema(context.Background(), &brokerv1.RegisterSchemaRequest{
+			Subject:    "orders",
+			Type:       schema.TYPE_AVRO,
+			Definition: definition,
+		})
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		registry.AssertNotCalled(t, "RegisterSchema", mock.Anything)
+	})
+
+	t.Run("Reject a schema incompatible with the subject", func(t *testing.T) {
+		registry := redis.NewSchemaRegistryServiceMock()
+		handler := NewRPCHandler(&RPCHandlerOptions{SchemaRegistry: registry}, logger)
+		registry.On("RegisterSchema", mock.Anything).Return(nil, redis.SchemaIncompatibleError("orders", schema.COMPATIBILITY_BACKWARD, errors.New("property id is required but was optional")))
+
+		_, err := handler.RegisterSchema(context.Background(), &brokerv1.RegisterSchemaRequest{
+			Subject:    "orders",
+			Type:       schema.TYPE_JSON,
+			Definition: definition,
+		})
+
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+}
+
+func TestRPCHandler_GetSchema(t *testing.T) {
+	logger := testutils.NewMockLogger()
+	registry := redis.NewSchemaRegistryServiceMock()
+	handler := NewRPCHandler(&RPCHandlerOptions{SchemaRegistry: registry}, logger)
+
+	registry.On("GetSchema", int64(4)).Return(&redis.Schema{Id: 4, Subject: "orders", Version: 2, Type: schema.TYPE_JSON}, nil)
+	registry.On("GetSchema", int64(5)).Return(nil, redis.SchemaNotFoundError(5))
+
+	resp, err := handler.GetSchema(context.Background(), &brokerv1.GetSchemaRequest{Id: 4})
+	assert.NoError(t, err)
+	assert.Equal(t, "orders", resp.Schema.Subject)
+
+	_, err = handler.GetSchema(context.Background(), &brokerv1.GetSchemaRequest{Id: 5})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestRPCHandler_SetSubjectCompatibility(t *testing.T) {
+	logger := testutils.NewMockLogger()
+	registry := redis.NewSchemaRegistryServiceMock()
+	handler := NewRPCHandler(&RPCHandlerOptions{SchemaRegistry: registry}, logger)
+
+	registry.On("SetCompatibility", "orders", schema.COMPATIBILITY_FULL).Return(nil)
+
+	resp, err := handler.SetSubjectCompatibility(context.Background(), &brokerv1.SetSubjectCompatibilityRequest{Subject: "orders", Compatibility: schema.COMPATIBILITY_FULL})
+	assert.NoError(t, err)
+	assert.Equal(t, "OK", resp.Status)
+
+	_, err = handler.SetSubjectCompatibility(context.Background(), &brokerv1.SetSubjectCompatibilityRequest{Subject: "orders", Compatibility: "transitive"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	registry.AssertExpectations(t)
 }
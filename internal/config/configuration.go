@@ -4,10 +4,13 @@ type StreamWeaverConfig struct {
 	// Port for rpc server to listen on
 	Port int `yaml:"port"`
 	// Logging configuration
-	Logging   *LoggingConfig   `yaml:"logging"`
-	Redis     *RedisConfig     `yaml:"redis"`
-	Storage   *StorageConfig   `yaml:"storage"`
-	Retention *RetentionConfig `yaml:"retention"`
+	Logging        *LoggingConfig        `yaml:"logging"`
+	Redis          *RedisConfig          `yaml:"redis"`
+	Storage        *StorageConfig        `yaml:"storage"`
+	Retention      *RetentionConfig      `yaml:"retention"`
+	ConsumerGroups *ConsumerGroupsConfig `yaml:"consumer_groups"`
+	LeaderElection *LeaderElectionConfig `yaml:"leader_election"`
+	Publish        *PublishConfig        `yaml:"publish"`
 }
 
 type RedisConfig struct {
@@ -47,18 +50,81 @@ type AWSS3StorageProviderConfig struct {
 	Region string `yaml:"region"`
 	// name of the S3 bucket
 	Bucket string `yaml:"bucket"`
-	// access key ID for the AWS IAM user
+	// access key ID for the AWS IAM user; when omitted the default AWS credential chain is used
 	AccessKeyId string `yaml:"access_key"`
 	// secret access key for the AWS IAM user
 	SecretAccessKey string `yaml:"secret_access_key"`
+	// session token for temporary credentials, only used together with the access key
+	SessionToken string `yaml:"session_token"`
+	// custom endpoint for S3-compatible stores such as MinIO or Ceph RGW
+	Endpoint string `yaml:"endpoint"`
+	// address buckets as part of the path instead of the host name, required by most S3-compatible stores
+	ForcePathStyle bool `yaml:"force_path_style"`
+	// connect to the endpoint over plain HTTP
+	DisableSSL bool `yaml:"disable_ssl"`
 }
 
 // global retention policy for the broker, which applies to all streams by default unless overridden by the stream configuration
 type RetentionConfig struct {
 	// maximum age in milliseconds of a message before its moved to storage
 	MaxAge int64 `yaml:"max_age"`
-	// cleanup policy for streams; either "delete" or "archive" or "delete,archive"
+	// cleanup policy for streams; either "delete" or "archive" or "delete,archive" or "compact"
 	CleanupPolicy string `yaml:"cleanup_policy"`
+	// maximum memory in bytes a stream may use before its oldest messages are cleaned up, 0 disables the limit
+	MaxSize int64 `yaml:"max_size"`
+	// default maximum number of messages kept in a stream before the oldest are cleaned up, 0 disables the limit
+	MaxMessages int64 `yaml:"max_messages"`
+	// interval in seconds between runs of the retention policies
+	Interval int `yaml:"interval"`
+	// time in seconds to wait for running retention policies to finish on shutdown before they are cancelled
+	ShutdownTimeout int `yaml:"shutdown_timeout"`
+	// number of streams a retention policy is applied to concurrently
+	Workers int `yaml:"workers"`
+	// time in milliseconds a retention policy may take on a single stream, 0 disables the timeout
+	StreamTimeout int64 `yaml:"stream_timeout"`
+	// maximum random delay in milliseconds before a retention policy is applied to a stream
+	Jitter int64 `yaml:"jitter"`
+	// message field holding the key compacted streams keep the newest message of
+	CompactionKeyField string `yaml:"compaction_key_field"`
+	// time in milliseconds a message must be older than before compaction may remove it
+	CompactionLag int64 `yaml:"compaction_lag"`
+	// time in milliseconds a tombstone, a message holding nothing but its key, is kept before its key is removed entirely
+	TombstoneRetention int64 `yaml:"tombstone_retention"`
+	// default cron expression of when retention runs on a stream, empty runs it on every interval
+	Schedule string `yaml:"schedule"`
+	// default daily UTC windows (HH:MM-HH:MM) archival is allowed in, empty allows it at any time
+	ArchiveWindows []string `yaml:"archive_windows"`
+}
+
+// global consumer group settings, which apply to all consumer groups unless overridden by the group
+type ConsumerGroupsConfig struct {
+	// time in milliseconds a pending message can stay unacknowledged before it is reclaimed for redelivery
+	ReclaimIdleTime int64 `yaml:"reclaim_idle_time"`
+	// interval in seconds between runs of the pending message reclaimer
+	ReclaimInterval int `yaml:"reclaim_interval"`
+}
+
+// settings for publishing messages to streams
+type PublishConfig struct {
+	// maximum number of messages sent to Redis in one pipelined round trip, larger publish requests are split into several batches
+	MaxBatchSize int `yaml:"max_batch_size"`
+	// how long in milliseconds idempotency keys of published messages are remembered
+	DedupWindow int64 `yaml:"dedup_window"`
+	// maximum number of idempotency keys remembered per stream, the oldest keys are forgotten first
+	DedupMaxEntries int64 `yaml:"dedup_max_entries"`
+	// message format of streams created without one; "raw" keeps content untouched, "key_value" splits it into key=value
+	// fields like streams created before raw payloads existed
+	MessageFormat string `yaml:"message_format"`
+}
+
+// settings for electing the broker instance that runs retention policies
+type LeaderElectionConfig struct {
+	// unique ID of this broker instance, defaults to the hostname and process ID
+	InstanceId string `yaml:"instance_id"`
+	// time in milliseconds the leader keeps its lease without renewing it
+	LeaseTime int64 `yaml:"lease_time"`
+	// interval in milliseconds between attempts to acquire or renew the lease
+	RenewInterval int64 `yaml:"renew_interval"`
 }
 
 type LoggingConfig struct {
@@ -6,4 +6,7 @@ var VALID_LOG_FORMATS = []string{"text", "json"}
 
 var VALID_STORAGE_PROVIDERS = []string{"local", "s3"}
 
-var VALID_CLEANUP_POLICIES = []string{"delete", "archive", "delete,archive"}
+var VALID_CLEANUP_POLICIES = []string{"delete", "archive", "delete,archive", "compact"}
+
+// raw stores message content untouched in a single field, key_value splits it into space separated key=value fields
+var VALID_MESSAGE_FORMATS = []string{"raw", "key_value"}
@@ -0,0 +1,15 @@
+package config
+
+import "fmt"
+
+func (c *ConsumerGroupsConfig) Validate() error {
+	if c.ReclaimIdleTime <= 0 {
+		return fmt.Errorf("consumer_groups.reclaim_idle_time must be greater than 0")
+	}
+
+	if c.ReclaimInterval <= 0 {
+		return fmt.Errorf("consumer_groups.reclaim_interval must be greater than 0")
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package config
+
+import "testing"
+
+type ConsumerGroupsConfigTestCase struct {
+	Name        string               `json:"name"`
+	Value       ConsumerGroupsConfig `json:"config"`
+	ExpectError bool                 `json:"expectedError"`
+}
+
+func TestConsumerGroupsConfig_Validate(t *testing.T) {
+	testCases := []ConsumerGroupsConfigTestCase{
+		{
+			Name: "Valid consumer groups configuration",
+			Value: ConsumerGroupsConfig{
+				ReclaimIdleTime: 300000,
+				ReclaimInterval: 30,
+			},
+			ExpectError: false,
+		},
+		{
+			Name: "Invalid consumer groups configuration - missing reclaim idle time",
+			Value: ConsumerGroupsConfig{
+				ReclaimInterval: 30,
+			},
+			ExpectError: true,
+		},
+		{
+			Name: "Invalid consumer groups configuration - negative reclaim interval",
+			Value: ConsumerGroupsConfig{
+				ReclaimIdleTime: 300000,
+				ReclaimInterval: -1,
+			},
+			ExpectError: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			err := testCase.Value.Validate()
+			if (err != nil) != testCase.ExpectError {
+				t.Errorf("Validate() error = %v, expectedError %v", err, testCase.ExpectError)
+			}
+		})
+	}
+}
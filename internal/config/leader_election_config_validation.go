@@ -0,0 +1,19 @@
+package config
+
+import "fmt"
+
+func (c *LeaderElectionConfig) Validate() error {
+	if c.LeaseTime <= 0 {
+		return fmt.Errorf("leader_election.lease_time must be greater than 0")
+	}
+
+	if c.RenewInterval <= 0 {
+		return fmt.Errorf("leader_election.renew_interval must be greater than 0")
+	}
+
+	if c.RenewInterval >= c.LeaseTime {
+		return fmt.Errorf("leader_election.renew_interval must be less than leader_election.lease_time")
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package config
+
+import "testing"
+
+type LeaderElectionConfigTestCase struct {
+	Name        string               `json:"name"`
+	Value       LeaderElectionConfig `json:"config"`
+	ExpectError bool                 `json:"expectedError"`
+}
+
+func TestLeaderElectionConfig_Validate(t *testing.T) {
+	testCases := []LeaderElectionConfigTestCase{
+		{
+			Name: "Valid leader election configuration",
+			Value: LeaderElectionConfig{
+				LeaseTime:     15000,
+				RenewInterval: 5000,
+			},
+			ExpectError: false,
+		},
+		{
+			Name: "Invalid leader election configuration - missing lease time",
+			Value: LeaderElectionConfig{
+				RenewInterval: 5000,
+			},
+			ExpectError: true,
+		},
+		{
+			Name: "Invalid leader election configuration - renew interval longer than lease time",
+			Value: LeaderElectionConfig{
+				LeaseTime:     5000,
+				RenewInterval: 15000,
+			},
+			ExpectError: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			err := testCase.Value.Validate()
+			if (err != nil) != testCase.ExpectError {
+				t.Errorf("Validate() error = %v, expectedError %v", err, testCase.ExpectError)
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+)
+
+func (c *PublishConfig) Validate() error {
+	if c.MaxBatchSize <= 0 {
+		return fmt.Errorf("publish.max_batch_size must be greater than 0")
+	}
+
+	if c.DedupWindow <= 0 {
+		return fmt.Errorf("publish.dedup_window must be greater than 0")
+	}
+
+	if c.DedupMaxEntries <= 0 {
+		return fmt.Errorf("publish.dedup_max_entries must be greater than 0")
+	}
+
+	if !slices.Contains(VALID_MESSAGE_FORMATS, c.MessageFormat) {
+		return fmt.Errorf("publish.message_format must be one of %v", VALID_MESSAGE_FORMATS)
+	}
+
+	return nil
+}
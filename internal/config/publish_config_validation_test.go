@@ -0,0 +1,53 @@
+package config
+
+import "testing"
+
+type PublishConfigTestCase struct {
+	Name        string        `json:"name"`
+	Value       PublishConfig `json:"config"`
+	ExpectError bool          `json:"expectedError"`
+}
+
+func TestPublishConfig_Validate(t *testing.T) {
+	testCases := []PublishConfigTestCase{
+		{
+			Name: "Valid publish configuration",
+			Value: PublishConfig{
+				MaxBatchSize:    1000,
+				DedupWindow:     300000,
+				DedupMaxEntries: 100000,
+				MessageFormat:   "raw",
+			},
+			ExpectError: false,
+		},
+		{
+			Name:        "Invalid publish configuration - missing max batch size",
+			Value:       PublishConfig{DedupWindow: 300000, DedupMaxEntries: 100000},
+			ExpectError: true,
+		},
+		{
+			Name:        "Invalid publish configuration - missing dedup window",
+			Value:       PublishConfig{MaxBatchSize: 1000, DedupMaxEntries: 100000},
+			ExpectError: true,
+		},
+		{
+			Name:        "Invalid publish configuration - missing dedup max entries",
+			Value:       PublishConfig{MaxBatchSize: 1000, DedupWindow: 300000, MessageFormat: "raw"},
+			ExpectError: true,
+		},
+		{
+			Name:        "Invalid publish configuration - unknown message format",
+			Value:       PublishConfig{MaxBatchSize: 1000, DedupWindow: 300000, DedupMaxEntries: 100000, MessageFormat: "json"},
+			ExpectError: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			err := testCase.Value.Validate()
+			if (err != nil) != testCase.ExpectError {
+				t.Errorf("Validate() error = %v, expectedError %v", err, testCase.ExpectError)
+			}
+		})
+	}
+}
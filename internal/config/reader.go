@@ -19,8 +19,30 @@ func ReadConfiguration(filepath string) (*StreamWeaverConfig, error) {
 			LogFormat: "text",
 		},
 		Retention: &RetentionConfig{
-			MaxAge:        1 * 24 * 60 * 60 * 1000, // 1 day in milliseconds
-			CleanupPolicy: "delete,archive",
+			MaxAge:             1 * 24 * 60 * 60 * 1000, // 1 day in milliseconds
+			CleanupPolicy:      "delete,archive",
+			Interval:           30,
+			ShutdownTimeout:    30,
+			Workers:            4,
+			StreamTimeout:      5 * 60 * 1000, // 5 minutes in milliseconds
+			Jitter:             1000,          // 1 second in milliseconds
+			CompactionKeyField: "key",
+			CompactionLag:      60 * 60 * 1000,      // 1 hour in milliseconds
+			TombstoneRetention: 24 * 60 * 60 * 1000, // 1 day in milliseconds
+		},
+		ConsumerGroups: &ConsumerGroupsConfig{
+			ReclaimIdleTime: 5 * 60 * 1000, // 5 minutes in milliseconds
+			ReclaimInterval: 30,
+		},
+		LeaderElection: &LeaderElectionConfig{
+			LeaseTime:     15 * 1000, // 15 seconds in milliseconds
+			RenewInterval: 5 * 1000,  // 5 seconds in milliseconds
+		},
+		Publish: &PublishConfig{
+			MaxBatchSize:    1000,
+			DedupWindow:     300000,
+			DedupMaxEntries: 100000,
+			MessageFormat:   "raw",
 		},
 	}
 
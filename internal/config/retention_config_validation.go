@@ -3,6 +3,8 @@ package config
 import (
 	"fmt"
 	"slices"
+
+	"github.com/streamweaverio/broker/internal/schedule"
 )
 
 func (c *RetentionConfig) Validate() error {
@@ -18,5 +20,55 @@ func (c *RetentionConfig) Validate() error {
 		return fmt.Errorf("retention.max_age is required")
 	}
 
+	if c.MaxSize < 0 {
+		return fmt.Errorf("retention.max_size cannot be negative")
+	}
+
+	if c.MaxMessages < 0 {
+		return fmt.Errorf("retention.max_messages cannot be negative")
+	}
+
+	if c.Interval <= 0 {
+		return fmt.Errorf("retention.interval must be greater than 0")
+	}
+
+	if c.ShutdownTimeout < 0 {
+		return fmt.Errorf("retention.shutdown_timeout cannot be negative")
+	}
+
+	if c.Workers < 0 {
+		return fmt.Errorf("retention.workers cannot be negative")
+	}
+
+	if c.StreamTimeout < 0 {
+		return fmt.Errorf("retention.stream_timeout cannot be negative")
+	}
+
+	if c.Jitter < 0 {
+		return fmt.Errorf("retention.jitter cannot be negative")
+	}
+
+	if c.CleanupPolicy == "compact" && c.CompactionKeyField == "" {
+		return fmt.Errorf("retention.compaction_key_field is required for the compact cleanup policy")
+	}
+
+	if c.CompactionLag < 0 {
+		return fmt.Errorf("retention.compaction_lag cannot be negative")
+	}
+
+	if c.TombstoneRetention < 0 {
+		return fmt.Errorf("retention.tombstone_retention cannot be negative")
+	}
+
+	if c.Schedule != "" {
+		if _, err := schedule.ParseCron(c.Schedule); err != nil {
+			return fmt.Errorf("retention.schedule is invalid: %w", err)
+		}
+	}
+
+	if _, err := schedule.ParseWindows(c.ArchiveWindows); err != nil {
+		return fmt.Errorf("retention.archive_windows is invalid: %w", err)
+	}
+
 	return nil
 }
@@ -15,6 +15,7 @@ func TestRetentionConfig_Validate(t *testing.T) {
 			Value: RetentionConfig{
 				CleanupPolicy: "delete",
 				MaxAge:        3600000,
+				Interval:      30,
 			},
 			ExpectError: false,
 		},
@@ -41,6 +42,104 @@ func TestRetentionConfig_Validate(t *testing.T) {
 			},
 			ExpectError: true,
 		},
+		{
+			Name: "Valid retention configuration - max size",
+			Value: RetentionConfig{
+				CleanupPolicy: "delete",
+				MaxAge:        3600000,
+				MaxSize:       1000000000,
+				Interval:      30,
+			},
+			ExpectError: false,
+		},
+		{
+			Name: "Invalid retention configuration - negative max size",
+			Value: RetentionConfig{
+				CleanupPolicy: "delete",
+				MaxAge:        3600000,
+				MaxSize:       -1,
+			},
+			ExpectError: true,
+		},
+		{
+			Name: "Invalid retention configuration - negative max messages",
+			Value: RetentionConfig{
+				CleanupPolicy: "delete",
+				MaxAge:        3600000,
+				MaxMessages:   -1,
+				Interval:      30,
+			},
+			ExpectError: true,
+		},
+		{
+			Name: "Invalid retention configuration - negative workers",
+			Value: RetentionConfig{
+				CleanupPolicy: "delete",
+				MaxAge:        3600000,
+				Interval:      30,
+				Workers:       -1,
+			},
+			ExpectError: true,
+		},
+		{
+			Name: "Valid retention configuration - compact",
+			Value: RetentionConfig{
+				CleanupPolicy:      "compact",
+				MaxAge:             3600000,
+				Interval:           30,
+				CompactionKeyField: "key",
+				CompactionLag:      3600000,
+			},
+			ExpectError: false,
+		},
+		{
+			Name: "Invalid retention configuration - compact without key field",
+			Value: RetentionConfig{
+				CleanupPolicy: "compact",
+				MaxAge:        3600000,
+				Interval:      30,
+			},
+			ExpectError: true,
+		},
+		{
+			Name: "Valid retention configuration - schedule and archive windows",
+			Value: RetentionConfig{
+				CleanupPolicy:  "archive",
+				MaxAge:         3600000,
+				Interval:       30,
+				Schedule:       "*/5 * * * *",
+				ArchiveWindows: []string{"01:00-05:00"},
+			},
+			ExpectError: false,
+		},
+		{
+			Name: "Invalid retention configuration - invalid schedule",
+			Value: RetentionConfig{
+				CleanupPolicy: "archive",
+				MaxAge:        3600000,
+				Interval:      30,
+				Schedule:      "every 5 minutes",
+			},
+			ExpectError: true,
+		},
+		{
+			Name: "Invalid retention configuration - invalid archive window",
+			Value: RetentionConfig{
+				CleanupPolicy:  "archive",
+				MaxAge:         3600000,
+				Interval:       30,
+				ArchiveWindows: []string{"01:00"},
+			},
+			ExpectError: true,
+		},
+		{
+			Name: "Invalid retention configuration - missing interval",
+			Value: RetentionConfig{
+				CleanupPolicy: "delete",
+				MaxAge:        3600000,
+			},
+			ExpectError: true,
+		},
 	}
 
 	for _, testCase := range testCases {
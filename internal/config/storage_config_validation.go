@@ -2,16 +2,28 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"slices"
 )
 
 func (c *AWSS3StorageProviderConfig) Validate() error {
-	if c.AccessKeyId == "" {
-		return fmt.Errorf("storage.aws_s3.access_key_id is required")
+	// Keys are optional, without them the default AWS credential chain is used
+	if c.AccessKeyId == "" && c.SecretAccessKey != "" {
+		return fmt.Errorf("storage.aws_s3.access_key_id is required when storage.aws_s3.secret_access_key is set")
 	}
 
-	if c.SecretAccessKey == "" {
-		return fmt.Errorf("storage.aws_s3.secret_access_key is required")
+	if c.AccessKeyId != "" && c.SecretAccessKey == "" {
+		return fmt.Errorf("storage.aws_s3.secret_access_key is required when storage.aws_s3.access_key_id is set")
+	}
+
+	if c.SessionToken != "" && c.AccessKeyId == "" {
+		return fmt.Errorf("storage.aws_s3.session_token requires storage.aws_s3.access_key_id and storage.aws_s3.secret_access_key")
+	}
+
+	if c.Endpoint != "" {
+		if _, err := url.Parse(c.Endpoint); err != nil {
+			return fmt.Errorf("storage.aws_s3.endpoint is not a valid URL: %v", err)
+		}
 	}
 
 	if c.Region == "" {
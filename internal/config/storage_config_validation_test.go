@@ -77,6 +77,57 @@ func TestStorageConfig_Validate(t *testing.T) {
 			},
 			ExpectError: true,
 		},
+		{
+			Name: "Invalid s3 configuration - session token without keys",
+			Value: StorageConfig{
+				Provider: "s3",
+				S3: &AWSS3StorageProviderConfig{
+					SessionToken: "token",
+					Bucket:       "streamweaver-us-west-2",
+					Region:       "us-west-2",
+				},
+			},
+			ExpectError: true,
+		},
+		{
+			Name: "Invalid s3 configuration - malformed endpoint",
+			Value: StorageConfig{
+				Provider: "s3",
+				S3: &AWSS3StorageProviderConfig{
+					Endpoint: "http://minio:port",
+					Bucket:   "streamweaver",
+					Region:   "us-east-1",
+				},
+			},
+			ExpectError: true,
+		},
+		{
+			Name: "Valid s3 configuration using the default credential chain",
+			Value: StorageConfig{
+				Provider: "s3",
+				S3: &AWSS3StorageProviderConfig{
+					Bucket: "streamweaver-us-west-2",
+					Region: "us-west-2",
+				},
+			},
+			ExpectError: false,
+		},
+		{
+			Name: "Valid s3 compatible storage configuration",
+			Value: StorageConfig{
+				Provider: "s3",
+				S3: &AWSS3StorageProviderConfig{
+					AccessKeyId:     "minioadmin",
+					SecretAccessKey: "minioadmin",
+					Bucket:          "streamweaver",
+					Region:          "us-east-1",
+					Endpoint:        "http://minio:9000",
+					ForcePathStyle:  true,
+					DisableSSL:      true,
+				},
+			},
+			ExpectError: false,
+		},
 		{
 			Name: "Valid s3 storage configuration",
 			Value: StorageConfig{
@@ -20,6 +20,18 @@ func (c *StreamWeaverConfig) Validate() error {
 		return fmt.Errorf("retention is required")
 	}
 
+	if c.ConsumerGroups == nil {
+		return fmt.Errorf("consumer_groups is required")
+	}
+
+	if c.LeaderElection == nil {
+		return fmt.Errorf("leader_election is required")
+	}
+
+	if c.Publish == nil {
+		return fmt.Errorf("publish is required")
+	}
+
 	if err := c.Logging.Validate(); err != nil {
 		return err
 	}
@@ -36,5 +48,17 @@ func (c *StreamWeaverConfig) Validate() error {
 		return err
 	}
 
+	if err := c.ConsumerGroups.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.LeaderElection.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.Publish.Validate(); err != nil {
+		return err
+	}
+
 	return nil
 }
@@ -0,0 +1,214 @@
+package leader
+
+import (
+	"sync"
+	"time"
+
+	"github.com/streamweaverio/broker/internal/logging"
+	"github.com/streamweaverio/broker/internal/redis"
+	"go.uber.org/zap"
+)
+
+// Elector competes with other broker instances for a Redis lease, the holder of the lease is the leader
+type Elector interface {
+	Start() error
+	Stop()
+	// Whether this instance holds an unexpired lease
+	IsLeader() bool
+	// Fencing token of the lease held by this instance, 0 when it is not the leader
+	Token() int64
+	// Whether this instance is still the leader with the given fencing token, checked against the lease in Redis
+	ValidateToken(token int64) bool
+	// Current leader of the lease across all instances
+	Status() (*Status, error)
+}
+
+type Status struct {
+	// Name of the lease
+	Name string
+	// ID of this instance
+	InstanceId string
+	// ID of the instance holding the lease, empty when there is no leader
+	LeaderId string
+	// Fencing token of the current leader
+	Token    int64
+	IsLeader bool
+}
+
+type ElectorOptions struct {
+	// Name of the lease instances compete for
+	Name string
+	// ID of this instance, must be unique across broker instances
+	InstanceId string
+	// Time in milliseconds a lease is valid without being renewed
+	LeaseTime int64
+	// Time interval in milliseconds between attempts to acquire or renew the lease
+	RenewInterval int64
+	LeaseService  redis.LeaseService
+}
+
+type ElectorImpl struct {
+	Name          string
+	InstanceId    string
+	LeaseTime     time.Duration
+	RenewInterval time.Duration
+	LeaseService  redis.LeaseService
+	Logger        logging.LoggerContract
+	mu            sync.RWMutex
+	token         int64
+	expiresAt     time.Time
+	done          chan struct{}
+}
+
+func New(opts *ElectorOptions, logger logging.LoggerContract) Elector {
+	return &ElectorImpl{
+		Name:          opts.Name,
+		InstanceId:    opts.InstanceId,
+		LeaseTime:     time.Duration(opts.LeaseTime) * time.Millisecond,
+		RenewInterval: time.Duration(opts.RenewInterval) * time.Millisecond,
+		LeaseService:  opts.LeaseService,
+		Logger:        logger,
+		done:          make(chan struct{}),
+	}
+}
+
+func (e *ElectorImpl) Start() error {
+	e.Logger.Info("Starting leader election...", zap.String("lease", e.Name), zap.String("instance_id", e.InstanceId))
+
+	ticker := time.NewTicker(e.RenewInterval)
+	defer ticker.Stop()
+
+	e.Elect()
+
+	for {
+		select {
+		case <-e.done:
+			return nil
+		case <-ticker.C:
+			e.Elect()
+		}
+	}
+}
+
+// Stops competing for the lease and releases it so another instance can take over without waiting for it to expire
+func (e *ElectorImpl) Stop() {
+	e.Logger.Info("Stopping leader election...", zap.String("lease", e.Name))
+	close(e.done)
+
+	e.mu.Lock()
+	token := e.token
+	e.token = 0
+	e.mu.Unlock()
+
+	if token == 0 {
+		return
+	}
+
+	if err := e.LeaseService.Release(e.Name, e.InstanceId, token); err != nil {
+		e.Logger.Error("Failed to release lease", zap.String("lease", e.Name), zap.Error(err))
+	}
+}
+
+// Renews the lease when this instance holds it, otherwise tries to acquire it
+func (e *ElectorImpl) Elect() {
+	select {
+	case <-e.done:
+		return
+	default:
+	}
+
+	e.mu.RLock()
+	token := e.token
+	e.mu.RUnlock()
+
+	// The expiry is measured from before the request so the local view never outlives the lease in Redis
+	now := time.Now()
+
+	if token != 0 {
+		renewed, err := e.LeaseService.Renew(e.Name, e.InstanceId, token, e.LeaseTime)
+		if err != nil {
+			// Leadership is kept until the lease expires locally, a later renewal may still succeed
+			e.Logger.Error("Failed to renew lease", zap.String("lease", e.Name), zap.Error(err))
+			return
+		}
+
+		if !renewed {
+			e.Logger.Warn("Lost leadership", zap.String("lease", e.Name), zap.String("instance_id", e.InstanceId), zap.Int64("token", token))
+			e.setLease(0, time.Time{})
+			return
+		}
+
+		e.setLease(token, now.Add(e.LeaseTime))
+		return
+	}
+
+	token, err := e.LeaseService.Acquire(e.Name, e.InstanceId, e.LeaseTime)
+	if err != nil {
+		e.Logger.Error("Failed to acquire lease", zap.String("lease", e.Name), zap.Error(err))
+		return
+	}
+
+	if token == 0 {
+		return
+	}
+
+	e.setLease(token, now.Add(e.LeaseTime))
+	e.Logger.Info("Became leader", zap.String("lease", e.Name), zap.String("instance_id", e.InstanceId), zap.Int64("token", token))
+}
+
+func (e *ElectorImpl) setLease(token int64, expiresAt time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.token = token
+	e.expiresAt = expiresAt
+}
+
+func (e *ElectorImpl) IsLeader() bool {
+	return e.Token() != 0
+}
+
+func (e *ElectorImpl) Token() int64 {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if e.token == 0 || time.Now().After(e.expiresAt) {
+		return 0
+	}
+
+	return e.token
+}
+
+// The local view of the lease may lag behind another instance taking it over, so the lease itself is checked as well
+func (e *ElectorImpl) ValidateToken(token int64) bool {
+	if token == 0 || e.Token() != token {
+		return false
+	}
+
+	lease, err := e.LeaseService.GetLease(e.Name)
+	if err != nil {
+		e.Logger.Error("Failed to validate fencing token", zap.String("lease", e.Name), zap.Error(err))
+		return false
+	}
+
+	return lease != nil && lease.Holder == e.InstanceId && lease.Token == token
+}
+
+func (e *ElectorImpl) Status() (*Status, error) {
+	lease, err := e.LeaseService.GetLease(e.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &Status{
+		Name:       e.Name,
+		InstanceId: e.InstanceId,
+		IsLeader:   e.IsLeader(),
+	}
+
+	if lease != nil {
+		status.LeaderId = lease.Holder
+		status.Token = lease.Token
+	}
+
+	return status, nil
+}
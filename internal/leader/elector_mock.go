@@ -0,0 +1,43 @@
+package leader
+
+import "github.com/stretchr/testify/mock"
+
+type ElectorMock struct {
+	mock.Mock
+}
+
+func NewElectorMock() *ElectorMock {
+	return &ElectorMock{}
+}
+
+func (m *ElectorMock) Start() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func (m *ElectorMock) Stop() {
+	m.Called()
+}
+
+func (m *ElectorMock) IsLeader() bool {
+	args := m.Called()
+	return args.Bool(0)
+}
+
+func (m *ElectorMock) Token() int64 {
+	args := m.Called()
+	return args.Get(0).(int64)
+}
+
+func (m *ElectorMock) ValidateToken(token int64) bool {
+	args := m.Called(token)
+	return args.Bool(0)
+}
+
+func (m *ElectorMock) Status() (*Status, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Status), args.Error(1)
+}
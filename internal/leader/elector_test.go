@@ -0,0 +1,100 @@
+package leader
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/streamweaverio/broker/internal/redis"
+	"github.com/streamweaverio/broker/internal/testutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupElector() (*ElectorImpl, *redis.LeaseServiceMock) {
+	leaseService := redis.NewLeaseServiceMock()
+	elector := New(&ElectorOptions{
+		Name:          "retention",
+		InstanceId:    "broker-1",
+		LeaseTime:     15000,
+		RenewInterval: 5000,
+		LeaseService:  leaseService,
+	}, testutils.NewMockLogger()).(*ElectorImpl)
+
+	return elector, leaseService
+}
+
+func TestElector_Elect(t *testing.T) {
+	t.Run("Become leader when the lease is acquired", func(t *testing.T) {
+		elector, leaseService := setupElector()
+		leaseService.On("Acquire", "retention", "broker-1", 15*time.Second).Return(int64(4), nil)
+		leaseService.On("GetLease", "retention").Return(&redis.Lease{Holder: "broker-1", Token: 4}, nil)
+
+		elector.Elect()
+
+		assert.True(t, elector.IsLeader())
+		assert.True(t, elector.ValidateToken(4))
+		leaseService.AssertExpectations(t)
+	})
+
+	t.Run("Reject the fencing token once another instance took over the lease", func(t *testing.T) {
+		elector, leaseService := setupElector()
+		leaseService.On("Acquire", "retention", "broker-1", 15*time.Second).Return(int64(4), nil)
+		// The lease expired in Redis before this instance noticed
+		leaseService.On("GetLease", "retention").Return(&redis.Lease{Holder: "broker-2", Token: 5}, nil)
+
+		elector.Elect()
+
+		assert.True(t, elector.IsLeader())
+		assert.False(t, elector.ValidateToken(4))
+	})
+
+	t.Run("Stay follower when another instance holds the lease", func(t *testing.T) {
+		elector, leaseService := setupElector()
+		leaseService.On("Acquire", "retention", "broker-1", 15*time.Second).Return(int64(0), nil)
+
+		elector.Elect()
+
+		assert.False(t, elector.IsLeader())
+		assert.Equal(t, int64(0), elector.Token())
+	})
+
+	t.Run("Step down when the lease cannot be renewed", func(t *testing.T) {
+		elector, leaseService := setupElector()
+		leaseService.On("Acquire", "retention", "broker-1", 15*time.Second).Return(int64(4), nil).Once()
+		leaseService.On("Renew", "retention", "broker-1", int64(4), 15*time.Second).Return(false, nil).Once()
+
+		elector.Elect()
+		elector.Elect()
+
+		assert.False(t, elector.IsLeader())
+		assert.False(t, elector.ValidateToken(4))
+		leaseService.AssertExpectations(t)
+	})
+
+	t.Run("Keep leadership until the lease expires when renewal fails", func(t *testing.T) {
+		elector, leaseService := setupElector()
+		leaseService.On("Acquire", "retention", "broker-1", 15*time.Second).Return(int64(4), nil).Once()
+		leaseService.On("Renew", "retention", "broker-1", int64(4), 15*time.Second).Return(false, errors.New("connection reset")).Once()
+
+		elector.Elect()
+		elector.Elect()
+
+		assert.True(t, elector.IsLeader())
+
+		// Once the local view of the lease expires another instance may have taken over
+		elector.expiresAt = time.Now().Add(-time.Second)
+		assert.False(t, elector.IsLeader())
+	})
+}
+
+func TestElector_Stop(t *testing.T) {
+	elector, leaseService := setupElector()
+	leaseService.On("Acquire", "retention", "broker-1", 15*time.Second).Return(int64(4), nil)
+	leaseService.On("Release", "retention", "broker-1", int64(4)).Return(nil)
+
+	elector.Elect()
+	elector.Stop()
+
+	assert.False(t, elector.IsLeader())
+	leaseService.AssertExpectations(t)
+}
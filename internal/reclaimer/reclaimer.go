@@ -0,0 +1,94 @@
+package reclaimer
+
+import (
+	"time"
+
+	"github.com/streamweaverio/broker/internal/leader"
+	"github.com/streamweaverio/broker/internal/logging"
+	"github.com/streamweaverio/broker/internal/redis"
+	"go.uber.org/zap"
+)
+
+// Reclaimer periodically moves pending messages that consumers stopped processing back to their group for redelivery
+type Reclaimer interface {
+	Start() error
+	Stop()
+}
+
+type ReclaimerOptions struct {
+	// Time interval to run the reclaimer in seconds
+	Interval             int
+	ConsumerGroupService redis.ConsumerGroupService
+	// Elects the only instance reclaiming messages when several brokers share the Redis cluster, every instance reclaims when nil
+	Elector leader.Elector
+}
+
+type ReclaimerImpl struct {
+	// Time interval to run the reclaimer in seconds
+	Interval             int
+	ConsumerGroupService redis.ConsumerGroupService
+	Elector              leader.Elector
+	Logger               logging.LoggerContract
+	done                 chan struct{}
+}
+
+func New(opts *ReclaimerOptions, logger logging.LoggerContract) Reclaimer {
+	return &ReclaimerImpl{
+		Interval:             opts.Interval,
+		ConsumerGroupService: opts.ConsumerGroupService,
+		Elector:              opts.Elector,
+		Logger:               logger,
+		done:                 make(chan struct{}),
+	}
+}
+
+func (r *ReclaimerImpl) Start() error {
+	r.Logger.Info("Starting pending message reclaimer...", zap.Int("interval", r.Interval))
+
+	ticker := time.NewTicker(time.Duration(r.Interval) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.done:
+			return nil
+		case <-ticker.C:
+			r.Reclaim()
+		}
+	}
+}
+
+func (r *ReclaimerImpl) Stop() {
+	r.Logger.Info("Stopping pending message reclaimer...")
+	close(r.done)
+}
+
+// Reclaims idle pending messages of every registered consumer group
+func (r *ReclaimerImpl) Reclaim() {
+	// Instances reclaiming the same groups would keep handing messages from one consumer to another
+	if r.Elector != nil && !r.Elector.IsLeader() {
+		r.Logger.Debug("Skipping reclaim, this instance is not the reclaimer leader")
+		return
+	}
+
+	groups, err := r.ConsumerGroupService.ListGroups()
+	if err != nil {
+		r.Logger.Error("Failed to list consumer groups", zap.Error(err))
+		return
+	}
+
+	for _, group := range groups {
+		reclaimed, err := r.ConsumerGroupService.ReclaimIdleMessages(group.StreamName, group.GroupName, group.ReclaimIdleTime)
+		if err != nil {
+			r.Logger.Error("Failed to reclaim idle messages", zap.String("stream", group.StreamName), zap.String("group", group.GroupName), zap.Error(err))
+			continue
+		}
+
+		if reclaimed > 0 {
+			r.Logger.Info("Reclaimed idle messages for redelivery",
+				zap.String("stream", group.StreamName),
+				zap.String("group", group.GroupName),
+				zap.Int64("count", reclaimed))
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package reclaimer
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/streamweaverio/broker/internal/leader"
+	"github.com/streamweaverio/broker/internal/redis"
+	"github.com/streamweaverio/broker/internal/testutils"
+)
+
+func TestReclaimer_Reclaim(t *testing.T) {
+	svc := redis.NewConsumerGroupServiceMock()
+	r := New(&ReclaimerOptions{
+		Interval:             30,
+		ConsumerGroupService: svc,
+	}, testutils.NewMockLogger()).(*ReclaimerImpl)
+
+	svc.On("ListGroups").Return([]*redis.ConsumerGroupMetadata{
+		{StreamName: "orders", GroupName: "billing", ReclaimIdleTime: time.Minute},
+		{StreamName: "orders", GroupName: "shipping", ReclaimIdleTime: 5 * time.Minute},
+	}, nil)
+	// A failing group must not stop the remaining groups from being reclaimed
+	svc.On("ReclaimIdleMessages", "orders", "billing", time.Minute).Return(int64(0), errors.New("connection reset"))
+	svc.On("ReclaimIdleMessages", "orders", "shipping", 5*time.Minute).Return(int64(3), nil)
+
+	r.Reclaim()
+
+	svc.AssertExpectations(t)
+}
+
+func TestReclaimer_ReclaimLeadership(t *testing.T) {
+	t.Run("Skip reclaiming when this instance is not the leader", func(t *testing.T) {
+		svc := redis.NewConsumerGroupServiceMock()
+		elector := leader.NewElectorMock()
+		r := New(&ReclaimerOptions{
+			Interval:             30,
+			ConsumerGroupService: svc,
+			Elector:              elector,
+		}, testutils.NewMockLogger()).(*ReclaimerImpl)
+
+		elector.On("IsLeader").Return(false)
+
+		r.Reclaim()
+
+		svc.AssertNotCalled(t, "ListGroups")
+	})
+
+	t.Run("Reclaim idle messages when this instance is the leader", func(t *testing.T) {
+		svc := redis.NewConsumerGroupServiceMock()
+		elector := leader.NewElectorMock()
+		r := New(&ReclaimerOptions{
+			Interval:             30,
+			ConsumerGroupService: svc,
+			Elector:              elector,
+		}, testutils.NewMockLogger()).(*ReclaimerImpl)
+
+		elector.On("IsLeader").Return(true)
+		svc.On("ListGroups").Return([]*redis.ConsumerGroupMetadata{
+			{StreamName: "orders", GroupName: "billing", ReclaimIdleTime: time.Minute},
+		}, nil)
+		svc.On("ReclaimIdleMessages", "orders", "billing", time.Minute).Return(int64(1), nil)
+
+		r.Reclaim()
+
+		svc.AssertExpectations(t)
+	})
+}
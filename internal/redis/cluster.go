@@ -156,3 +156,28 @@ func NewClusterClient(opts *ClusterClientOptions, logger logging.LoggerContract)
 
 	return client, nil
 }
+
+// Gets the part of a key Redis Cluster hashes to pick its slot, the content of the first non-empty {...} or the whole key
+func GetHashTag(key string) string {
+	if start := strings.Index(key, "{"); start != -1 {
+		if end := strings.Index(key[start+1:], "}"); end > 0 {
+			return key[start+1 : start+1+end]
+		}
+	}
+
+	return key
+}
+
+// Gets a prefix for keys that have to be in the same cluster slot as the given key, keys with a hash tag are their own prefix
+func GetSlotPrefix(key string) string {
+	if GetHashTag(key) != key {
+		return key
+	}
+
+	return "{" + key + "}"
+}
+
+// Whether two keys are always in the same cluster slot and can be used together in one script
+func InSameSlot(a string, b string) bool {
+	return GetHashTag(a) == GetHashTag(b)
+}
@@ -0,0 +1,32 @@
+package redis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetHashTag(t *testing.T) {
+	tests := []struct {
+		Key      string
+		Expected string
+	}{
+		{Key: "orders", Expected: "orders"},
+		{Key: "{orders}.dlq", Expected: "orders"},
+		{Key: "tenant:{orders}:dedup_ids", Expected: "orders"},
+		// Empty hash tags hash the whole key
+		{Key: "{}orders", Expected: "{}orders"},
+		{Key: "orders{", Expected: "orders{"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Key, func(t *testing.T) {
+			assert.Equal(t, test.Expected, GetHashTag(test.Key))
+		})
+	}
+
+	assert.Equal(t, "{orders}", GetSlotPrefix("orders"))
+	assert.Equal(t, "{tenant-1}.orders", GetSlotPrefix("{tenant-1}.orders"))
+	assert.True(t, InSameSlot("orders", "{orders}.dlq"))
+	assert.False(t, InSameSlot("orders", "orders.dlq"))
+}
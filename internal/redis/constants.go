@@ -1,8 +1,108 @@
 package redis
 
+import "time"
+
 // The curly braces are used to force keys with simiar tags to go the same cluster slot, which is useful for sharding.
 const STREAM_META_DATA_PREFIX = "{streamweaver_stream_metadata}:"
-const STREAM_CLEANUP_BUCKET_DELETE = "stream_cleanup_bucket:delete"
-const STREAM_CLEANUP_BUCKET_ARCHIVE = "stream_cleanup_bucket:archive"
-const STREAM_CLEANUP_BUCKET_DELETE_ARCHIVE = "stream_cleanup_bucket:delete_archive"
+
+// Cleanup buckets share the hash tag of the stream metadata so a stream's metadata and bucket can be changed in one script
+const STREAM_CLEANUP_BUCKET_DELETE = "{streamweaver_stream_metadata}:stream_cleanup_bucket:delete"
+const STREAM_CLEANUP_BUCKET_ARCHIVE = "{streamweaver_stream_metadata}:stream_cleanup_bucket:archive"
+const STREAM_CLEANUP_BUCKET_DELETE_ARCHIVE = "{streamweaver_stream_metadata}:stream_cleanup_bucket:delete_archive"
+const STREAM_CLEANUP_BUCKET_COMPACT = "{streamweaver_stream_metadata}:stream_cleanup_bucket:compact"
+
+var STREAM_CLEANUP_BUCKETS = []string{
+	STREAM_CLEANUP_BUCKET_DELETE,
+	STREAM_CLEANUP_BUCKET_ARCHIVE,
+	STREAM_CLEANUP_BUCKET_DELETE_ARCHIVE,
+	STREAM_CLEANUP_BUCKET_COMPACT,
+}
+
+// Cleanup buckets from before they shared the hash tag of the stream metadata. They are in other cluster slots, so a
+// stream is removed from them separately when its cleanup policy changes
+var LEGACY_STREAM_CLEANUP_BUCKETS = []string{
+	"stream_cleanup_bucket:delete",
+	"stream_cleanup_bucket:archive",
+	"stream_cleanup_bucket:delete_archive",
+}
+
 const STREAM_REGISTRY_KEY = "stream_registry"
+
+// Field of the stream metadata hash holding the ID of the last message written to storage
+const STREAM_ARCHIVE_WATERMARK_FIELD = "last_archived_id"
+
+// Field of the stream metadata hash holding the newest fencing token the watermark was written with
+const STREAM_FENCING_TOKEN_FIELD = "fencing_token"
+
+// Suffix of the key in the slot of a stream holding the newest fencing token the stream was trimmed with. Lease keys
+// are in their own slot, so fenced writes compare tokens against these instead of reading the lease
+const STREAM_FENCING_TOKEN_SUFFIX = ":fencing_token"
+const CONSUMER_GROUP_META_DATA_PREFIX = "{streamweaver_consumer_group_metadata}:"
+const CONSUMER_GROUP_REGISTRY_KEY = "consumer_group_registry"
+
+// Leases and their fencing token counters share a hash tag so the lease scripts can access both in one cluster slot
+const LEASE_KEY_PREFIX = "{streamweaver_lease}:"
+const LEASE_TOKEN_SUFFIX = ":token"
+
+// Schemas, subjects and the schema ID counter share a hash tag so a new schema version and its ID are registered in one
+// script
+const SCHEMA_KEY_PREFIX = "{streamweaver_schema}:"
+const SCHEMA_ID_COUNTER_KEY = "{streamweaver_schema}:id_counter"
+
+// Compatibility mode of subjects that never had one set
+const DEFAULT_SCHEMA_COMPATIBILITY = "backward"
+
+// Number of times registering a schema is retried when another version of the subject was registered concurrently
+const SCHEMA_REGISTER_ATTEMPTS = 3
+
+// Header messages carry the ID of the schema they were validated against in
+const MESSAGE_SCHEMA_ID_HEADER = "schema_id"
+
+// Consumer that holds negatively acknowledged and reclaimed messages until another consumer in the group claims them
+const CONSUMER_GROUP_REDELIVERY_CONSUMER = "__streamweaver_redelivery__"
+
+// Consumer that holds messages while they are moved to a dead letter stream, prevents two readers from dead lettering the same message
+const CONSUMER_GROUP_DEAD_LETTER_CONSUMER = "__streamweaver_dead_letter__"
+
+// Minimum idle time before a message held for redelivery can be claimed, prevents two readers from claiming the same message
+const CONSUMER_GROUP_REDELIVERY_MIN_IDLE = time.Millisecond
+
+// Number of pending entries inspected per XAUTOCLAIM call when reclaiming idle messages
+const CONSUMER_GROUP_RECLAIM_BATCH_SIZE = 100
+
+// Number of message IDs deleted per script call, Lua cannot unpack much more than 8000 arguments at once
+const DELETE_MESSAGES_BATCH_SIZE = 1000
+
+// Number of messages pipelined to Redis in one round trip when publishing, unless configured otherwise
+const DEFAULT_MAX_PUBLISH_BATCH_SIZE = 1000
+
+// How long in milliseconds idempotency keys of published messages are remembered, unless configured otherwise
+const DEFAULT_DEDUP_WINDOW = 5 * 60 * 1000
+
+// Number of idempotency keys remembered per stream, unless configured otherwise
+const DEFAULT_DEDUP_MAX_ENTRIES = 100000
+
+// Suffixes of the per stream keys mapping idempotency keys to message IDs and ordering them by publish time
+const STREAM_DEDUP_IDS_SUFFIX = ":dedup_ids"
+const STREAM_DEDUP_WINDOW_SUFFIX = ":dedup_window"
+
+// Message formats of streams, raw stores the published content untouched in the payload field while key_value splits it
+// into space separated key=value fields
+const MESSAGE_FORMAT_RAW = "raw"
+const MESSAGE_FORMAT_KEY_VALUE = "key_value"
+
+// Field raw messages keep their content in
+const MESSAGE_PAYLOAD_FIELD = "payload"
+
+// Prefix of the fields message headers are stored in
+const MESSAGE_HEADER_PREFIX = "header:"
+
+// Suffix appended to the hash tagged stream name to name its dead letter stream when none is given
+const STREAM_DEAD_LETTER_SUFFIX = ".dlq"
+
+// Fields added to a message when it is moved to a dead letter stream
+const DEAD_LETTER_FIELD_ORIGINAL_ID = "dlq_original_id"
+const DEAD_LETTER_FIELD_ORIGINAL_STREAM = "dlq_original_stream"
+const DEAD_LETTER_FIELD_GROUP = "dlq_group"
+const DEAD_LETTER_FIELD_FAILURE_REASON = "dlq_failure_reason"
+const DEAD_LETTER_FIELD_DELIVERY_COUNT = "dlq_delivery_count"
@@ -0,0 +1,650 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/streamweaverio/broker/internal/logging"
+	"github.com/streamweaverio/broker/pkg/utils"
+	"go.uber.org/zap"
+)
+
+// Moves message ARGV[2] of the stream at KEYS[1] to the dead letter stream at KEYS[2] and acknowledges it for group ARGV[1],
+// so a crash can never leave the message both dead lettered and pending. Only messages still pending for the dead letter
+// consumer ARGV[3] are moved, ARGV[4] onwards are the dead letter fields added to the message. Returns 0 when the message
+// was no longer pending, such as when another reader already moved it
+const deadLetterScriptSource = `
+local pending = redis.call("XPENDING", KEYS[1], ARGV[1], ARGV[2], ARGV[2], 1, ARGV[3])
+if #pending == 0 then
+	return 0
+end
+
+local added = {}
+for i = 4, #ARGV, 2 do
+	added[ARGV[i]] = true
+end
+
+-- Messages already removed from the stream by retention only need to be acknowledged
+local entries = redis.call("XRANGE", KEYS[1], ARGV[2], ARGV[2])
+if #entries > 0 then
+	local fields = {}
+	local values = entries[1][2]
+	for i = 1, #values, 2 do
+		if not added[values[i]] then
+			fields[#fields + 1] = values[i]
+			fields[#fields + 1] = values[i + 1]
+		end
+	end
+	for i = 4, #ARGV do
+		fields[#fields + 1] = ARGV[i]
+	end
+	redis.call("XADD", KEYS[2], "*", unpack(fields))
+end
+
+redis.call("XACK", KEYS[1], ARGV[1], ARGV[2])
+return 1
+`
+
+var deadLetterScript = redis.NewScript(deadLetterScriptSource)
+
+type CreateGroupParameters struct {
+	StreamName string
+	GroupName  string
+	// ID after which the group starts delivering messages, "$" only delivers new messages
+	StartID string
+	// How long a pending message can stay unacknowledged before it is reclaimed, defaults to the global setting
+	ReclaimIdleTime time.Duration
+}
+
+type ConsumerGroupMetadata struct {
+	StreamName      string
+	GroupName       string
+	ReclaimIdleTime time.Duration
+	CreatedAt       int64
+}
+
+// A message read from a consumer group
+type ConsumerGroupMessage struct {
+	redis.XMessage
+	// Number of times the message has been delivered to consumers of the group, including this delivery
+	DeliveryCount int64
+}
+
+type ReadGroupParameters struct {
+	StreamName   string
+	GroupName    string
+	ConsumerName string
+	// Maximum number of messages to return
+	Count int64
+	// How long to wait for new messages, zero returns immediately
+	Block time.Duration
+}
+
+type ConsumerGroupService interface {
+	// Create a consumer group on a stream
+	CreateGroup(params *CreateGroupParameters) error
+	// Delete a consumer group and its pending entries from a stream
+	DeleteGroup(streamName string, groupName string) error
+	// Set how long a pending message of a group can stay unacknowledged before it is reclaimed
+	SetReclaimIdleTime(streamName string, groupName string, idle time.Duration) error
+	// List all consumer groups created through the broker
+	ListGroups() ([]*ConsumerGroupMetadata, error)
+	// Read messages as a named consumer of a group, messages held for redelivery are delivered before new ones
+	ReadGroup(ctx context.Context, params *ReadGroupParameters) ([]*ConsumerGroupMessage, error)
+	// Move pending messages idle for longer than minIdle back to the group for redelivery
+	ReclaimIdleMessages(streamName string, groupName string, minIdle time.Duration) (int64, error)
+	// Acknowledge messages, removing them from the group's pending entries
+	Ack(streamName string, groupName string, ids []string) (int64, error)
+	// Negatively acknowledge messages so they are redelivered to the next consumer that reads from the group
+	Nack(streamName string, groupName string, ids []string) (int64, error)
+}
+
+type ConsumerGroupServiceImpl struct {
+	Ctx                    context.Context
+	Client                 RedisStreamClient
+	StreamMetadataService  StreamMetadataService
+	Logger                 logging.LoggerContract
+	DefaultReclaimIdleTime time.Duration
+}
+
+type ConsumerGroupServiceOptions struct {
+	Ctx             context.Context
+	RedisClient     RedisStreamClient
+	MetadataService StreamMetadataService
+	// Reclaim idle time for groups created without one
+	DefaultReclaimIdleTime time.Duration
+}
+
+func NewConsumerGroupService(opts *ConsumerGroupServiceOptions, logger logging.LoggerContract) ConsumerGroupService {
+	return &ConsumerGroupServiceImpl{
+		Ctx:                    opts.Ctx,
+		Client:                 opts.RedisClient,
+		StreamMetadataService:  opts.MetadataService,
+		Logger:                 logger,
+		DefaultReclaimIdleTime: opts.DefaultReclaimIdleTime,
+	}
+}
+
+func (p *CreateGroupParameters) Validate() error {
+	if p.StreamName == "" {
+		return fmt.Errorf("stream name is required")
+	}
+
+	if p.GroupName == "" {
+		return fmt.Errorf("group name is required")
+	}
+
+	if p.ReclaimIdleTime < 0 {
+		return fmt.Errorf("reclaim idle time must not be negative")
+	}
+
+	return nil
+}
+
+func (p *ReadGroupParameters) Validate() error {
+	if p.StreamName == "" {
+		return fmt.Errorf("stream name is required")
+	}
+
+	if p.GroupName == "" {
+		return fmt.Errorf("group name is required")
+	}
+
+	if p.ConsumerName == "" {
+		return fmt.Errorf("consumer name is required")
+	}
+
+	if p.ConsumerName == CONSUMER_GROUP_REDELIVERY_CONSUMER || p.ConsumerName == CONSUMER_GROUP_DEAD_LETTER_CONSUMER {
+		return fmt.Errorf("consumer name %s is reserved", p.ConsumerName)
+	}
+
+	return nil
+}
+
+func (s *ConsumerGroupServiceImpl) CreateGroup(params *CreateGroupParameters) error {
+	err := params.Validate()
+	if err != nil {
+		return err
+	}
+
+	if params.StartID == "" {
+		params.StartID = "$"
+	}
+
+	if params.ReclaimIdleTime == 0 {
+		params.ReclaimIdleTime = s.DefaultReclaimIdleTime
+	}
+
+	s.Logger.Debug("Creating consumer group...", zap.String("stream", params.StreamName), zap.String("group", params.GroupName), zap.String("start_id", params.StartID))
+
+	err = s.Client.XGroupCreate(s.Ctx, params.StreamName, params.GroupName, params.StartID).Err()
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "BUSYGROUP") {
+			return ConsumerGroupExistsError(params.StreamName, params.GroupName)
+		}
+		// XGROUP CREATE refuses to create a group on a key that does not exist
+		if strings.Contains(err.Error(), "requires the key to exist") {
+			return StreamNotFoundError(params.StreamName)
+		}
+		return fmt.Errorf("failed to create consumer group %s on stream %s: %w", params.GroupName, params.StreamName, err)
+	}
+
+	err = s.WriteGroupMetadata(&ConsumerGroupMetadata{
+		StreamName:      params.StreamName,
+		GroupName:       params.GroupName,
+		ReclaimIdleTime: params.ReclaimIdleTime,
+		CreatedAt:       time.Now().Unix(),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.Client.SAdd(s.Ctx, CONSUMER_GROUP_REGISTRY_KEY, GetConsumerGroupHash(params.StreamName, params.GroupName)).Result()
+	if err != nil {
+		return fmt.Errorf("failed to add consumer group to registry: %w", err)
+	}
+
+	s.Logger.Debug("Consumer group created", zap.String("stream", params.StreamName), zap.String("group", params.GroupName))
+	return nil
+}
+
+func (s *ConsumerGroupServiceImpl) DeleteGroup(streamName string, groupName string) error {
+	destroyed, err := s.Client.XGroupDestroy(s.Ctx, streamName, groupName).Result()
+	if err != nil {
+		if err.Error() == "ERR no such key" {
+			return StreamNotFoundError(streamName)
+		}
+		return fmt.Errorf("failed to delete consumer group %s from stream %s: %w", groupName, streamName, err)
+	}
+
+	if destroyed == 0 {
+		return ConsumerGroupNotFoundError(streamName, groupName)
+	}
+
+	groupHash := GetConsumerGroupHash(streamName, groupName)
+
+	_, err = s.Client.SRem(s.Ctx, CONSUMER_GROUP_REGISTRY_KEY, groupHash).Result()
+	if err != nil {
+		return fmt.Errorf("failed to remove consumer group from registry: %w", err)
+	}
+
+	_, err = s.Client.Del(s.Ctx, GetConsumerGroupMetadataKey(groupHash)).Result()
+	if err != nil {
+		return fmt.Errorf("failed to delete consumer group metadata: %w", err)
+	}
+
+	s.Logger.Debug("Consumer group deleted", zap.String("stream", streamName), zap.String("group", groupName))
+	return nil
+}
+
+func (s *ConsumerGroupServiceImpl) SetReclaimIdleTime(streamName string, groupName string, idle time.Duration) error {
+	if idle <= 0 {
+		return fmt.Errorf("reclaim idle time must be greater than 0")
+	}
+
+	meta, err := s.GetGroupMetadata(GetConsumerGroupHash(streamName, groupName))
+	if err != nil {
+		return err
+	}
+
+	if meta == nil {
+		return ConsumerGroupNotFoundError(streamName, groupName)
+	}
+
+	meta.ReclaimIdleTime = idle
+	return s.WriteGroupMetadata(meta)
+}
+
+func (s *ConsumerGroupServiceImpl) ListGroups() ([]*ConsumerGroupMetadata, error) {
+	hashes, err := s.Client.SMembers(s.Ctx, CONSUMER_GROUP_REGISTRY_KEY).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list consumer groups: %w", err)
+	}
+
+	groups := make([]*ConsumerGroupMetadata, 0, len(hashes))
+	for _, hash := range hashes {
+		meta, err := s.GetGroupMetadata(hash)
+		if err != nil {
+			return nil, err
+		}
+
+		// The registry can briefly reference a group whose metadata was already removed
+		if meta == nil {
+			continue
+		}
+
+		groups = append(groups, meta)
+	}
+
+	return groups, nil
+}
+
+// Writes the metadata of a consumer group to Redis
+func (s *ConsumerGroupServiceImpl) WriteGroupMetadata(meta *ConsumerGroupMetadata) error {
+	key := GetConsumerGroupMetadataKey(GetConsumerGroupHash(meta.StreamName, meta.GroupName))
+
+	err := s.Client.HSet(s.Ctx, key,
+		"stream_name", meta.StreamName,
+		"group_name", meta.GroupName,
+		"reclaim_idle_time", strconv.FormatInt(meta.ReclaimIdleTime.Milliseconds(), 10),
+		"created_at", strconv.FormatInt(meta.CreatedAt, 10),
+	).Err()
+	if err != nil {
+		return fmt.Errorf("failed to write consumer group metadata: %w", err)
+	}
+
+	return nil
+}
+
+// Gets the metadata of a consumer group, returns nil when the group has no metadata
+func (s *ConsumerGroupServiceImpl) GetGroupMetadata(hash string) (*ConsumerGroupMetadata, error) {
+	metadata, err := s.Client.HGetAll(s.Ctx, GetConsumerGroupMetadataKey(hash)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get consumer group metadata: %w", err)
+	}
+
+	if len(metadata) == 0 {
+		return nil, nil
+	}
+
+	reclaimIdleTime, err := strconv.ParseInt(metadata["reclaim_idle_time"], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse reclaim_idle_time: %w", err)
+	}
+
+	return &ConsumerGroupMetadata{
+		StreamName:      metadata["stream_name"],
+		GroupName:       metadata["group_name"],
+		ReclaimIdleTime: time.Duration(reclaimIdleTime) * time.Millisecond,
+		CreatedAt:       utils.ParseInt64(metadata["created_at"]),
+	}, nil
+}
+
+func (s *ConsumerGroupServiceImpl) ReadGroup(ctx context.Context, params *ReadGroupParameters) ([]*ConsumerGroupMessage, error) {
+	err := params.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	redelivered, err := s.ClaimRedeliveredMessages(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(redelivered) > 0 {
+		return redelivered, nil
+	}
+
+	// A negative block duration omits the BLOCK argument, BLOCK 0 would wait forever
+	block := params.Block
+	if block <= 0 {
+		block = -1
+	}
+
+	streams, err := s.Client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    params.GroupName,
+		Consumer: params.ConsumerName,
+		Streams:  []string{params.StreamName, ">"},
+		Count:    params.Count,
+		Block:    block,
+	}).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return []*ConsumerGroupMessage{}, nil
+		}
+		if strings.HasPrefix(err.Error(), "NOGROUP") {
+			return nil, ConsumerGroupNotFoundError(params.StreamName, params.GroupName)
+		}
+		return nil, fmt.Errorf("failed to read messages from consumer group %s: %w", params.GroupName, err)
+	}
+
+	if len(streams) == 0 {
+		return []*ConsumerGroupMessage{}, nil
+	}
+
+	// Messages read with ">" have never been delivered before
+	messages := make([]*ConsumerGroupMessage, len(streams[0].Messages))
+	for i, msg := range streams[0].Messages {
+		messages[i] = &ConsumerGroupMessage{XMessage: msg, DeliveryCount: 1}
+	}
+
+	return messages, nil
+}
+
+// Claims messages held for redelivery for the consumer reading from the group
+func (s *ConsumerGroupServiceImpl) ClaimRedeliveredMessages(ctx context.Context, params *ReadGroupParameters) ([]*ConsumerGroupMessage, error) {
+	pending, err := s.Client.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream:   params.StreamName,
+		Group:    params.GroupName,
+		Consumer: CONSUMER_GROUP_REDELIVERY_CONSUMER,
+		Start:    "-",
+		End:      "+",
+		Count:    params.Count,
+	}).Result()
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "NOGROUP") {
+			return nil, ConsumerGroupNotFoundError(params.StreamName, params.GroupName)
+		}
+		return nil, fmt.Errorf("failed to get pending messages for consumer group %s: %w", params.GroupName, err)
+	}
+
+	if len(pending) == 0 {
+		return []*ConsumerGroupMessage{}, nil
+	}
+
+	policy := s.GetDeadLetterPolicy(params.StreamName)
+
+	ids := make([]string, 0, len(pending))
+	deliveryCounts := make(map[string]int64, len(pending))
+	deadLetters := make([]redis.XPendingExt, 0)
+	for _, entry := range pending {
+		// Delivering the message again would exceed the maximum number of deliveries
+		if policy != nil && entry.RetryCount >= policy.MaxDeliveries {
+			deadLetters = append(deadLetters, entry)
+			continue
+		}
+
+		ids = append(ids, entry.ID)
+		deliveryCounts[entry.ID] = entry.RetryCount
+	}
+
+	if len(deadLetters) > 0 {
+		err = s.DeadLetterMessages(ctx, params.StreamName, params.GroupName, policy, deadLetters)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(ids) == 0 {
+		return []*ConsumerGroupMessage{}, nil
+	}
+
+	claimed, err := s.Client.XClaim(ctx, &redis.XClaimArgs{
+		Stream:   params.StreamName,
+		Group:    params.GroupName,
+		Consumer: params.ConsumerName,
+		MinIdle:  CONSUMER_GROUP_REDELIVERY_MIN_IDLE,
+		Messages: ids,
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim messages held for redelivery for consumer group %s: %w", params.GroupName, err)
+	}
+
+	// XCLAIM increments the delivery counter of every claimed message
+	messages := make([]*ConsumerGroupMessage, len(claimed))
+	for i, msg := range claimed {
+		messages[i] = &ConsumerGroupMessage{XMessage: msg, DeliveryCount: deliveryCounts[msg.ID] + 1}
+	}
+
+	s.Logger.Debug("Redelivering messages", zap.String("stream", params.StreamName), zap.String("group", params.GroupName), zap.String("consumer", params.ConsumerName), zap.Int("count", len(messages)))
+
+	return messages, nil
+}
+
+// Gets the metadata of a stream when dead lettering is enabled for it, returns nil otherwise
+func (s *ConsumerGroupServiceImpl) GetDeadLetterPolicy(streamName string) *StreamMetadata {
+	metadata, err := s.StreamMetadataService.GetStreamMetadata(utils.HashString(streamName))
+	if err != nil {
+		// Streams created outside the broker have no metadata and are never dead lettered
+		s.Logger.Warn("Failed to get dead letter policy, delivering messages without a limit", zap.String("stream", streamName), zap.Error(err))
+		return nil
+	}
+
+	if metadata.MaxDeliveries <= 0 || metadata.DeadLetterStream == "" {
+		return nil
+	}
+
+	return metadata
+}
+
+// Moves messages that exceeded the maximum number of deliveries to the dead letter stream and acknowledges them on the source stream
+func (s *ConsumerGroupServiceImpl) DeadLetterMessages(ctx context.Context, streamName string, groupName string, policy *StreamMetadata, entries []redis.XPendingExt) error {
+	ids := make([]string, len(entries))
+	deliveryCounts := make(map[string]int64, len(entries))
+	for i, entry := range entries {
+		ids[i] = entry.ID
+		deliveryCounts[entry.ID] = entry.RetryCount
+	}
+
+	// Only messages this reader manages to claim are dead lettered, entries left behind by a crash are swept back by the reclaimer
+	claimed, err := s.Client.XClaimJustID(ctx, &redis.XClaimArgs{
+		Stream:   streamName,
+		Group:    groupName,
+		Consumer: CONSUMER_GROUP_DEAD_LETTER_CONSUMER,
+		MinIdle:  CONSUMER_GROUP_REDELIVERY_MIN_IDLE,
+		Messages: ids,
+	}).Result()
+	if err != nil {
+		return fmt.Errorf("failed to claim messages for dead lettering for consumer group %s: %w", groupName, err)
+	}
+
+	// Dead letter streams configured before they had to share the stream's hash tag cannot be used in one script with it
+	atomic := InSameSlot(streamName, policy.DeadLetterStream)
+	if !atomic {
+		s.Logger.Warn("Dead letter stream is in another cluster slot, messages are moved without a script",
+			zap.String("stream", streamName),
+			zap.String("dead_letter_stream", policy.DeadLetterStream))
+	}
+
+	for _, id := range claimed {
+		fields := GetDeadLetterFields(streamName, groupName, id, policy.MaxDeliveries, deliveryCounts[id])
+
+		if atomic {
+			args := append([]interface{}{groupName, id, CONSUMER_GROUP_DEAD_LETTER_CONSUMER}, fields...)
+			moved, err := deadLetterScript.Run(ctx, s.Client, []string{streamName, policy.DeadLetterStream}, args...).Int64()
+			if err != nil {
+				return fmt.Errorf("failed to move message %s to dead letter stream %s: %w", id, policy.DeadLetterStream, err)
+			}
+
+			if moved == 0 {
+				continue
+			}
+		} else {
+			err = s.MoveToDeadLetterStream(ctx, streamName, groupName, policy.DeadLetterStream, id, fields)
+			if err != nil {
+				return err
+			}
+		}
+
+		s.Logger.Warn("Moved message to dead letter stream",
+			zap.String("stream", streamName),
+			zap.String("group", groupName),
+			zap.String("message_id", id),
+			zap.String("dead_letter_stream", policy.DeadLetterStream),
+			zap.Int64("delivery_count", deliveryCounts[id]))
+	}
+
+	return nil
+}
+
+// Gets the fields added to a message when it is moved to a dead letter stream, in a fixed order
+func GetDeadLetterFields(streamName string, groupName string, id string, maxDeliveries int64, deliveryCount int64) []interface{} {
+	return []interface{}{
+		DEAD_LETTER_FIELD_ORIGINAL_ID, id,
+		DEAD_LETTER_FIELD_ORIGINAL_STREAM, streamName,
+		DEAD_LETTER_FIELD_GROUP, groupName,
+		DEAD_LETTER_FIELD_FAILURE_REASON, fmt.Sprintf("exceeded max deliveries of %d", maxDeliveries),
+		DEAD_LETTER_FIELD_DELIVERY_COUNT, strconv.FormatInt(deliveryCount, 10),
+	}
+}
+
+// Moves a message to a dead letter stream in another cluster slot with separate commands, a crash between adding and
+// acknowledging the message dead letters it again on its next redelivery
+func (s *ConsumerGroupServiceImpl) MoveToDeadLetterStream(ctx context.Context, streamName string, groupName string, deadLetterStream string, id string, fields []interface{}) error {
+	messages, err := s.Client.XRangeN(ctx, streamName, id, id, 1).Result()
+	if err != nil {
+		return fmt.Errorf("failed to get message %s from stream %s: %w", id, streamName, err)
+	}
+
+	// Messages already removed from the stream by retention only need to be acknowledged
+	if len(messages) > 0 {
+		values := make(map[string]interface{}, len(messages[0].Values)+len(fields)/2)
+		for k, v := range messages[0].Values {
+			values[k] = v
+		}
+		for i := 0; i < len(fields); i += 2 {
+			values[fields[i].(string)] = fields[i+1]
+		}
+
+		_, err = s.Client.XAdd(ctx, &redis.XAddArgs{
+			Stream: deadLetterStream,
+			Values: values,
+		}).Result()
+		if err != nil {
+			return fmt.Errorf("failed to add message %s to dead letter stream %s: %w", id, deadLetterStream, err)
+		}
+	}
+
+	_, err = s.Client.XAck(ctx, streamName, groupName, id).Result()
+	if err != nil {
+		return fmt.Errorf("failed to acknowledge dead lettered message %s for consumer group %s: %w", id, groupName, err)
+	}
+
+	return nil
+}
+
+func (s *ConsumerGroupServiceImpl) ReclaimIdleMessages(streamName string, groupName string, minIdle time.Duration) (int64, error) {
+	var reclaimed int64
+	start := "0-0"
+
+	for {
+		// JUSTID leaves the delivery counter untouched, it is incremented once the message is claimed for redelivery
+		ids, next, err := s.Client.XAutoClaimJustID(s.Ctx, &redis.XAutoClaimArgs{
+			Stream:   streamName,
+			Group:    groupName,
+			Consumer: CONSUMER_GROUP_REDELIVERY_CONSUMER,
+			MinIdle:  minIdle,
+			Start:    start,
+			Count:    CONSUMER_GROUP_RECLAIM_BATCH_SIZE,
+		}).Result()
+		if err != nil {
+			if strings.HasPrefix(err.Error(), "NOGROUP") {
+				return reclaimed, ConsumerGroupNotFoundError(streamName, groupName)
+			}
+			return reclaimed, fmt.Errorf("failed to reclaim idle messages for consumer group %s: %w", groupName, err)
+		}
+
+		reclaimed += int64(len(ids))
+
+		// XAUTOCLAIM returns 0-0 once the whole pending entries list has been scanned
+		if next == "0-0" || next == "" {
+			break
+		}
+		start = next
+	}
+
+	return reclaimed, nil
+}
+
+func (s *ConsumerGroupServiceImpl) Ack(streamName string, groupName string, ids []string) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	acknowledged, err := s.Client.XAck(s.Ctx, streamName, groupName, ids...).Result()
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "NOGROUP") {
+			return 0, ConsumerGroupNotFoundError(streamName, groupName)
+		}
+		return 0, fmt.Errorf("failed to acknowledge messages for consumer group %s: %w", groupName, err)
+	}
+
+	return acknowledged, nil
+}
+
+func (s *ConsumerGroupServiceImpl) Nack(streamName string, groupName string, ids []string) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	// JUSTID leaves the delivery counter untouched, it is incremented once the message is claimed for redelivery
+	claimed, err := s.Client.XClaimJustID(s.Ctx, &redis.XClaimArgs{
+		Stream:   streamName,
+		Group:    groupName,
+		Consumer: CONSUMER_GROUP_REDELIVERY_CONSUMER,
+		MinIdle:  0,
+		Messages: ids,
+	}).Result()
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "NOGROUP") {
+			return 0, ConsumerGroupNotFoundError(streamName, groupName)
+		}
+		return 0, fmt.Errorf("failed to negatively acknowledge messages for consumer group %s: %w", groupName, err)
+	}
+
+	return int64(len(claimed)), nil
+}
+
+// Gets the hash used to identify a consumer group in the registry
+func GetConsumerGroupHash(streamName string, groupName string) string {
+	return utils.HashString(fmt.Sprintf("%s:%s", streamName, groupName))
+}
+
+// Gets the key of the hash holding a consumer group's metadata
+func GetConsumerGroupMetadataKey(hash string) string {
+	return fmt.Sprintf("%s%s", CONSUMER_GROUP_META_DATA_PREFIX, hash)
+}
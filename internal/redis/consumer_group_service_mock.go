@@ -0,0 +1,62 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type ConsumerGroupServiceMock struct {
+	mock.Mock
+}
+
+func NewConsumerGroupServiceMock() *ConsumerGroupServiceMock {
+	return &ConsumerGroupServiceMock{}
+}
+
+func (m *ConsumerGroupServiceMock) CreateGroup(params *CreateGroupParameters) error {
+	args := m.Called(params)
+	return args.Error(0)
+}
+
+func (m *ConsumerGroupServiceMock) DeleteGroup(streamName string, groupName string) error {
+	args := m.Called(streamName, groupName)
+	return args.Error(0)
+}
+
+func (m *ConsumerGroupServiceMock) SetReclaimIdleTime(streamName string, groupName string, idle time.Duration) error {
+	args := m.Called(streamName, groupName, idle)
+	return args.Error(0)
+}
+
+func (m *ConsumerGroupServiceMock) ListGroups() ([]*ConsumerGroupMetadata, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*ConsumerGroupMetadata), args.Error(1)
+}
+
+func (m *ConsumerGroupServiceMock) ReadGroup(ctx context.Context, params *ReadGroupParameters) ([]*ConsumerGroupMessage, error) {
+	args := m.Called(ctx, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*ConsumerGroupMessage), args.Error(1)
+}
+
+func (m *ConsumerGroupServiceMock) ReclaimIdleMessages(streamName string, groupName string, minIdle time.Duration) (int64, error) {
+	args := m.Called(streamName, groupName, minIdle)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *ConsumerGroupServiceMock) Ack(streamName string, groupName string, ids []string) (int64, error) {
+	args := m.Called(streamName, groupName, ids)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *ConsumerGroupServiceMock) Nack(streamName string, groupName string, ids []string) (int64, error) {
+	args := m.Called(streamName, groupName, ids)
+	return args.Get(0).(int64), args.Error(1)
+}
@@ -0,0 +1,229 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	rdb "github.com/redis/go-redis/v9"
+	"github.com/streamweaverio/broker/internal/testutils"
+	"github.com/streamweaverio/broker/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupConsumerGroupService() (ConsumerGroupService, *MockRedisClient, *StreamMetadataServiceMock) {
+	client := &MockRedisClient{}
+	metadataService := NewStreamMetadataServiceMock()
+	logger := testutils.NewMockLogger()
+
+	service := NewConsumerGroupService(&ConsumerGroupServiceOptions{
+		Ctx:                    context.Background(),
+		RedisClient:            client,
+		MetadataService:        metadataService,
+		DefaultReclaimIdleTime: 5 * time.Minute,
+	}, logger)
+
+	return service, client, metadataService
+}
+
+func TestConsumerGroupService_CreateGroup(t *testing.T) {
+	t.Run("Create a consumer group and register it", func(t *testing.T) {
+		service, client, _ := setupConsumerGroupService()
+		groupHash := GetConsumerGroupHash("test-stream", "workers")
+
+		client.On("XGroupCreate", mock.Anything, "test-stream", "workers", "$").Return(rdb.NewStatusResult("OK", nil))
+		client.On("HSet", mock.Anything, GetConsumerGroupMetadataKey(groupHash), mock.MatchedBy(func(values []interface{}) bool {
+			return len(values) == 8 && values[4] == "reclaim_idle_time" && values[5] == "300000"
+		})).Return(rdb.NewIntResult(4, nil))
+		client.On("SAdd", mock.Anything, CONSUMER_GROUP_REGISTRY_KEY, []interface{}{groupHash}).Return(rdb.NewIntResult(1, nil))
+
+		err := service.CreateGroup(&CreateGroupParameters{StreamName: "test-stream", GroupName: "workers"})
+
+		assert.NoError(t, err)
+		client.AssertExpectations(t)
+	})
+
+	t.Run("Return an error if the group already exists", func(t *testing.T) {
+		service, client, _ := setupConsumerGroupService()
+
+		client.On("XGroupCreate", mock.Anything, "test-stream", "workers", "$").
+			Return(rdb.NewStatusResult("", errors.New("BUSYGROUP Consumer Group name already exists")))
+
+		err := service.CreateGroup(&CreateGroupParameters{StreamName: "test-stream", GroupName: "workers", StartID: "$"})
+
+		assert.IsType(t, &RedisConsumerGroupExistsError{}, err)
+		client.AssertExpectations(t)
+	})
+}
+
+func TestConsumerGroupService_ReadGroup(t *testing.T) {
+	params := &ReadGroupParameters{
+		StreamName:   "test-stream",
+		GroupName:    "workers",
+		ConsumerName: "worker-1",
+		Count:        10,
+	}
+
+	t.Run("Read new messages when nothing was negatively acknowledged", func(t *testing.T) {
+		service, client, _ := setupConsumerGroupService()
+
+		pendingCmd := &rdb.XPendingExtCmd{}
+		pendingCmd.SetVal([]rdb.XPendingExt{})
+		client.On("XPendingExt", mock.Anything, mock.MatchedBy(func(args *rdb.XPendingExtArgs) bool {
+			return args.Consumer == CONSUMER_GROUP_REDELIVERY_CONSUMER
+		})).Return(pendingCmd)
+
+		readCmd := &rdb.XStreamSliceCmd{}
+		readCmd.SetVal([]rdb.XStream{{Stream: "test-stream", Messages: []rdb.XMessage{{ID: "1-0"}}}})
+		client.On("XReadGroup", mock.Anything, mock.MatchedBy(func(args *rdb.XReadGroupArgs) bool {
+			return args.Consumer == "worker-1" && args.Streams[1] == ">" && args.Block < 0
+		})).Return(readCmd)
+
+		messages, err := service.ReadGroup(context.Background(), params)
+
+		assert.NoError(t, err)
+		assert.Len(t, messages, 1)
+		assert.Equal(t, int64(1), messages[0].DeliveryCount)
+		client.AssertExpectations(t)
+	})
+
+	t.Run("Redeliver negatively acknowledged messages first", func(t *testing.T) {
+		service, client, metadataService := setupConsumerGroupService()
+
+		metadataService.On("GetStreamMetadata", utils.HashString("test-stream")).Return(&StreamMetadata{Name: "test-stream"}, nil)
+
+		pendingCmd := &rdb.XPendingExtCmd{}
+		pendingCmd.SetVal([]rdb.XPendingExt{{ID: "1-0", Consumer: CONSUMER_GROUP_REDELIVERY_CONSUMER, RetryCount: 2}})
+		client.On("XPendingExt", mock.Anything, mock.Anything).Return(pendingCmd)
+		client.On("XClaim", mock.Anything, mock.MatchedBy(func(args *rdb.XClaimArgs) bool {
+			return args.Consumer == "worker-1" && len(args.Messages) == 1 && args.Messages[0] == "1-0"
+		})).Return(rdb.NewXMessageSliceCmdResult([]rdb.XMessage{{ID: "1-0"}}, nil))
+
+		messages, err := service.ReadGroup(context.Background(), params)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "1-0", messages[0].ID)
+		assert.Equal(t, int64(3), messages[0].DeliveryCount)
+		client.AssertNotCalled(t, "XReadGroup", mock.Anything, mock.Anything)
+		client.AssertExpectations(t)
+	})
+}
+
+func TestConsumerGroupService_DeadLetter(t *testing.T) {
+	params := &ReadGroupParameters{
+		StreamName:   "test-stream",
+		GroupName:    "workers",
+		ConsumerName: "worker-1",
+		Count:        10,
+	}
+
+	setup := func(deadLetterStream string) (ConsumerGroupService, *MockRedisClient) {
+		service, client, metadataService := setupConsumerGroupService()
+
+		metadataService.On("GetStreamMetadata", utils.HashString("test-stream")).Return(&StreamMetadata{
+			Name:             "test-stream",
+			MaxDeliveries:    3,
+			DeadLetterStream: deadLetterStream,
+		}, nil)
+
+		pendingCmd := &rdb.XPendingExtCmd{}
+		pendingCmd.SetVal([]rdb.XPendingExt{
+			{ID: "1-0", Consumer: CONSUMER_GROUP_REDELIVERY_CONSUMER, RetryCount: 3},
+			{ID: "2-0", Consumer: CONSUMER_GROUP_REDELIVERY_CONSUMER, RetryCount: 1},
+		})
+		client.On("XPendingExt", mock.Anything, mock.Anything).Return(pendingCmd)
+
+		// The message that was already delivered the maximum number of times is claimed for dead lettering
+		client.On("XClaimJustID", mock.Anything, mock.MatchedBy(func(args *rdb.XClaimArgs) bool {
+			return args.Consumer == CONSUMER_GROUP_DEAD_LETTER_CONSUMER && len(args.Messages) == 1 && args.Messages[0] == "1-0"
+		})).Return(rdb.NewStringSliceResult([]string{"1-0"}, nil))
+
+		// The remaining message is redelivered
+		client.On("XClaim", mock.Anything, mock.MatchedBy(func(args *rdb.XClaimArgs) bool {
+			return args.Consumer == "worker-1" && len(args.Messages) == 1 && args.Messages[0] == "2-0"
+		})).Return(rdb.NewXMessageSliceCmdResult([]rdb.XMessage{{ID: "2-0"}}, nil))
+
+		return service, client
+	}
+
+	t.Run("Move and acknowledge the message in one script", func(t *testing.T) {
+		service, client := setup("{test-stream}.dlq")
+
+		client.On("Eval", mock.Anything, deadLetterScriptSource, []string{"test-stream", "{test-stream}.dlq"}, mock.MatchedBy(func(args []interface{}) bool {
+			return assert.ObjectsAreEqual([]interface{}{"workers", "1-0", CONSUMER_GROUP_DEAD_LETTER_CONSUMER}, args[:3]) &&
+				assert.ObjectsAreEqual(GetDeadLetterFields("test-stream", "workers", "1-0", 3, 3), args[3:])
+		})).Return(rdb.NewCmdResult(int64(1), nil))
+
+		messages, err := service.ReadGroup(context.Background(), params)
+
+		assert.NoError(t, err)
+		assert.Len(t, messages, 1)
+		assert.Equal(t, "2-0", messages[0].ID)
+		assert.Equal(t, int64(2), messages[0].DeliveryCount)
+		client.AssertNotCalled(t, "XAdd", mock.Anything, mock.Anything)
+		client.AssertNotCalled(t, "XAck", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		client.AssertExpectations(t)
+	})
+
+	t.Run("Move messages with separate commands to dead letter streams in another slot", func(t *testing.T) {
+		service, client := setup("test-stream.dlq")
+
+		client.On("XRangeN", mock.Anything, "test-stream", "1-0", "1-0", int64(1)).
+			Return(rdb.NewXMessageSliceCmdResult([]rdb.XMessage{{ID: "1-0", Values: map[string]interface{}{"event_name": "login"}}}, nil))
+		client.On("XAdd", mock.Anything, mock.MatchedBy(func(args *rdb.XAddArgs) bool {
+			values, ok := args.Values.(map[string]interface{})
+			return ok && args.Stream == "test-stream.dlq" &&
+				values["event_name"] == "login" &&
+				values[DEAD_LETTER_FIELD_ORIGINAL_ID] == "1-0" &&
+				values[DEAD_LETTER_FIELD_ORIGINAL_STREAM] == "test-stream" &&
+				values[DEAD_LETTER_FIELD_DELIVERY_COUNT] == "3" &&
+				values[DEAD_LETTER_FIELD_FAILURE_REASON] != ""
+		})).Return(rdb.NewStringResult("5-0", nil))
+		client.On("XAck", mock.Anything, "test-stream", "workers", []string{"1-0"}).Return(rdb.NewIntResult(1, nil))
+
+		messages, err := service.ReadGroup(context.Background(), params)
+
+		assert.NoError(t, err)
+		assert.Len(t, messages, 1)
+		client.AssertExpectations(t)
+	})
+}
+
+func TestConsumerGroupService_Nack(t *testing.T) {
+	service, client, _ := setupConsumerGroupService()
+	ids := []string{"1-0", "2-0"}
+
+	client.On("XClaimJustID", mock.Anything, mock.MatchedBy(func(args *rdb.XClaimArgs) bool {
+		return args.Consumer == CONSUMER_GROUP_REDELIVERY_CONSUMER && len(args.Messages) == 2
+	})).Return(rdb.NewStringSliceResult(ids, nil))
+
+	count, err := service.Nack("test-stream", "workers", ids)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+	client.AssertExpectations(t)
+}
+
+func TestConsumerGroupService_ReclaimIdleMessages(t *testing.T) {
+	service, client, _ := setupConsumerGroupService()
+
+	firstBatch := &rdb.XAutoClaimJustIDCmd{}
+	firstBatch.SetVal([]string{"1-0", "2-0"}, "3-0")
+	client.On("XAutoClaimJustID", mock.Anything, mock.MatchedBy(func(args *rdb.XAutoClaimArgs) bool {
+		return args.Start == "0-0" && args.Consumer == CONSUMER_GROUP_REDELIVERY_CONSUMER && args.MinIdle == time.Minute
+	})).Return(firstBatch).Once()
+
+	lastBatch := &rdb.XAutoClaimJustIDCmd{}
+	lastBatch.SetVal([]string{"3-0"}, "0-0")
+	client.On("XAutoClaimJustID", mock.Anything, mock.MatchedBy(func(args *rdb.XAutoClaimArgs) bool {
+		return args.Start == "3-0"
+	})).Return(lastBatch).Once()
+
+	reclaimed, err := service.ReclaimIdleMessages("test-stream", "workers", time.Minute)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), reclaimed)
+	client.AssertExpectations(t)
+}
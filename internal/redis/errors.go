@@ -5,20 +5,106 @@ import "fmt"
 type RedisNotEnoughNodesError struct{}
 
 type RedisStreamPublishError struct {
-	Err error
+	// Position of the message in the publish request
+	Index int
+	Err   error
+}
+
+type RedisPublishBatchTooLargeError struct {
+	Size int
+	Max  int
+}
+
+type RedisSchemaNotFoundError struct {
+	Id int64
+}
+
+type RedisSubjectNotFoundError struct {
+	Subject string
+	Version int64
+}
+
+type RedisSchemaIncompatibleError struct {
+	Subject       string
+	Compatibility string
+	Err           error
+}
+
+type RedisSchemaValidationError struct {
+	// Position of the message in the publish request
+	Index int
+	Err   error
 }
 
 type RedisStreamNotFoundError struct {
 	Name string
 }
 
+type RedisArchiveWatermarkConflictError struct {
+	Stream string
+	// Watermark the archiver started from
+	Expected string
+}
+
+type RedisFencedError struct {
+	Stream string
+	// Fencing token the write was rejected with
+	Token int64
+}
+
+type RedisConsumerGroupExistsError struct {
+	Stream string
+	Group  string
+}
+
+type RedisConsumerGroupNotFoundError struct {
+	Stream string
+	Group  string
+}
+
 func NotEnoughNodesError() *RedisNotEnoughNodesError {
 	return &RedisNotEnoughNodesError{}
 }
 
-func StreamPublishError(err error) *RedisStreamPublishError {
+func StreamPublishError(index int, err error) *RedisStreamPublishError {
 	return &RedisStreamPublishError{
-		Err: err,
+		Index: index,
+		Err:   err,
+	}
+}
+
+func PublishBatchTooLargeError(size int, max int) *RedisPublishBatchTooLargeError {
+	return &RedisPublishBatchTooLargeError{
+		Size: size,
+		Max:  max,
+	}
+}
+
+func SchemaNotFoundError(id int64) *RedisSchemaNotFoundError {
+	return &RedisSchemaNotFoundError{
+		Id: id,
+	}
+}
+
+func SubjectNotFoundError(subject string, version int64) *RedisSubjectNotFoundError {
+	return &RedisSubjectNotFoundError{
+		Subject: subject,
+		Version: version,
+	}
+}
+
+func SchemaIncompatibleError(subject string, compatibility string, err error) *RedisSchemaIncompatibleError {
+	return &RedisSchemaIncompatibleError{
+		Subject:       subject,
+		Compatibility: compatibility,
+		Err:           err,
+	}
+}
+
+func SchemaValidationError(index int, err error) *RedisSchemaValidationError {
+	return &RedisSchemaValidationError{
+		Index: index,
+		Err:   err,
 	}
 }
 
@@ -28,14 +114,81 @@ func StreamNotFoundError(name string) *RedisStreamNotFoundError {
 	}
 }
 
+func ArchiveWatermarkConflictError(stream string, expected string) *RedisArchiveWatermarkConflictError {
+	return &RedisArchiveWatermarkConflictError{
+		Stream:   stream,
+		Expected: expected,
+	}
+}
+
+func FencedError(stream string, token int64) *RedisFencedError {
+	return &RedisFencedError{
+		Stream: stream,
+		Token:  token,
+	}
+}
+
+func ConsumerGroupExistsError(stream string, group string) *RedisConsumerGroupExistsError {
+	return &RedisConsumerGroupExistsError{
+		Stream: stream,
+		Group:  group,
+	}
+}
+
+func ConsumerGroupNotFoundError(stream string, group string) *RedisConsumerGroupNotFoundError {
+	return &RedisConsumerGroupNotFoundError{
+		Stream: stream,
+		Group:  group,
+	}
+}
+
 func (e *RedisNotEnoughNodesError) Error() string {
 	return "Not enough nodes provided"
 }
 
 func (e *RedisStreamPublishError) Error() string {
-	return fmt.Sprintf("Error publishing message: %s", e.Err)
+	return fmt.Sprintf("Error publishing message %d: %s", e.Index, e.Err)
+}
+
+func (e *RedisPublishBatchTooLargeError) Error() string {
+	return fmt.Sprintf("Atomic publish of %d messages exceeds the max batch size of %d", e.Size, e.Max)
+}
+
+func (e *RedisSchemaNotFoundError) Error() string {
+	return fmt.Sprintf("Schema: %d not found", e.Id)
+}
+
+func (e *RedisSubjectNotFoundError) Error() string {
+	if e.Version == 0 {
+		return fmt.Sprintf("Subject: %s has no schema", e.Subject)
+	}
+	return fmt.Sprintf("Version: %d of subject: %s not found", e.Version, e.Subject)
+}
+
+func (e *RedisSchemaIncompatibleError) Error() string {
+	return fmt.Sprintf("Schema is not %s compatible with the latest version of subject: %s: %s", e.Compatibility, e.Subject, e.Err)
+}
+
+func (e *RedisSchemaValidationError) Error() string {
+	return fmt.Sprintf("Message %d does not match its schema: %s", e.Index, e.Err)
 }
 
 func (e *RedisStreamNotFoundError) Error() string {
 	return fmt.Sprintf("Stream: %s not found", e.Name)
 }
+
+func (e *RedisArchiveWatermarkConflictError) Error() string {
+	return fmt.Sprintf("Archive watermark of stream: %s moved past: %s", e.Stream, e.Expected)
+}
+
+func (e *RedisFencedError) Error() string {
+	return fmt.Sprintf("Fencing token: %d was superseded on stream: %s", e.Token, e.Stream)
+}
+
+func (e *RedisConsumerGroupExistsError) Error() string {
+	return fmt.Sprintf("Consumer group: %s already exists on stream: %s", e.Group, e.Stream)
+}
+
+func (e *RedisConsumerGroupNotFoundError) Error() string {
+	return fmt.Sprintf("Consumer group: %s not found on stream: %s", e.Group, e.Stream)
+}
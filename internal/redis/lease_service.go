@@ -0,0 +1,146 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/streamweaverio/broker/internal/logging"
+	"go.uber.org/zap"
+)
+
+// Takes the lease with SET NX PX when nobody holds it and returns the new fencing token, or 0 when it is held
+const leaseAcquireScriptSource = `
+if redis.call("EXISTS", KEYS[1]) == 1 then
+	return 0
+end
+local token = redis.call("INCR", KEYS[2])
+redis.call("SET", KEYS[1], ARGV[1] .. "|" .. token, "NX", "PX", ARGV[2])
+return token
+`
+
+// Extends the lease only while it is still held by the same holder and fencing token
+const leaseRenewScriptSource = `
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`
+
+// Deletes the lease only while it is still held by the same holder and fencing token
+const leaseReleaseScriptSource = `
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`
+
+var leaseAcquireScript = redis.NewScript(leaseAcquireScriptSource)
+var leaseRenewScript = redis.NewScript(leaseRenewScriptSource)
+var leaseReleaseScript = redis.NewScript(leaseReleaseScriptSource)
+
+type Lease struct {
+	// Instance holding the lease
+	Holder string
+	// Fencing token handed out when the lease was acquired, increases with every new holder
+	Token int64
+}
+
+type LeaseService interface {
+	// Acquire a lease for the holder, returns the fencing token or 0 when another holder has the lease
+	Acquire(name string, holder string, ttl time.Duration) (int64, error)
+	// Extend a lease held by the holder with the fencing token, returns false when the lease was lost
+	Renew(name string, holder string, token int64, ttl time.Duration) (bool, error)
+	// Release a lease held by the holder with the fencing token
+	Release(name string, holder string, token int64) error
+	// Get the current holder of a lease, nil when nobody holds it
+	GetLease(name string) (*Lease, error)
+}
+
+type LeaseServiceImpl struct {
+	Ctx    context.Context
+	Client RedisStreamClient
+	Logger logging.LoggerContract
+}
+
+func NewLeaseService(ctx context.Context, client RedisStreamClient, logger logging.LoggerContract) LeaseService {
+	return &LeaseServiceImpl{
+		Ctx:    ctx,
+		Client: client,
+		Logger: logger,
+	}
+}
+
+func GetLeaseKey(name string) string {
+	return LEASE_KEY_PREFIX + name
+}
+
+// Formats the value stored in a lease key
+func FormatLeaseValue(holder string, token int64) string {
+	return fmt.Sprintf("%s|%d", holder, token)
+}
+
+// Parses the value stored in a lease key, the holder may itself contain the separator
+func ParseLeaseValue(value string) (*Lease, error) {
+	separator := strings.LastIndex(value, "|")
+	if separator == -1 {
+		return nil, fmt.Errorf("invalid lease value: %s", value)
+	}
+
+	token, err := strconv.ParseInt(value[separator+1:], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid lease fencing token: %w", err)
+	}
+
+	return &Lease{
+		Holder: value[:separator],
+		Token:  token,
+	}, nil
+}
+
+func (s *LeaseServiceImpl) Acquire(name string, holder string, ttl time.Duration) (int64, error) {
+	key := GetLeaseKey(name)
+	token, err := leaseAcquireScript.Run(s.Ctx, s.Client, []string{key, key + LEASE_TOKEN_SUFFIX}, holder, ttl.Milliseconds()).Int64()
+	if err != nil {
+		return 0, fmt.Errorf("failed to acquire lease %s: %w", name, err)
+	}
+
+	if token > 0 {
+		s.Logger.Debug("Acquired lease", zap.String("lease", name), zap.String("holder", holder), zap.Int64("token", token))
+	}
+
+	return token, nil
+}
+
+func (s *LeaseServiceImpl) Renew(name string, holder string, token int64, ttl time.Duration) (bool, error) {
+	renewed, err := leaseRenewScript.Run(s.Ctx, s.Client, []string{GetLeaseKey(name)}, FormatLeaseValue(holder, token), ttl.Milliseconds()).Int64()
+	if err != nil {
+		return false, fmt.Errorf("failed to renew lease %s: %w", name, err)
+	}
+
+	return renewed == 1, nil
+}
+
+func (s *LeaseServiceImpl) Release(name string, holder string, token int64) error {
+	err := leaseReleaseScript.Run(s.Ctx, s.Client, []string{GetLeaseKey(name)}, FormatLeaseValue(holder, token)).Err()
+	if err != nil {
+		return fmt.Errorf("failed to release lease %s: %w", name, err)
+	}
+
+	return nil
+}
+
+func (s *LeaseServiceImpl) GetLease(name string) (*Lease, error) {
+	value, err := s.Client.Get(s.Ctx, GetLeaseKey(name)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get lease %s: %w", name, err)
+	}
+
+	return ParseLeaseValue(value)
+}
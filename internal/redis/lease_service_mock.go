@@ -0,0 +1,38 @@
+package redis
+
+import (
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type LeaseServiceMock struct {
+	mock.Mock
+}
+
+func NewLeaseServiceMock() *LeaseServiceMock {
+	return &LeaseServiceMock{}
+}
+
+func (m *LeaseServiceMock) Acquire(name string, holder string, ttl time.Duration) (int64, error) {
+	args := m.Called(name, holder, ttl)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *LeaseServiceMock) Renew(name string, holder string, token int64, ttl time.Duration) (bool, error) {
+	args := m.Called(name, holder, token, ttl)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *LeaseServiceMock) Release(name string, holder string, token int64) error {
+	args := m.Called(name, holder, token)
+	return args.Error(0)
+}
+
+func (m *LeaseServiceMock) GetLease(name string) (*Lease, error) {
+	args := m.Called(name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Lease), args.Error(1)
+}
@@ -0,0 +1,66 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	rdb "github.com/redis/go-redis/v9"
+	"github.com/streamweaverio/broker/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupLeaseService() (LeaseService, *MockRedisClient) {
+	client := &MockRedisClient{}
+	return NewLeaseService(context.Background(), client, testutils.NewMockLogger()), client
+}
+
+func TestLeaseService_Acquire(t *testing.T) {
+	service, client := setupLeaseService()
+
+	client.On("Eval", mock.Anything, leaseAcquireScriptSource, []string{"{streamweaver_lease}:retention", "{streamweaver_lease}:retention:token"}, []interface{}{"broker-1", int64(15000)}).
+		Return(rdb.NewCmdResult(int64(7), nil))
+
+	token, err := service.Acquire("retention", "broker-1", 15*time.Second)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), token)
+	client.AssertExpectations(t)
+}
+
+func TestLeaseService_Renew(t *testing.T) {
+	service, client := setupLeaseService()
+
+	// The lease was taken over by another holder, so the script does not extend it
+	client.On("Eval", mock.Anything, leaseRenewScriptSource, []string{"{streamweaver_lease}:retention"}, []interface{}{"broker-1|7", int64(15000)}).
+		Return(rdb.NewCmdResult(int64(0), nil))
+
+	renewed, err := service.Renew("retention", "broker-1", 7, 15*time.Second)
+
+	assert.NoError(t, err)
+	assert.False(t, renewed)
+	client.AssertExpectations(t)
+}
+
+func TestLeaseService_GetLease(t *testing.T) {
+	t.Run("Return the holder and fencing token", func(t *testing.T) {
+		service, client := setupLeaseService()
+		client.On("Get", mock.Anything, "{streamweaver_lease}:retention").Return(rdb.NewStringResult("host|a|12", nil))
+
+		lease, err := service.GetLease("retention")
+
+		assert.NoError(t, err)
+		assert.Equal(t, &Lease{Holder: "host|a", Token: 12}, lease)
+	})
+
+	t.Run("Return nil when nobody holds the lease", func(t *testing.T) {
+		service, client := setupLeaseService()
+		client.On("Get", mock.Anything, "{streamweaver_lease}:retention").Return(rdb.NewStringResult("", rdb.Nil))
+
+		lease, err := service.GetLease("retention")
+
+		assert.NoError(t, err)
+		assert.Nil(t, lease)
+	})
+}
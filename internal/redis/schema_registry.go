@@ -0,0 +1,320 @@
+package redis
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/streamweaverio/broker/internal/logging"
+	"github.com/streamweaverio/broker/internal/schema"
+	"go.uber.org/zap"
+)
+
+// Adds a schema as the next version of a subject, allocating its ID from the counter KEYS[4] only once it is registered.
+// The schema hash is ARGV[3] followed by the ID, in the slot of the other keys. Returns {0, id} when the subject already
+// has the same definition, {-1, latest} when another version was registered since the compatibility check and
+// {1, version, id} once registered
+const registerSchemaScriptSource = `
+local existing = redis.call("HGET", KEYS[3], ARGV[1])
+if existing then
+	return {0, tonumber(existing)}
+end
+local latest = tonumber(redis.call("HGET", KEYS[1], "latest_version") or "0")
+if latest ~= tonumber(ARGV[2]) then
+	return {-1, latest}
+end
+local id = redis.call("INCR", KEYS[4])
+local version = latest + 1
+redis.call("HSET", ARGV[3] .. id, "version", version, unpack(ARGV, 4))
+redis.call("HSET", KEYS[2], version, id)
+redis.call("HSET", KEYS[3], ARGV[1], id)
+redis.call("HSET", KEYS[1], "latest_version", version)
+return {1, version, id}
+`
+
+var registerSchemaScript = redis.NewScript(registerSchemaScriptSource)
+
+type Schema struct {
+	Id      int64
+	Subject string
+	Version int64
+	Type    string
+	// JSON Schema or Avro schema document, or a serialized protobuf FileDescriptorSet
+	Definition []byte
+	// Message type payloads of protobuf schemas are decoded as
+	MessageName string
+	CreatedAt   int64
+}
+
+type RegisterSchemaParameters struct {
+	Subject     string
+	Type        string
+	Definition  []byte
+	MessageName string
+}
+
+type SchemaRegistryService interface {
+	// Register a schema as the next version of a subject, returns the existing schema when the subject already has the same definition
+	RegisterSchema(params *RegisterSchemaParameters) (*Schema, error)
+	// Get a schema by ID
+	GetSchema(id int64) (*Schema, error)
+	// Get a version of the schema of a subject, version 0 gets the latest version
+	GetSubjectSchema(subject string, version int64) (*Schema, error)
+	// Get the compatibility mode new versions of a subject are checked with
+	GetCompatibility(subject string) (string, error)
+	// Set the compatibility mode new versions of a subject are checked with
+	SetCompatibility(subject string, compatibility string) error
+	// Validate a message payload against a schema
+	ValidateMessage(value *Schema, payload []byte) error
+}
+
+type SchemaRegistryServiceImpl struct {
+	Ctx    context.Context
+	Client RedisStreamClient
+	Logger logging.LoggerContract
+	// Compiled schemas by ID, schemas never change once registered
+	validators map[int64]schema.Validator
+	mu         sync.Mutex
+}
+
+func NewSchemaRegistryService(ctx context.Context, client RedisStreamClient, logger logging.LoggerContract) SchemaRegistryService {
+	return &SchemaRegistryServiceImpl{
+		Ctx:        ctx,
+		Client:     client,
+		Logger:     logger,
+		validators: make(map[int64]schema.Validator),
+	}
+}
+
+func (p *RegisterSchemaParameters) Validate() error {
+	if p.Subject == "" {
+		return fmt.Errorf("subject is required")
+	}
+
+	if _, err := schema.Compile(p.GetDefinition()); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (p *RegisterSchemaParameters) GetDefinition() *schema.Definition {
+	return &schema.Definition{Type: p.Type, Schema: p.Definition, MessageName: p.MessageName}
+}
+
+// Identifies a definition within a subject so registering it again returns the existing schema
+func (p *RegisterSchemaParameters) GetFingerprint() string {
+	hash := sha256.New()
+	hash.Write([]byte(p.Type + "\x00" + p.MessageName + "\x00"))
+	hash.Write(p.Definition)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func (s *Schema) GetDefinition() *schema.Definition {
+	return &schema.Definition{Type: s.Type, Schema: s.Definition, MessageName: s.MessageName}
+}
+
+func GetSchemaKey(id int64) string {
+	return GetSchemaKeyPrefix() + strconv.FormatInt(id, 10)
+}
+
+// Gets the prefix schema hashes are stored under, followed by the schema ID
+func GetSchemaKeyPrefix() string {
+	return SCHEMA_KEY_PREFIX + "schema:"
+}
+
+func GetSubjectKey(subject string) string {
+	return SCHEMA_KEY_PREFIX + "subject:" + subject
+}
+
+func GetSubjectVersionsKey(subject string) string {
+	return GetSubjectKey(subject) + ":versions"
+}
+
+func GetSubjectFingerprintsKey(subject string) string {
+	return GetSubjectKey(subject) + ":fingerprints"
+}
+
+func (s *SchemaRegistryServiceImpl) RegisterSchema(params *RegisterSchemaParameters) (*Schema, error) {
+	fingerprint := params.GetFingerprint()
+
+	for attempt := 0; attempt < SCHEMA_REGISTER_ATTEMPTS; attempt++ {
+		existingId, err := s.Client.HGet(s.Ctx, GetSubjectFingerprintsKey(params.Subject), fingerprint).Int64()
+		if err == nil {
+			return s.GetSchema(existingId)
+		}
+		if err != redis.Nil {
+			return nil, fmt.Errorf("failed to look up schema of subject %s: %w", params.Subject, err)
+		}
+
+		latest, err := s.GetSubjectSchema(params.Subject, 0)
+		if err != nil {
+			if _, ok := err.(*RedisSubjectNotFoundError); !ok {
+				return nil, err
+			}
+		}
+
+		latestVersion := int64(0)
+		if latest != nil {
+			compatibility, err := s.GetCompatibility(params.Subject)
+			if err != nil {
+				return nil, err
+			}
+
+			if err := schema.CheckCompatibility(compatibility, params.GetDefinition(), latest.GetDefinition()); err != nil {
+				return nil, SchemaIncompatibleError(params.Subject, compatibility, err)
+			}
+			latestVersion = latest.Version
+		}
+
+		createdAt := time.Now().UnixMilli()
+		keys := []string{
+			GetSubjectKey(params.Subject),
+			GetSubjectVersionsKey(params.Subject),
+			GetSubjectFingerprintsKey(params.Subject),
+			SCHEMA_ID_COUNTER_KEY,
+		}
+		args := []interface{}{
+			fingerprint, latestVersion, GetSchemaKeyPrefix(),
+			"subject", params.Subject,
+			"type", params.Type,
+			"schema", params.Definition,
+			"message_name", params.MessageName,
+			"created_at", createdAt,
+		}
+
+		reply, err := registerSchemaScript.Run(s.Ctx, s.Client, keys, args...).Int64Slice()
+		if err != nil {
+			return nil, fmt.Errorf("failed to register schema of subject %s: %w", params.Subject, err)
+		}
+
+		if len(reply) < 2 || (reply[0] == 1 && len(reply) != 3) {
+			return nil, fmt.Errorf("unexpected register schema reply: %v", reply)
+		}
+
+		switch reply[0] {
+		case 0:
+			return s.GetSchema(reply[1])
+		case 1:
+			id := reply[2]
+			s.Logger.Info("Registered schema", zap.String("subject", params.Subject), zap.Int64("id", id), zap.Int64("version", reply[1]))
+			return &Schema{
+				Id:          id,
+				Subject:     params.Subject,
+				Version:     reply[1],
+				Type:        params.Type,
+				Definition:  params.Definition,
+				MessageName: params.MessageName,
+				CreatedAt:   createdAt,
+			}, nil
+		}
+
+		// Another version was registered after the compatibility check, check against it instead
+		s.Logger.Debug("Subject changed while registering schema, retrying", zap.String("subject", params.Subject), zap.Int64("latest_version", reply[1]))
+	}
+
+	return nil, fmt.Errorf("failed to register schema of subject %s: subject kept changing", params.Subject)
+}
+
+func (s *SchemaRegistryServiceImpl) GetSchema(id int64) (*Schema, error) {
+	values, err := s.Client.HGetAll(s.Ctx, GetSchemaKey(id)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schema %d: %w", id, err)
+	}
+
+	if len(values) == 0 {
+		return nil, SchemaNotFoundError(id)
+	}
+
+	return ParseSchema(id, values)
+}
+
+func (s *SchemaRegistryServiceImpl) GetSubjectSchema(subject string, version int64) (*Schema, error) {
+	if version == 0 {
+		latest, err := s.Client.HGet(s.Ctx, GetSubjectKey(subject), "latest_version").Int64()
+		if err != nil {
+			if err == redis.Nil {
+				return nil, SubjectNotFoundError(subject, version)
+			}
+			return nil, fmt.Errorf("failed to get latest version of subject %s: %w", subject, err)
+		}
+		version = latest
+	}
+
+	id, err := s.Client.HGet(s.Ctx, GetSubjectVersionsKey(subject), strconv.FormatInt(version, 10)).Int64()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, SubjectNotFoundError(subject, version)
+		}
+		return nil, fmt.Errorf("failed to get version %d of subject %s: %w", version, subject, err)
+	}
+
+	return s.GetSchema(id)
+}
+
+func (s *SchemaRegistryServiceImpl) GetCompatibility(subject string) (string, error) {
+	compatibility, err := s.Client.HGet(s.Ctx, GetSubjectKey(subject), "compatibility").Result()
+	if err != nil {
+		if err == redis.Nil {
+			return DEFAULT_SCHEMA_COMPATIBILITY, nil
+		}
+		return "", fmt.Errorf("failed to get compatibility of subject %s: %w", subject, err)
+	}
+
+	return compatibility, nil
+}
+
+func (s *SchemaRegistryServiceImpl) SetCompatibility(subject string, compatibility string) error {
+	err := s.Client.HSet(s.Ctx, GetSubjectKey(subject), "compatibility", compatibility).Err()
+	if err != nil {
+		return fmt.Errorf("failed to set compatibility of subject %s: %w", subject, err)
+	}
+
+	s.Logger.Info("Set subject compatibility", zap.String("subject", subject), zap.String("compatibility", compatibility))
+	return nil
+}
+
+func (s *SchemaRegistryServiceImpl) ValidateMessage(value *Schema, payload []byte) error {
+	s.mu.Lock()
+	validator, ok := s.validators[value.Id]
+	s.mu.Unlock()
+
+	if !ok {
+		compiled, err := schema.Compile(value.GetDefinition())
+		if err != nil {
+			return fmt.Errorf("failed to compile schema %d: %w", value.Id, err)
+		}
+
+		s.mu.Lock()
+		s.validators[value.Id] = compiled
+		s.mu.Unlock()
+		validator = compiled
+	}
+
+	return validator.Validate(payload)
+}
+
+// Parses the hash a schema is stored in
+func ParseSchema(id int64, values map[string]string) (*Schema, error) {
+	version, err := strconv.ParseInt(values["version"], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid version of schema %d: %w", id, err)
+	}
+
+	createdAt, _ := strconv.ParseInt(values["created_at"], 10, 64)
+
+	return &Schema{
+		Id:          id,
+		Subject:     values["subject"],
+		Version:     version,
+		Type:        values["type"],
+		Definition:  []byte(values["schema"]),
+		MessageName: values["message_name"],
+		CreatedAt:   createdAt,
+	}, nil
+}
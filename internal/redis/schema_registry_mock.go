@@ -0,0 +1,52 @@
+package redis
+
+import (
+	"github.com/stretchr/testify/mock"
+)
+
+type SchemaRegistryServiceMock struct {
+	mock.Mock
+}
+
+func NewSchemaRegistryServiceMock() *SchemaRegistryServiceMock {
+	return &SchemaRegistryServiceMock{}
+}
+
+func (m *SchemaRegistryServiceMock) RegisterSchema(params *RegisterSchemaParameters) (*Schema, error) {
+	args := m.Called(params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Schema), args.Error(1)
+}
+
+func (m *SchemaRegistryServiceMock) GetSchema(id int64) (*Schema, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Schema), args.Error(1)
+}
+
+func (m *SchemaRegistryServiceMock) GetSubjectSchema(subject string, version int64) (*Schema, error) {
+	args := m.Called(subject, version)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Schema), args.Error(1)
+}
+
+func (m *SchemaRegistryServiceMock) GetCompatibility(subject string) (string, error) {
+	args := m.Called(subject)
+	return args.String(0), args.Error(1)
+}
+
+func (m *SchemaRegistryServiceMock) SetCompatibility(subject string, compatibility string) error {
+	args := m.Called(subject, compatibility)
+	return args.Error(0)
+}
+
+func (m *SchemaRegistryServiceMock) ValidateMessage(value *Schema, payload []byte) error {
+	args := m.Called(value, payload)
+	return args.Error(0)
+}
@@ -0,0 +1,109 @@
+package redis
+
+import (
+	"context"
+	"testing"
+
+	rdb "github.com/redis/go-redis/v9"
+	"github.com/streamweaverio/broker/internal/schema"
+	"github.com/streamweaverio/broker/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const ordersSchemaV1 = `{"type": "object", "properties": {"id": {"type": "integer"}}, "required": ["id"]}`
+
+func setupSchemaRegistryService() (SchemaRegistryService, *MockRedisClient) {
+	client := &MockRedisClient{}
+	return NewSchemaRegistryService(context.Background(), client, testutils.NewMockLogger()), client
+}
+
+func TestSchemaRegistryService_RegisterSchema(t *testing.T) {
+	storedV1 := map[string]string{
+		"subject":    "orders",
+		"version":    "1",
+		"type":       schema.TYPE_JSON,
+		"schema":     ordersSchemaV1,
+		"created_at": "1700000000000",
+	}
+
+	t.Run("Register the first version of a subject", func(t *testing.T) {
+		service, client := setupSchemaRegistryService()
+		params := &RegisterSchemaParameters{Subject: "orders", Type: schema.TYPE_JSON, Definition: []byte(ordersSchemaV1)}
+
+		client.On("HGet", mock.Anything, "{streamweaver_schema}:subject:orders:fingerprints", params.GetFingerprint()).Return(rdb.NewStringResult("", rdb.Nil))
+		client.On("HGet", mock.Anything, "{streamweaver_schema}:subject:orders", "latest_version").Return(rdb.NewStringResult("", rdb.Nil))
+		// The ID is allocated by the script once the version is registered
+		client.On("Eval", mock.Anything, registerSchemaScriptSource, []string{
+			"{streamweaver_schema}:subject:orders",
+			"{streamweaver_schema}:subject:orders:versions",
+			"{streamweaver_schema}:subject:orders:fingerprints",
+			SCHEMA_ID_COUNTER_KEY,
+		}, mock.MatchedBy(func(args []interface{}) bool {
+			return args[0] == params.GetFingerprint() && args[1] == int64(0) && args[2] == "{streamweaver_schema}:schema:"
+		})).Return(rdb.NewCmdResult([]interface{}{int64(1), int64(1), int64(1)}, nil))
+
+		value, err := service.RegisterSchema(params)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), value.Id)
+		assert.Equal(t, int64(1), value.Version)
+		client.AssertExpectations(t)
+	})
+
+	t.Run("Return the existing schema when the definition is registered again", func(t *testing.T) {
+		service, client := setupSchemaRegistryService()
+		params := &RegisterSchemaParameters{Subject: "orders", Type: schema.TYPE_JSON, Definition: []byte(ordersSchemaV1)}
+
+		client.On("HGet", mock.Anything, "{streamweaver_schema}:subject:orders:fingerprints", params.GetFingerprint()).Return(rdb.NewStringResult("1", nil))
+		client.On("HGetAll", mock.Anything, "{streamweaver_schema}:schema:1").Return(rdb.NewMapStringStringResult(storedV1, nil))
+
+		value, err := service.RegisterSchema(params)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &Schema{Id: 1, Subject: "orders", Version: 1, Type: schema.TYPE_JSON, Definition: []byte(ordersSchemaV1), CreatedAt: 1700000000000}, value)
+		client.AssertNotCalled(t, "Eval", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Reject a schema incompatible with the latest version", func(t *testing.T) {
+		service, client := setupSchemaRegistryService()
+		params := &RegisterSchemaParameters{
+			Subject:    "orders",
+			Type:       schema.TYPE_JSON,
+			Definition: []byte(`{"type": "object", "properties": {"id": {"type": "integer"}}, "required": ["id", "total"]}`),
+		}
+
+		client.On("HGet", mock.Anything, "{streamweaver_schema}:subject:orders:fingerprints", params.GetFingerprint()).Return(rdb.NewStringResult("", rdb.Nil))
+		client.On("HGet", mock.Anything, "{streamweaver_schema}:subject:orders", "latest_version").Return(rdb.NewStringResult("1", nil))
+		client.On("HGet", mock.Anything, "{streamweaver_schema}:subject:orders:versions", "1").Return(rdb.NewStringResult("1", nil))
+		client.On("HGetAll", mock.Anything, "{streamweaver_schema}:schema:1").Return(rdb.NewMapStringStringResult(storedV1, nil))
+		client.On("HGet", mock.Anything, "{streamweaver_schema}:subject:orders", "compatibility").Return(rdb.NewStringResult("", rdb.Nil))
+
+		_, err := service.RegisterSchema(params)
+
+		assert.IsType(t, &RedisSchemaIncompatibleError{}, err)
+		assert.Equal(t, DEFAULT_SCHEMA_COMPATIBILITY, err.(*RedisSchemaIncompatibleError).Compatibility)
+		client.AssertNotCalled(t, "Eval", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestSchemaRegistryService_GetSchema(t *testing.T) {
+	service, client := setupSchemaRegistryService()
+	client.On("HGetAll", mock.Anything, "{streamweaver_schema}:schema:9").Return(rdb.NewMapStringStringResult(map[string]string{}, nil))
+	client.On("HGet", mock.Anything, "{streamweaver_schema}:subject:orders:versions", "3").Return(rdb.NewStringResult("", rdb.Nil))
+
+	_, err := service.GetSchema(9)
+	assert.Equal(t, SchemaNotFoundError(9), err)
+
+	_, err = service.GetSubjectSchema("orders", 3)
+	assert.Equal(t, SubjectNotFoundError("orders", 3), err)
+}
+
+func TestSchemaRegistryService_ValidateMessage(t *testing.T) {
+	service, _ := setupSchemaRegistryService()
+	value := &Schema{Id: 1, Subject: "orders", Version: 1, Type: schema.TYPE_JSON, Definition: []byte(ordersSchemaV1)}
+
+	assert.NoError(t, service.ValidateMessage(value, []byte(`{"id": 1}`)))
+	assert.Error(t, service.ValidateMessage(value, []byte(`{"id": "1"}`)))
+	assert.Len(t, service.(*SchemaRegistryServiceImpl).validators, 1)
+}
@@ -10,12 +10,30 @@ type RedisStreamClient interface {
 	XAdd(ctx context.Context, args *rdb.XAddArgs) *rdb.StringCmd
 	XDel(ctx context.Context, stream string, ids ...string) *rdb.IntCmd
 	XInfoStream(ctx context.Context, stream string) *rdb.XInfoStreamCmd
+	MemoryUsage(ctx context.Context, key string, samples ...int) *rdb.IntCmd
 	XTrimMinID(ctx context.Context, stream string, minID string) *rdb.IntCmd
 	XRange(ctx context.Context, stream, start, stop string) *rdb.XMessageSliceCmd
 	XRangeN(ctx context.Context, stream, start, stop string, count int64) *rdb.XMessageSliceCmd
+	XRead(ctx context.Context, a *rdb.XReadArgs) *rdb.XStreamSliceCmd
+	XGroupCreate(ctx context.Context, stream, group, start string) *rdb.StatusCmd
+	XGroupDestroy(ctx context.Context, stream, group string) *rdb.IntCmd
+	XReadGroup(ctx context.Context, a *rdb.XReadGroupArgs) *rdb.XStreamSliceCmd
+	XAck(ctx context.Context, stream, group string, ids ...string) *rdb.IntCmd
+	XPendingExt(ctx context.Context, a *rdb.XPendingExtArgs) *rdb.XPendingExtCmd
+	XClaim(ctx context.Context, a *rdb.XClaimArgs) *rdb.XMessageSliceCmd
+	XClaimJustID(ctx context.Context, a *rdb.XClaimArgs) *rdb.StringSliceCmd
+	XAutoClaimJustID(ctx context.Context, a *rdb.XAutoClaimArgs) *rdb.XAutoClaimJustIDCmd
 	HSet(ctx context.Context, key string, values ...interface{}) *rdb.IntCmd
 	HSetNX(ctx context.Context, key, field string, value interface{}) *rdb.BoolCmd
+	HGet(ctx context.Context, key, field string) *rdb.StringCmd
 	HGetAll(ctx context.Context, key string) *rdb.MapStringStringCmd
 	SAdd(ctx context.Context, key string, members ...interface{}) *rdb.IntCmd
 	SMembers(ctx context.Context, key string) *rdb.StringSliceCmd
+	SRem(ctx context.Context, key string, members ...interface{}) *rdb.IntCmd
+	Del(ctx context.Context, keys ...string) *rdb.IntCmd
+	Get(ctx context.Context, key string) *rdb.StringCmd
+	Incr(ctx context.Context, key string) *rdb.IntCmd
+	// Scripts are run with EVALSHA through rdb.Script, falling back to EVAL when Redis does not have them cached
+	rdb.Scripter
+	Pipelined(ctx context.Context, fn func(rdb.Pipeliner) error) ([]rdb.Cmder, error)
 }
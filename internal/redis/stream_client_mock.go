@@ -11,6 +11,15 @@ type MockRedisClient struct {
 	mock.Mock
 }
 
+// Error replied by the mocked Redis server, as opposed to a network or client error
+type MockRedisError string
+
+func (e MockRedisError) Error() string {
+	return string(e)
+}
+
+func (e MockRedisError) RedisError() {}
+
 func (m *MockRedisClient) XAdd(ctx context.Context, params *rdb.XAddArgs) *rdb.StringCmd {
 	args := m.Called(ctx, params)
 	return args.Get(0).(*rdb.StringCmd)
@@ -26,6 +35,11 @@ func (m *MockRedisClient) XInfoStream(ctx context.Context, stream string) *rdb.X
 	return args.Get(0).(*rdb.XInfoStreamCmd)
 }
 
+func (m *MockRedisClient) MemoryUsage(ctx context.Context, key string, samples ...int) *rdb.IntCmd {
+	args := m.Called(ctx, key, samples)
+	return args.Get(0).(*rdb.IntCmd)
+}
+
 func (m *MockRedisClient) XTrimMinID(ctx context.Context, stream string, minID string) *rdb.IntCmd {
 	args := m.Called(ctx, stream, minID)
 	return args.Get(0).(*rdb.IntCmd)
@@ -41,6 +55,51 @@ func (m *MockRedisClient) XRangeN(ctx context.Context, stream, start, stop strin
 	return args.Get(0).(*rdb.XMessageSliceCmd)
 }
 
+func (m *MockRedisClient) XRead(ctx context.Context, a *rdb.XReadArgs) *rdb.XStreamSliceCmd {
+	args := m.Called(ctx, a)
+	return args.Get(0).(*rdb.XStreamSliceCmd)
+}
+
+func (m *MockRedisClient) XGroupCreate(ctx context.Context, stream, group, start string) *rdb.StatusCmd {
+	args := m.Called(ctx, stream, group, start)
+	return args.Get(0).(*rdb.StatusCmd)
+}
+
+func (m *MockRedisClient) XGroupDestroy(ctx context.Context, stream, group string) *rdb.IntCmd {
+	args := m.Called(ctx, stream, group)
+	return args.Get(0).(*rdb.IntCmd)
+}
+
+func (m *MockRedisClient) XReadGroup(ctx context.Context, a *rdb.XReadGroupArgs) *rdb.XStreamSliceCmd {
+	args := m.Called(ctx, a)
+	return args.Get(0).(*rdb.XStreamSliceCmd)
+}
+
+func (m *MockRedisClient) XAck(ctx context.Context, stream, group string, ids ...string) *rdb.IntCmd {
+	args := m.Called(ctx, stream, group, ids)
+	return args.Get(0).(*rdb.IntCmd)
+}
+
+func (m *MockRedisClient) XPendingExt(ctx context.Context, a *rdb.XPendingExtArgs) *rdb.XPendingExtCmd {
+	args := m.Called(ctx, a)
+	return args.Get(0).(*rdb.XPendingExtCmd)
+}
+
+func (m *MockRedisClient) XClaim(ctx context.Context, a *rdb.XClaimArgs) *rdb.XMessageSliceCmd {
+	args := m.Called(ctx, a)
+	return args.Get(0).(*rdb.XMessageSliceCmd)
+}
+
+func (m *MockRedisClient) XClaimJustID(ctx context.Context, a *rdb.XClaimArgs) *rdb.StringSliceCmd {
+	args := m.Called(ctx, a)
+	return args.Get(0).(*rdb.StringSliceCmd)
+}
+
+func (m *MockRedisClient) XAutoClaimJustID(ctx context.Context, a *rdb.XAutoClaimArgs) *rdb.XAutoClaimJustIDCmd {
+	args := m.Called(ctx, a)
+	return args.Get(0).(*rdb.XAutoClaimJustIDCmd)
+}
+
 func (m *MockRedisClient) HSet(ctx context.Context, key string, values ...interface{}) *rdb.IntCmd {
 	args := m.Called(ctx, key, values)
 	return args.Get(0).(*rdb.IntCmd)
@@ -51,6 +110,11 @@ func (m *MockRedisClient) HSetNX(ctx context.Context, key, field string, value i
 	return args.Get(0).(*rdb.BoolCmd)
 }
 
+func (m *MockRedisClient) HGet(ctx context.Context, key, field string) *rdb.StringCmd {
+	args := m.Called(ctx, key, field)
+	return args.Get(0).(*rdb.StringCmd)
+}
+
 func (m *MockRedisClient) HGetAll(ctx context.Context, key string) *rdb.MapStringStringCmd {
 	args := m.Called(ctx, key)
 	return args.Get(0).(*rdb.MapStringStringCmd)
@@ -65,3 +129,74 @@ func (m *MockRedisClient) SMembers(ctx context.Context, key string) *rdb.StringS
 	args := m.Called(key)
 	return args.Get(0).(*rdb.StringSliceCmd)
 }
+
+func (m *MockRedisClient) SRem(ctx context.Context, key string, members ...interface{}) *rdb.IntCmd {
+	args := m.Called(ctx, key, members)
+	return args.Get(0).(*rdb.IntCmd)
+}
+
+func (m *MockRedisClient) Del(ctx context.Context, keys ...string) *rdb.IntCmd {
+	args := m.Called(ctx, keys)
+	return args.Get(0).(*rdb.IntCmd)
+}
+
+func (m *MockRedisClient) Get(ctx context.Context, key string) *rdb.StringCmd {
+	args := m.Called(ctx, key)
+	return args.Get(0).(*rdb.StringCmd)
+}
+
+func (m *MockRedisClient) Incr(ctx context.Context, key string) *rdb.IntCmd {
+	args := m.Called(ctx, key)
+	return args.Get(0).(*rdb.IntCmd)
+}
+
+func (m *MockRedisClient) Eval(ctx context.Context, script string, keys []string, params ...interface{}) *rdb.Cmd {
+	args := m.Called(ctx, script, keys, params)
+	return args.Get(0).(*rdb.Cmd)
+}
+
+func (m *MockRedisClient) EvalRO(ctx context.Context, script string, keys []string, params ...interface{}) *rdb.Cmd {
+	return m.Eval(ctx, script, keys, params...)
+}
+
+// The mocked client never has a script cached, so rdb.Script falls back to Eval with the script source
+func (m *MockRedisClient) EvalSha(ctx context.Context, sha1 string, keys []string, params ...interface{}) *rdb.Cmd {
+	return rdb.NewCmdResult(nil, MockRedisError("NOSCRIPT No matching script. Please use EVAL."))
+}
+
+func (m *MockRedisClient) EvalShaRO(ctx context.Context, sha1 string, keys []string, params ...interface{}) *rdb.Cmd {
+	return m.EvalSha(ctx, sha1, keys, params...)
+}
+
+func (m *MockRedisClient) ScriptExists(ctx context.Context, hashes ...string) *rdb.BoolSliceCmd {
+	return rdb.NewBoolSliceResult(make([]bool, len(hashes)), nil)
+}
+
+func (m *MockRedisClient) ScriptLoad(ctx context.Context, script string) *rdb.StringCmd {
+	args := m.Called(ctx, script)
+	return args.Get(0).(*rdb.StringCmd)
+}
+
+// Runs fn against a pipeline whose commands are answered by the mocked client methods, returns the mocked execution error
+func (m *MockRedisClient) Pipelined(ctx context.Context, fn func(rdb.Pipeliner) error) ([]rdb.Cmder, error) {
+	pipe := &MockPipeliner{Client: m}
+	if err := fn(pipe); err != nil {
+		return nil, err
+	}
+
+	args := m.Called(ctx)
+	return pipe.Cmds, args.Error(0)
+}
+
+// Pipeliner forwarding queued commands to the mocked client, commands it does not implement panic
+type MockPipeliner struct {
+	rdb.Pipeliner
+	Client *MockRedisClient
+	Cmds   []rdb.Cmder
+}
+
+func (p *MockPipeliner) XAdd(ctx context.Context, a *rdb.XAddArgs) *rdb.StringCmd {
+	cmd := p.Client.XAdd(ctx, a)
+	p.Cmds = append(p.Cmds, cmd)
+	return cmd
+}
@@ -3,6 +3,7 @@ package redis
 import (
 	"bytes"
 	"fmt"
+	"strings"
 )
 
 // Converts a slice of byte slices into a slice of maps that can be used with Redis.
@@ -29,3 +30,57 @@ func ByteSliceToRedisMessageMapSlice(values [][]byte) []map[string]interface{} {
 	}
 	return result
 }
+
+// Content of a stream message split into its payload, headers and remaining fields
+type StreamMessageContent struct {
+	Payload []byte
+	Headers map[string]string
+	// Fields that are neither the payload nor a header, every field of key_value messages
+	Fields map[string]string
+}
+
+// Converts published content and headers into stream message fields of the given message format
+func ToRedisMessages(format string, contents [][]byte, headers []map[string]string) []map[string]interface{} {
+	var messages []map[string]interface{}
+	if format == MESSAGE_FORMAT_KEY_VALUE {
+		messages = ByteSliceToRedisMessageMapSlice(contents)
+	} else {
+		messages = make([]map[string]interface{}, len(contents))
+		for i, content := range contents {
+			messages[i] = make(map[string]interface{})
+			// Messages without content but with headers, such as tombstones, only hold their headers
+			if len(content) > 0 || i >= len(headers) || len(headers[i]) == 0 {
+				messages[i][MESSAGE_PAYLOAD_FIELD] = string(content)
+			}
+		}
+	}
+
+	for i := range min(len(headers), len(messages)) {
+		for name, value := range headers[i] {
+			messages[i][MESSAGE_HEADER_PREFIX+name] = value
+		}
+	}
+
+	return messages
+}
+
+// Splits the fields of a stream message into its payload, headers and remaining fields
+func GetStreamMessageContent(values map[string]interface{}) *StreamMessageContent {
+	content := &StreamMessageContent{
+		Headers: make(map[string]string),
+		Fields:  make(map[string]string),
+	}
+
+	for key, value := range values {
+		switch {
+		case key == MESSAGE_PAYLOAD_FIELD:
+			content.Payload = []byte(fmt.Sprint(value))
+		case strings.HasPrefix(key, MESSAGE_HEADER_PREFIX):
+			content.Headers[strings.TrimPrefix(key, MESSAGE_HEADER_PREFIX)] = fmt.Sprint(value)
+		default:
+			content.Fields[key] = fmt.Sprint(value)
+		}
+	}
+
+	return content
+}
@@ -0,0 +1,46 @@
+package redis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToRedisMessages(t *testing.T) {
+	contents := [][]byte{[]byte(`{"name": "login"}`), []byte("")}
+	headers := []map[string]string{{"content-type": "application/json"}, {"key": "user-1"}}
+
+	t.Run("Store raw content untouched", func(t *testing.T) {
+		messages := ToRedisMessages(MESSAGE_FORMAT_RAW, contents, headers)
+
+		assert.Equal(t, []map[string]interface{}{
+			{"payload": `{"name": "login"}`, "header:content-type": "application/json"},
+			// Empty content with headers is a tombstone holding only its headers
+			{"header:key": "user-1"},
+		}, messages)
+	})
+
+	t.Run("Keep an empty payload field for messages without headers", func(t *testing.T) {
+		messages := ToRedisMessages(MESSAGE_FORMAT_RAW, [][]byte{[]byte("")}, nil)
+
+		assert.Equal(t, []map[string]interface{}{{"payload": ""}}, messages)
+	})
+
+	t.Run("Split key_value content into fields", func(t *testing.T) {
+		messages := ToRedisMessages(MESSAGE_FORMAT_KEY_VALUE, [][]byte{[]byte("event=login user=1")}, []map[string]string{{"trace": "abc"}})
+
+		assert.Equal(t, []map[string]interface{}{{"event": "login", "user": "1", "header:trace": "abc"}}, messages)
+	})
+}
+
+func TestGetStreamMessageContent(t *testing.T) {
+	content := GetStreamMessageContent(map[string]interface{}{
+		"payload":             "\x00\x01binary",
+		"header:content-type": "application/octet-stream",
+		"source":              "legacy",
+	})
+
+	assert.Equal(t, []byte("\x00\x01binary"), content.Payload)
+	assert.Equal(t, map[string]string{"content-type": "application/octet-stream"}, content.Headers)
+	assert.Equal(t, map[string]string{"source": "legacy"}, content.Fields)
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/redis/go-redis/v9"
 	"github.com/streamweaverio/broker/internal/logging"
 	"github.com/streamweaverio/broker/pkg/utils"
 	"go.uber.org/zap"
@@ -17,8 +19,102 @@ type StreamMetadataService interface {
 	GetStreamMetadata(streamHash string) (*StreamMetadata, error)
 	ListStreams() ([]string, error)
 	WriteStreamMetadata(value *StreamMetadata) error
+	// Write the dead letter policy fields of an existing stream without touching the rest of its metadata
+	WriteDeadLetterPolicy(streamName string, maxDeliveries int64, deadLetterStream string) error
+	// Set the updated metadata fields of an existing stream and move it to the cleanup bucket of a changed cleanup policy
+	// atomically, without reading the metadata first
+	UpdateStreamMetadata(update *StreamMetadataUpdate) error
+	// Get the ID of the last message of a stream written to storage, empty when nothing was archived yet
+	GetArchiveWatermark(streamName string) (string, error)
+	// Advance the ID of the last message of a stream written to storage from the previous ID, fails when another
+	// archiver moved the watermark in the meantime or a newer fencing token was recorded. A zero token is not fenced
+	SetArchiveWatermark(streamName string, previousId string, messageId string, token int64) error
+	// Record the fencing token on the stream's metadata before writing to storage, fails when a newer token was recorded
+	CheckFencingToken(streamName string, token int64) error
 }
 
+// Sets metadata fields of an existing stream and moves the stream hash from every cleanup bucket to the new one.
+// KEYS[1] is the metadata hash, followed by the new bucket and every bucket when the cleanup policy changes. ARGV[1] is
+// the stream hash and ARGV[2] the number of compaction default field value pairs, followed by them and the field value
+// pairs to set. Compaction fields of a compacted stream that are empty or zero after the update get their default.
+// Returns 0 when the stream has no metadata and -1 without changing anything when a compacted stream has no key field
+const streamMetadataUpdateScriptSource = `
+if redis.call("EXISTS", KEYS[1]) == 0 then
+	return 0
+end
+local first = 3 + tonumber(ARGV[2]) * 2
+local fields = {}
+for i = first, #ARGV, 2 do
+	fields[ARGV[i]] = ARGV[i + 1]
+end
+local function current(field)
+	return fields[field] or redis.call("HGET", KEYS[1], field) or ""
+end
+if current("cleanup_policy") == "compact" then
+	for i = 3, first - 1, 2 do
+		local value = current(ARGV[i])
+		if value == "" or value == "0" then
+			fields[ARGV[i]] = ARGV[i + 1]
+		end
+	end
+	if current("compaction_key_field") == "" then
+		return -1
+	end
+end
+local args = {}
+for field, value in pairs(fields) do
+	table.insert(args, field)
+	table.insert(args, value)
+end
+if #args > 0 then
+	redis.call("HSET", KEYS[1], unpack(args))
+end
+if #KEYS > 1 then
+	for i = 3, #KEYS do
+		redis.call("SREM", KEYS[i], ARGV[1])
+	end
+	redis.call("SADD", KEYS[2], ARGV[1])
+end
+return 1
+`
+
+// Rejects a fencing token ARGV[1] older than the one in field ARGV[2] of the metadata hash KEYS[1] with -1 and records
+// it otherwise, a zero token is not fenced
+const metadataFenceCheck = `
+local token = tonumber(ARGV[1])
+if token > 0 then
+	local seen = tonumber(redis.call("HGET", KEYS[1], ARGV[2]) or "0")
+	if token < seen then
+		return -1
+	end
+	redis.call("HSET", KEYS[1], ARGV[2], token)
+end
+`
+
+// Records the fencing token of an existing stream. Returns 0 when the stream has no metadata
+const fencingTokenScriptSource = `
+if redis.call("EXISTS", KEYS[1]) == 0 then
+	return 0
+end
+` + metadataFenceCheck + `
+return 1
+`
+
+// Sets the archive watermark field ARGV[3] of the metadata hash KEYS[1] to ARGV[5] only while it still holds ARGV[4],
+// a missing watermark matches an empty ARGV[4]. Returns 0 when the watermark moved
+const archiveWatermarkScriptSource = metadataFenceCheck + `
+local current = redis.call("HGET", KEYS[1], ARGV[3]) or ""
+if current ~= ARGV[4] then
+	return 0
+end
+redis.call("HSET", KEYS[1], ARGV[3], ARGV[5])
+return 1
+`
+
+var streamMetadataUpdateScript = redis.NewScript(streamMetadataUpdateScriptSource)
+var fencingTokenScript = redis.NewScript(fencingTokenScriptSource)
+var archiveWatermarkScript = redis.NewScript(archiveWatermarkScriptSource)
+
 type StreamMetadataServiceImpl struct {
 	Ctx    context.Context
 	Logger logging.LoggerContract
@@ -38,36 +134,37 @@ func (s *StreamMetadataServiceImpl) WriteStreamMetadata(value *StreamMetadata) e
 	key := fmt.Sprintf("%s%s", STREAM_META_DATA_PREFIX, streamHash)
 	s.Logger.Debug("Writing stream metadata to Redis...", zap.String("key", key))
 
-	existingMetadata, err := s.Client.HGetAll(s.Ctx, key).Result()
+	hsetArgs := GetStreamMetadataFields(value)
+
+	// Call HSet with key-value pairs
+	err := s.Client.HSet(s.Ctx, key, hsetArgs...).Err()
 	if err != nil {
-		return fmt.Errorf("failed to get stream metadata: %w", err)
+		s.Logger.Error("Failed to write stream metadata to Redis", zap.String("key", key), zap.Any("metadata", hsetArgs), zap.Error(err))
+		return fmt.Errorf("failed to update stream metadata: %w", err)
 	}
 
-	metadata := map[string]interface{}{
-		"name":           value.Name,
-		"cleanup_policy": value.CleanupPolicy,
-		"max_age":        strconv.FormatInt(value.MaxAge, 10),
-		"updated_at":     strconv.FormatInt(time.Now().Unix(), 10),
+	// The creation time is only set once, without reading the metadata first
+	err = s.Client.HSetNX(s.Ctx, key, "created_at", strconv.FormatInt(value.CreatedAt, 10)).Err()
+	if err != nil {
+		return fmt.Errorf("failed to set stream creation time: %w", err)
 	}
 
-	if len(existingMetadata) == 0 {
-		metadata["created_at"] = strconv.FormatInt(value.CreatedAt, 10)
-	}
+	s.Logger.Debug("Successfully updated stream metadata in Redis", zap.String("key", key), zap.Any("metadata", hsetArgs))
+	return nil
+}
 
-	// Convert map to key-value pairs
-	var hsetArgs []interface{}
-	for k, v := range metadata {
-		hsetArgs = append(hsetArgs, k, v)
-	}
+func (s *StreamMetadataServiceImpl) WriteDeadLetterPolicy(streamName string, maxDeliveries int64, deadLetterStream string) error {
+	key := fmt.Sprintf("%s%s", STREAM_META_DATA_PREFIX, utils.HashString(streamName))
 
-	// Call HSet with key-value pairs
-	err = s.Client.HSet(s.Ctx, key, hsetArgs...).Err()
+	err := s.Client.HSet(s.Ctx, key,
+		"max_deliveries", strconv.FormatInt(maxDeliveries, 10),
+		"dead_letter_stream", deadLetterStream,
+		"updated_at", strconv.FormatInt(time.Now().Unix(), 10),
+	).Err()
 	if err != nil {
-		s.Logger.Error("Failed to write stream metadata to Redis", zap.String("key", key), zap.Any("metadata", metadata), zap.Error(err))
-		return fmt.Errorf("failed to update stream metadata: %w", err)
+		return fmt.Errorf("failed to write dead letter policy: %w", err)
 	}
 
-	s.Logger.Debug("Successfully updated stream metadata in Redis", zap.String("key", key), zap.Any("metadata", metadata))
 	return nil
 }
 
@@ -99,6 +196,84 @@ func (s *StreamMetadataServiceImpl) AddToRegistry(streamName string) error {
 	return nil
 }
 
+// Sets the updated metadata fields of an existing stream and moves it to the cleanup bucket of a changed cleanup policy
+// in one atomic step, so concurrent updates of other fields are kept
+func (s *StreamMetadataServiceImpl) UpdateStreamMetadata(update *StreamMetadataUpdate) error {
+	streamHash := utils.HashString(update.Name)
+	key := fmt.Sprintf("%s%s", STREAM_META_DATA_PREFIX, streamHash)
+	s.Logger.Debug("Updating stream metadata in Redis...", zap.String("key", key))
+
+	keys := []string{key}
+	if update.CleanupPolicy != "" {
+		keys = append(keys, GetCleanupBucketKey(update.CleanupPolicy))
+		keys = append(keys, STREAM_CLEANUP_BUCKETS...)
+	}
+
+	args := []interface{}{streamHash, len(update.CompactionDefaults) / 2}
+	args = append(args, update.CompactionDefaults...)
+	args = append(args, update.Fields...)
+
+	updated, err := streamMetadataUpdateScript.Run(s.Ctx, s.Client, keys, args...).Int64()
+	if err != nil {
+		return fmt.Errorf("failed to update stream metadata: %w", err)
+	}
+
+	switch updated {
+	case 0:
+		return StreamNotFoundError(update.Name)
+	case -1:
+		return fmt.Errorf("compaction key field is required for the compact cleanup policy")
+	}
+
+	if update.CleanupPolicy != "" {
+		// Streams created before the buckets were moved may still be in a legacy bucket
+		for _, bucket := range LEGACY_STREAM_CLEANUP_BUCKETS {
+			err := s.Client.SRem(s.Ctx, bucket, streamHash).Err()
+			if err != nil {
+				return fmt.Errorf("failed to remove stream from legacy cleanup bucket %s: %w", bucket, err)
+			}
+		}
+	}
+
+	s.Logger.Debug("Successfully updated stream metadata in Redis", zap.String("key", key), zap.String("cleanup_policy", update.CleanupPolicy))
+	return nil
+}
+
+// Gets the metadata fields written for a stream, in a fixed order so the HSET arguments are deterministic
+func GetStreamMetadataFields(value *StreamMetadata) []interface{} {
+	return []interface{}{
+		"name", value.Name,
+		"cleanup_policy", value.CleanupPolicy,
+		"max_age", strconv.FormatInt(value.MaxAge, 10),
+		"max_deliveries", strconv.FormatInt(value.MaxDeliveries, 10),
+		"dead_letter_stream", value.DeadLetterStream,
+		"max_size", strconv.FormatInt(value.MaxSize, 10),
+		"max_messages", strconv.FormatInt(value.MaxMessages, 10),
+		"compaction_key_field", value.CompactionKeyField,
+		"compaction_lag", strconv.FormatInt(value.CompactionLag, 10),
+		"tombstone_retention", strconv.FormatInt(value.TombstoneRetention, 10),
+		"schedule", value.Schedule,
+		"archive_windows", strings.Join(value.ArchiveWindows, ","),
+		"message_format", value.MessageFormat,
+		"schema_subject", value.SchemaSubject,
+		"updated_at", strconv.FormatInt(time.Now().Unix(), 10),
+	}
+}
+
+// Gets the cleanup bucket of a cleanup policy, unknown policies fall back to the delete bucket
+func GetCleanupBucketKey(cleanupPolicy string) string {
+	switch cleanupPolicy {
+	case "archive":
+		return STREAM_CLEANUP_BUCKET_ARCHIVE
+	case "delete,archive":
+		return STREAM_CLEANUP_BUCKET_DELETE_ARCHIVE
+	case "compact":
+		return STREAM_CLEANUP_BUCKET_COMPACT
+	default:
+		return STREAM_CLEANUP_BUCKET_DELETE
+	}
+}
+
 // Gets the metadata for a stream
 func (s *StreamMetadataServiceImpl) GetStreamMetadata(hash string) (*StreamMetadata, error) {
 	key := fmt.Sprintf("%s%s", STREAM_META_DATA_PREFIX, hash)
@@ -117,7 +292,7 @@ func (s *StreamMetadataServiceImpl) GetStreamMetadata(hash string) (*StreamMetad
 	// Check if metadata exists
 	if len(metadata) == 0 {
 		s.Logger.Warn("No metadata found for stream", zap.String("key", key))
-		return nil, fmt.Errorf("stream %s not found", hash)
+		return nil, StreamNotFoundError(hash)
 	}
 
 	// Parse and log each field in the metadata
@@ -142,14 +317,47 @@ func (s *StreamMetadataServiceImpl) GetStreamMetadata(hash string) (*StreamMetad
 		return nil, fmt.Errorf("failed to parse updated_at: %w", err)
 	}
 
+	// Dead letter settings are absent from streams created before they were introduced
+	maxDeliveries := utils.ParseInt64(metadata["max_deliveries"])
+	deadLetterStream := metadata["dead_letter_stream"]
+	// Absent from streams created before size and count retention were introduced
+	maxSize := utils.ParseInt64(metadata["max_size"])
+	maxMessages := utils.ParseInt64(metadata["max_messages"])
+	lastArchivedId := metadata[STREAM_ARCHIVE_WATERMARK_FIELD]
+	compactionKeyField := metadata["compaction_key_field"]
+	compactionLag := utils.ParseInt64(metadata["compaction_lag"])
+	tombstoneRetention := utils.ParseInt64(metadata["tombstone_retention"])
+	retentionSchedule := metadata["schedule"]
+	var archiveWindows []string
+	if metadata["archive_windows"] != "" {
+		archiveWindows = strings.Split(metadata["archive_windows"], ",")
+	}
+	// Streams created before raw payloads were introduced keep splitting content into key=value fields
+	messageFormat := metadata["message_format"]
+	if messageFormat == "" {
+		messageFormat = MESSAGE_FORMAT_KEY_VALUE
+	}
+
 	s.Logger.Debug("Parsed metadata fields", zap.String("key", key), zap.String("name", name), zap.Int64("max_age", maxAge), zap.String("cleanup_policy", cleanupPolicy), zap.Int64("created_at", createdAt), zap.Int64("updated_at", updatedAt))
 
 	return &StreamMetadata{
-		Name:          name,
-		MaxAge:        maxAge,
-		CleanupPolicy: cleanupPolicy,
-		CreatedAt:     createdAt,
-		UpdatedAt:     updatedAt,
+		Name:               name,
+		MaxAge:             maxAge,
+		CleanupPolicy:      cleanupPolicy,
+		CreatedAt:          createdAt,
+		UpdatedAt:          updatedAt,
+		MaxDeliveries:      maxDeliveries,
+		DeadLetterStream:   deadLetterStream,
+		MaxSize:            maxSize,
+		MaxMessages:        maxMessages,
+		LastArchivedId:     lastArchivedId,
+		CompactionKeyField: compactionKeyField,
+		CompactionLag:      compactionLag,
+		TombstoneRetention: tombstoneRetention,
+		Schedule:           retentionSchedule,
+		ArchiveWindows:     archiveWindows,
+		MessageFormat:      messageFormat,
+		SchemaSubject:      metadata["schema_subject"],
 	}, nil
 }
 
@@ -162,3 +370,57 @@ func (s *StreamMetadataServiceImpl) ListStreams() ([]string, error) {
 
 	return streams, nil
 }
+
+func (s *StreamMetadataServiceImpl) GetArchiveWatermark(streamName string) (string, error) {
+	key := fmt.Sprintf("%s%s", STREAM_META_DATA_PREFIX, utils.HashString(streamName))
+
+	watermark, err := s.Client.HGet(s.Ctx, key, STREAM_ARCHIVE_WATERMARK_FIELD).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get archive watermark: %w", err)
+	}
+
+	return watermark, nil
+}
+
+// The watermark is written on its own so metadata updates never move it, and only advanced from the watermark the
+// archiver started from so two archivers never both move it past the same messages
+func (s *StreamMetadataServiceImpl) SetArchiveWatermark(streamName string, previousId string, messageId string, token int64) error {
+	key := fmt.Sprintf("%s%s", STREAM_META_DATA_PREFIX, utils.HashString(streamName))
+
+	args := []interface{}{token, STREAM_FENCING_TOKEN_FIELD, STREAM_ARCHIVE_WATERMARK_FIELD, previousId, messageId}
+	advanced, err := archiveWatermarkScript.Run(s.Ctx, s.Client, []string{key}, args...).Int64()
+	if err != nil {
+		return fmt.Errorf("failed to set archive watermark: %w", err)
+	}
+
+	switch advanced {
+	case -1:
+		return FencedError(streamName, token)
+	case 0:
+		return ArchiveWatermarkConflictError(streamName, previousId)
+	}
+
+	s.Logger.Debug("Advanced archive watermark", zap.String("stream", streamName), zap.String("last_archived_id", messageId))
+	return nil
+}
+
+func (s *StreamMetadataServiceImpl) CheckFencingToken(streamName string, token int64) error {
+	key := fmt.Sprintf("%s%s", STREAM_META_DATA_PREFIX, utils.HashString(streamName))
+
+	recorded, err := fencingTokenScript.Run(s.Ctx, s.Client, []string{key}, token, STREAM_FENCING_TOKEN_FIELD).Int64()
+	if err != nil {
+		return fmt.Errorf("failed to check fencing token: %w", err)
+	}
+
+	switch recorded {
+	case -1:
+		return FencedError(streamName, token)
+	case 0:
+		return StreamNotFoundError(streamName)
+	}
+
+	return nil
+}
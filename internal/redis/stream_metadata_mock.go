@@ -17,6 +17,11 @@ func (m *StreamMetadataServiceMock) WriteStreamMetadata(value *StreamMetadata) e
 	return args.Error(0)
 }
 
+func (m *StreamMetadataServiceMock) UpdateStreamMetadata(update *StreamMetadataUpdate) error {
+	args := m.Called(update)
+	return args.Error(0)
+}
+
 func (m *StreamMetadataServiceMock) ReadStreamMetadata(streamName string) (*StreamMetadata, error) {
 	args := m.Called(streamName)
 	return args.Get(0).(*StreamMetadata), args.Error(1)
@@ -41,3 +46,23 @@ func (m *StreamMetadataServiceMock) GetStreamMetadata(streamName string) (*Strea
 	args := m.Called(streamName)
 	return args.Get(0).(*StreamMetadata), args.Error(1)
 }
+
+func (m *StreamMetadataServiceMock) GetArchiveWatermark(streamName string) (string, error) {
+	args := m.Called(streamName)
+	return args.String(0), args.Error(1)
+}
+
+func (m *StreamMetadataServiceMock) SetArchiveWatermark(streamName string, previousId string, messageId string, token int64) error {
+	args := m.Called(streamName, previousId, messageId, token)
+	return args.Error(0)
+}
+
+func (m *StreamMetadataServiceMock) CheckFencingToken(streamName string, token int64) error {
+	args := m.Called(streamName, token)
+	return args.Error(0)
+}
+
+func (m *StreamMetadataServiceMock) WriteDeadLetterPolicy(streamName string, maxDeliveries int64, deadLetterStream string) error {
+	args := m.Called(streamName, maxDeliveries, deadLetterStream)
+	return args.Error(0)
+}
@@ -30,7 +30,7 @@ func MetadataKeyMatcher(streamName string) func(interface{}) bool {
 }
 
 func TestStreamMetadataImpl_WriteStreamMetadata(t *testing.T) {
-	t.Run("Updates metadata in Redis if it exists", func(t *testing.T) {
+	t.Run("Write the metadata fields and keep the creation time", func(t *testing.T) {
 		svc, client := CreateTestSubject()
 		streamName := "test-stream"
 		streamMetadata := &StreamMetadata{
@@ -38,28 +38,33 @@ func TestStreamMetadataImpl_WriteStreamMetadata(t *testing.T) {
 			MaxAge:        7200000,
 			CleanupPolicy: "delete",
 			CreatedAt:     1620000000,
+			MessageFormat: "raw",
+			SchemaSubject: "orders",
 		}
 
-		// Setup: Metadata exists in Redis
-		existingMetadata := map[string]string{
-			"name":           streamName,
-			"cleanup_policy": "archive",
-			"max_age":        "3600000",
-			"created_at":     "1620000000",
-		}
-
-		// Mock the HGetAll call to simulate existing metadata retrieval
-		client.On("HGetAll", mock.Anything, mock.MatchedBy(MetadataKeyMatcher(streamName))).Return(redis.NewMapStringStringResult(existingMetadata, nil))
-
 		// Expect HSet to update the metadata
 		client.
 			On("HSet", mock.Anything, mock.MatchedBy(MetadataKeyMatcher(streamName)), mock.MatchedBy(func(value []interface{}) bool {
-				return len(value) == 8 &&
+				return len(value) == 30 &&
 					value[0] == "name" && value[1] == streamName &&
 					value[2] == "cleanup_policy" && value[3] == "delete" &&
-					value[4] == "max_age" && value[5] == "7200000"
+					value[4] == "max_age" && value[5] == "7200000" &&
+					value[6] == "max_deliveries" && value[7] == "0" &&
+					value[8] == "dead_letter_stream" && value[9] == "" &&
+					value[10] == "max_size" && value[11] == "0" &&
+					value[12] == "max_messages" && value[13] == "0" &&
+					value[14] == "compaction_key_field" && value[15] == "" &&
+					value[16] == "compaction_lag" && value[17] == "0" &&
+					value[18] == "tombstone_retention" && value[19] == "0" &&
+					value[20] == "schedule" && value[21] == "" &&
+					value[22] == "archive_windows" && value[23] == "" &&
+					value[24] == "message_format" && value[25] == "raw" &&
+					value[26] == "schema_subject" && value[27] == "orders"
 			})).
 			Return(redis.NewIntResult(1, nil))
+		// The creation time is only written when the stream has none, so the metadata is never read first
+		client.On("HSetNX", mock.Anything, mock.MatchedBy(MetadataKeyMatcher(streamName)), "created_at", "1620000000").
+			Return(redis.NewBoolResult(false, nil))
 
 		// Run test
 		err := svc.WriteStreamMetadata(streamMetadata)
@@ -67,5 +72,153 @@ func TestStreamMetadataImpl_WriteStreamMetadata(t *testing.T) {
 
 		// Verify mock expectations
 		client.AssertExpectations(t)
+		client.AssertNotCalled(t, "HGetAll", mock.Anything, mock.Anything)
+	})
+}
+
+func TestStreamMetadataService_WriteDeadLetterPolicy(t *testing.T) {
+	svc, client := CreateTestSubject()
+	client.On("HSet", mock.Anything, mock.MatchedBy(MetadataKeyMatcher("test-stream")), mock.MatchedBy(func(value []interface{}) bool {
+		return len(value) == 6 &&
+			value[0] == "max_deliveries" && value[1] == "5" &&
+			value[2] == "dead_letter_stream" && value[3] == "{test-stream}.dlq" &&
+			value[4] == "updated_at"
+	})).Return(redis.NewIntResult(0, nil))
+
+	err := svc.WriteDeadLetterPolicy("test-stream", 5, "{test-stream}.dlq")
+
+	assert.NoError(t, err)
+	client.AssertExpectations(t)
+}
+
+func TestStreamMetadataService_ArchiveWatermark(t *testing.T) {
+	t.Run("Return an empty watermark when nothing was archived", func(t *testing.T) {
+		svc, client := CreateTestSubject()
+		client.On("HGet", mock.Anything, mock.MatchedBy(MetadataKeyMatcher("test-stream")), STREAM_ARCHIVE_WATERMARK_FIELD).
+			Return(redis.NewStringResult("", redis.Nil))
+
+		watermark, err := svc.GetArchiveWatermark("test-stream")
+
+		assert.NoError(t, err)
+		assert.Empty(t, watermark)
+	})
+
+	t.Run("Advance only the watermark field from the previous watermark", func(t *testing.T) {
+		svc, client := CreateTestSubject()
+		client.On("Eval", mock.Anything, archiveWatermarkScriptSource, mock.MatchedBy(func(keys []string) bool {
+			return len(keys) == 1 && MetadataKeyMatcher("test-stream")(keys[0])
+		}), []interface{}{int64(4), STREAM_FENCING_TOKEN_FIELD, STREAM_ARCHIVE_WATERMARK_FIELD, "2-0", "5-0"}).Return(redis.NewCmdResult(int64(1), nil))
+
+		err := svc.SetArchiveWatermark("test-stream", "2-0", "5-0", 4)
+
+		assert.NoError(t, err)
+		client.AssertExpectations(t)
+	})
+
+	t.Run("Fail when another archiver moved the watermark", func(t *testing.T) {
+		svc, client := CreateTestSubject()
+		client.On("Eval", mock.Anything, archiveWatermarkScriptSource, mock.Anything, mock.Anything).Return(redis.NewCmdResult(int64(0), nil))
+
+		err := svc.SetArchiveWatermark("test-stream", "2-0", "5-0", 4)
+
+		assert.Equal(t, ArchiveWatermarkConflictError("test-stream", "2-0"), err)
+	})
+
+	t.Run("Fail when a newer fencing token advanced the watermark", func(t *testing.T) {
+		svc, client := CreateTestSubject()
+		client.On("Eval", mock.Anything, archiveWatermarkScriptSource, mock.Anything, mock.Anything).Return(redis.NewCmdResult(int64(-1), nil))
+
+		err := svc.SetArchiveWatermark("test-stream", "2-0", "5-0", 4)
+
+		assert.Equal(t, FencedError("test-stream", 4), err)
+	})
+}
+
+func TestStreamMetadataService_CheckFencingToken(t *testing.T) {
+	t.Run("Record the fencing token on the stream's metadata", func(t *testing.T) {
+		svc, client := CreateTestSubject()
+		client.On("Eval", mock.Anything, fencingTokenScriptSource, mock.MatchedBy(func(keys []string) bool {
+			return len(keys) == 1 && MetadataKeyMatcher("test-stream")(keys[0])
+		}), []interface{}{int64(4), STREAM_FENCING_TOKEN_FIELD}).Return(redis.NewCmdResult(int64(1), nil))
+
+		err := svc.CheckFencingToken("test-stream", 4)
+
+		assert.NoError(t, err)
+		client.AssertExpectations(t)
+	})
+
+	t.Run("Fail when a newer fencing token was recorded", func(t *testing.T) {
+		svc, client := CreateTestSubject()
+		client.On("Eval", mock.Anything, fencingTokenScriptSource, mock.Anything, mock.Anything).Return(redis.NewCmdResult(int64(-1), nil))
+
+		err := svc.CheckFencingToken("test-stream", 4)
+
+		assert.Equal(t, FencedError("test-stream", 4), err)
+	})
+}
+
+func TestStreamMetadataImpl_UpdateStreamMetadata(t *testing.T) {
+	streamHash := utils.HashString("test-stream")
+	metadataKey := STREAM_META_DATA_PREFIX + streamHash
+	bucketKeys := append([]string{metadataKey, STREAM_CLEANUP_BUCKET_ARCHIVE}, STREAM_CLEANUP_BUCKETS...)
+	compactionDefaults := []interface{}{"compaction_key_field", "key"}
+
+	t.Run("Set the updated fields and move the stream to the bucket of its new cleanup policy", func(t *testing.T) {
+		svc, client := CreateTestSubject()
+		client.On("Eval", mock.Anything, streamMetadataUpdateScriptSource, bucketKeys, []interface{}{
+			streamHash, 1, "compaction_key_field", "key", "cleanup_policy", "archive",
+		}).Return(redis.NewCmdResult(int64(1), nil))
+		// Streams created before the buckets were moved are removed from the legacy buckets as well
+		for _, bucket := range LEGACY_STREAM_CLEANUP_BUCKETS {
+			client.On("SRem", mock.Anything, bucket, []interface{}{streamHash}).Return(redis.NewIntResult(0, nil))
+		}
+
+		err := svc.UpdateStreamMetadata(&StreamMetadataUpdate{
+			Name:               "test-stream",
+			CleanupPolicy:      "archive",
+			Fields:             []interface{}{"cleanup_policy", "archive"},
+			CompactionDefaults: compactionDefaults,
+		})
+
+		assert.NoError(t, err)
+		client.AssertExpectations(t)
+		client.AssertNumberOfCalls(t, "SRem", len(LEGACY_STREAM_CLEANUP_BUCKETS))
+	})
+
+	t.Run("Only touch the metadata hash when the cleanup policy is unchanged", func(t *testing.T) {
+		svc, client := CreateTestSubject()
+		client.On("Eval", mock.Anything, streamMetadataUpdateScriptSource, []string{metadataKey}, []interface{}{
+			streamHash, 1, "compaction_key_field", "key", "max_age", "7200000",
+		}).Return(redis.NewCmdResult(int64(1), nil))
+
+		err := svc.UpdateStreamMetadata(&StreamMetadataUpdate{
+			Name:               "test-stream",
+			Fields:             []interface{}{"max_age", "7200000"},
+			CompactionDefaults: compactionDefaults,
+		})
+
+		assert.NoError(t, err)
+		client.AssertExpectations(t)
+		client.AssertNotCalled(t, "SRem", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Return not found when the stream has no metadata", func(t *testing.T) {
+		svc, client := CreateTestSubject()
+		client.On("Eval", mock.Anything, streamMetadataUpdateScriptSource, bucketKeys, mock.Anything).Return(redis.NewCmdResult(int64(0), nil))
+
+		err := svc.UpdateStreamMetadata(&StreamMetadataUpdate{Name: "test-stream", CleanupPolicy: "archive", Fields: []interface{}{"cleanup_policy", "archive"}})
+
+		assert.IsType(t, &RedisStreamNotFoundError{}, err)
+	})
+
+	t.Run("Reject a compacted stream without a compaction key field", func(t *testing.T) {
+		svc, client := CreateTestSubject()
+		compactKeys := append([]string{metadataKey, STREAM_CLEANUP_BUCKET_COMPACT}, STREAM_CLEANUP_BUCKETS...)
+		client.On("Eval", mock.Anything, streamMetadataUpdateScriptSource, compactKeys, mock.Anything).Return(redis.NewCmdResult(int64(-1), nil))
+
+		err := svc.UpdateStreamMetadata(&StreamMetadataUpdate{Name: "test-stream", CleanupPolicy: "compact", Fields: []interface{}{"cleanup_policy", "compact"}})
+
+		assert.Error(t, err)
+		client.AssertNotCalled(t, "SRem", mock.Anything, mock.Anything, mock.Anything)
 	})
 }
@@ -0,0 +1,355 @@
+package redis
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type PublishBatchParameters struct {
+	Name     string
+	Messages [][]byte
+	// Append either every message or none of them, the batch may not exceed the max publish batch size
+	Atomic bool
+	// Identifies the producer, messages without an idempotency key are deduplicated by producer ID and sequence number when set
+	ProducerId string
+	// Sequence number of the first message, the following messages are numbered consecutively
+	FirstSequence int64
+	// Idempotency key per message in message order, empty keys fall back to the producer sequence number
+	IdempotencyKeys []string
+	// Headers per message in message order, stored next to the content of the message
+	Headers []map[string]string
+}
+
+// Appends a batch of messages to the stream at KEYS[1], remembering idempotency keys in the hash at KEYS[2] ordered by
+// publish time in the sorted set at KEYS[3]. ARGV[1] enables atomic mode, ARGV[2] is the current time, ARGV[3] the dedup
+// window and ARGV[4] the maximum number of remembered keys, followed by every message as its idempotency key, its number
+// of field and value arguments and those arguments. Messages with a remembered key are not appended again.
+// Scripts run without interleaving other commands but are not rolled back by Redis, so in atomic mode the messages
+// appended before a failed one are deleted again and -1 is replaced by the zero based index of the failed message and
+// its error. Otherwise returns -1 and a status and message ID or error per message
+const publishScriptSource = `
+local atomic = ARGV[1] == "1"
+local now = tonumber(ARGV[2])
+local window = tonumber(ARGV[3])
+local maxEntries = tonumber(ARGV[4])
+
+local cutoff = "(" .. (now - window)
+for _, key in ipairs(redis.call("ZRANGEBYSCORE", KEYS[3], "-inf", cutoff)) do
+	redis.call("HDEL", KEYS[2], key)
+end
+redis.call("ZREMRANGEBYSCORE", KEYS[3], "-inf", cutoff)
+
+local results = {}
+local appended = {}
+local recorded = {}
+local rollback = function()
+	for _, id in ipairs(appended) do
+		redis.call("XDEL", KEYS[1], id)
+	end
+	for _, key in ipairs(recorded) do
+		redis.call("HDEL", KEYS[2], key)
+		redis.call("ZREM", KEYS[3], key)
+	end
+end
+
+local pos = 5
+local index = 0
+while pos <= #ARGV do
+	local key = ARGV[pos]
+	local size = tonumber(ARGV[pos + 1])
+	local existing = false
+	if key ~= "" then
+		existing = redis.call("HGET", KEYS[2], key)
+	end
+
+	if existing then
+		results[#results + 1] = {"duplicate", existing}
+	else
+		local reply = redis.pcall("XADD", KEYS[1], "NOMKSTREAM", "*", unpack(ARGV, pos + 2, pos + 1 + size))
+		local err = nil
+		if type(reply) == "table" and reply.err then
+			err = reply.err
+		elseif not reply then
+			err = "stream does not exist"
+		end
+
+		if err and atomic then
+			rollback()
+			return {index, err}
+		elseif err then
+			results[#results + 1] = {"error", err}
+		else
+			appended[#appended + 1] = reply
+			if key ~= "" then
+				redis.call("HSET", KEYS[2], key, reply)
+				redis.call("ZADD", KEYS[3], now, key)
+				recorded[#recorded + 1] = key
+			end
+			results[#results + 1] = {"published", reply}
+		end
+	end
+
+	pos = pos + size + 2
+	index = index + 1
+end
+
+if #recorded > 0 then
+	local overflow = redis.call("ZCARD", KEYS[3]) - maxEntries
+	if overflow > 0 then
+		for _, key in ipairs(redis.call("ZRANGE", KEYS[3], 0, overflow - 1)) do
+			redis.call("HDEL", KEYS[2], key)
+		end
+		redis.call("ZREMRANGEBYRANK", KEYS[3], 0, overflow - 1)
+	end
+	redis.call("PEXPIRE", KEYS[2], window)
+	redis.call("PEXPIRE", KEYS[3], window)
+end
+
+return {-1, results}
+`
+
+// Run on every scripted publish, so it is sent by SHA once Redis has cached it
+var publishScript = redis.NewScript(publishScriptSource)
+
+func (p *PublishBatchParameters) Validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("stream name is required")
+	}
+
+	if len(p.IdempotencyKeys) > 0 && len(p.IdempotencyKeys) != len(p.Messages) {
+		return fmt.Errorf("expected one idempotency key per message, got %d keys for %d messages", len(p.IdempotencyKeys), len(p.Messages))
+	}
+
+	if len(p.Headers) > 0 && len(p.Headers) != len(p.Messages) {
+		return fmt.Errorf("expected headers for every message, got %d headers for %d messages", len(p.Headers), len(p.Messages))
+	}
+
+	if p.FirstSequence < 0 {
+		return fmt.Errorf("first sequence must be greater than or equal to 0")
+	}
+
+	return nil
+}
+
+// Gets the key a message is deduplicated by, empty when the message is not deduplicated
+func (p *PublishBatchParameters) GetIdempotencyKey(index int) string {
+	if index < len(p.IdempotencyKeys) && p.IdempotencyKeys[index] != "" {
+		return "key:" + p.IdempotencyKeys[index]
+	}
+
+	if p.ProducerId != "" {
+		return fmt.Sprintf("producer:%s:%d", p.ProducerId, p.FirstSequence+int64(index))
+	}
+
+	return ""
+}
+
+// Gets the keys of the idempotency key hash and sorted set of a stream. They are tagged with the stream name, or keep the
+// stream's own hash tag, so they are in the stream's cluster slot and can be used in one script with it
+func GetStreamDedupKeys(streamName string) (string, string) {
+	prefix := GetSlotPrefix(streamName)
+	return prefix + STREAM_DEDUP_IDS_SUFFIX, prefix + STREAM_DEDUP_WINDOW_SUFFIX
+}
+
+// Whether the messages need the publish script, batches that are neither atomic nor deduplicated are pipelined
+func (p *PublishBatchParameters) RequiresScript() bool {
+	return p.Atomic || p.ProducerId != "" || len(p.IdempotencyKeys) > 0
+}
+
+// Publish messages to a stream, atomic and deduplicated batches are appended by a single script
+func (s *RedisStreamServiceImpl) PublishBatch(params *PublishBatchParameters) (*StreamPublishResult, error) {
+	if params.Atomic && len(params.Messages) > s.MaxPublishBatchSize {
+		return nil, PublishBatchTooLargeError(len(params.Messages), s.MaxPublishBatchSize)
+	}
+
+	exists, err := s.StreamExists(params.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check if stream exists: %w", err)
+	}
+
+	if !exists {
+		return nil, StreamNotFoundError(params.Name)
+	}
+
+	result := &StreamPublishResult{
+		MessageIds: make([]string, 0),
+		Errors:     make([]error, 0),
+	}
+
+	metadata, err := s.GetPublishMetadata(params.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	headers := params.Headers
+	if metadata.SchemaSubject != "" && s.SchemaRegistry != nil {
+		headers, err = s.ValidateMessages(metadata.SchemaSubject, params.Messages, params.Headers)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	messages := ToRedisMessages(metadata.MessageFormat, params.Messages, headers)
+	if !params.RequiresScript() {
+		s.PublishPipelined(params.Name, messages, result)
+		return result, nil
+	}
+
+	// Non atomic batches are split like pipelined publishes, every chunk is deduplicated on its own
+	batchSize := s.MaxPublishBatchSize
+	if params.Atomic {
+		batchSize = max(len(params.Messages), 1)
+	}
+
+	idsKey, windowKey := GetStreamDedupKeys(params.Name)
+	for start := 0; start < len(params.Messages); start += batchSize {
+		end := min(start+batchSize, len(params.Messages))
+		args := []interface{}{0, time.Now().UnixMilli(), s.DedupWindow, s.DedupMaxEntries}
+		if params.Atomic {
+			args[0] = 1
+		}
+		args = append(args, GetPublishScriptArgs(params, messages, start, end)...)
+
+		reply, err := publishScript.Run(s.Ctx, s.Client, []string{params.Name, idsKey, windowKey}, args...).Slice()
+		if err != nil {
+			return nil, fmt.Errorf("failed to publish messages to stream %s: %w", params.Name, err)
+		}
+
+		if err := ApplyPublishScriptReply(result, reply, start, end-start); err != nil {
+			return nil, fmt.Errorf("failed to publish messages to stream %s: %w", params.Name, err)
+		}
+	}
+
+	return result, nil
+}
+
+// Flattens messages start to end into script arguments, every message is its idempotency key and number of field and
+// value arguments followed by them
+func GetPublishScriptArgs(params *PublishBatchParameters, messages []map[string]interface{}, start int, end int) []interface{} {
+	args := make([]interface{}, 0, (end-start)*4)
+	for i, message := range messages[start:end] {
+		// Sorted so the fields are stored in the same order on every publish
+		keys := slices.Sorted(maps.Keys(message))
+		args = append(args, params.GetIdempotencyKey(start+i), len(keys)*2)
+		for _, key := range keys {
+			args = append(args, key, message[key])
+		}
+	}
+	return args
+}
+
+// Adds the outcome of count messages from offset on in the publish script reply to the result
+func ApplyPublishScriptReply(result *StreamPublishResult, reply []interface{}, offset int, count int) error {
+	if len(reply) != 2 {
+		return fmt.Errorf("unexpected script reply %v", reply)
+	}
+
+	index, ok := reply[0].(int64)
+	if !ok {
+		return fmt.Errorf("unexpected script reply %v", reply)
+	}
+
+	// The whole atomic batch was rolled back because of a single message
+	if index >= 0 {
+		result.Failed += count
+		result.AddError(StreamPublishError(offset+int(index), errors.New(fmt.Sprint(reply[1]))))
+		return nil
+	}
+
+	outcomes, ok := reply[1].([]interface{})
+	if !ok || len(outcomes) != count {
+		return fmt.Errorf("unexpected script reply %v", reply)
+	}
+
+	for i, outcome := range outcomes {
+		values, ok := outcome.([]interface{})
+		if !ok || len(values) != 2 {
+			return fmt.Errorf("unexpected script reply %v", reply)
+		}
+
+		value := fmt.Sprint(values[1])
+		switch values[0] {
+		case "published":
+			result.IncrementPublished()
+			result.AddMessageId(value)
+		case "duplicate":
+			result.Duplicates++
+			result.AddMessageId(value)
+		default:
+			result.IncrementFailed()
+			result.AddError(StreamPublishError(offset+i, errors.New(value)))
+		}
+	}
+
+	return nil
+}
+
+// Validates messages against the schema named by their schema ID header, or the latest schema of the subject, and returns
+// their headers with the ID of the schema they were validated against. The first invalid message fails the whole batch
+func (s *RedisStreamServiceImpl) ValidateMessages(subject string, messages [][]byte, headers []map[string]string) ([]map[string]string, error) {
+	latest, err := s.SchemaRegistry.GetSubjectSchema(subject, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schema of subject %s: %w", subject, err)
+	}
+
+	schemas := map[int64]*Schema{latest.Id: latest}
+	result := make([]map[string]string, len(messages))
+	for i, message := range messages {
+		result[i] = make(map[string]string)
+		if i < len(headers) {
+			maps.Copy(result[i], headers[i])
+		}
+
+		value := latest
+		if header, ok := result[i][MESSAGE_SCHEMA_ID_HEADER]; ok {
+			id, err := strconv.ParseInt(header, 10, 64)
+			if err != nil {
+				return nil, SchemaValidationError(i, fmt.Errorf("invalid schema ID %q", header))
+			}
+
+			value, err = s.GetMessageSchema(subject, id, schemas)
+			if err != nil {
+				if _, ok := err.(*RedisSchemaNotFoundError); ok {
+					return nil, SchemaValidationError(i, err)
+				}
+				return nil, err
+			}
+		}
+
+		// Messages without content, such as tombstones, have nothing to validate
+		if len(message) > 0 {
+			if err := s.SchemaRegistry.ValidateMessage(value, message); err != nil {
+				return nil, SchemaValidationError(i, err)
+			}
+		}
+
+		result[i][MESSAGE_SCHEMA_ID_HEADER] = strconv.FormatInt(value.Id, 10)
+	}
+
+	return result, nil
+}
+
+// Gets a schema named by a schema ID header, which has to be a schema of the subject
+func (s *RedisStreamServiceImpl) GetMessageSchema(subject string, id int64, schemas map[int64]*Schema) (*Schema, error) {
+	if value, ok := schemas[id]; ok {
+		return value, nil
+	}
+
+	value, err := s.SchemaRegistry.GetSchema(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if value.Subject != subject {
+		return nil, SchemaNotFoundError(id)
+	}
+
+	schemas[id] = value
+	return value, nil
+}
@@ -0,0 +1,273 @@
+package redis
+
+import (
+	"errors"
+	"testing"
+
+	rdb "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetStreamDedupKeys(t *testing.T) {
+	idsKey, windowKey := GetStreamDedupKeys("orders")
+	assert.Equal(t, "{orders}:dedup_ids", idsKey)
+	assert.Equal(t, "{orders}:dedup_window", windowKey)
+
+	// Streams with their own hash tag keep it
+	idsKey, _ = GetStreamDedupKeys("{tenant-1}.orders")
+	assert.Equal(t, "{tenant-1}.orders:dedup_ids", idsKey)
+}
+
+func TestPublishBatchParameters(t *testing.T) {
+	params := &PublishBatchParameters{
+		Name:            "orders",
+		Messages:        [][]byte{[]byte("a=1"), []byte("a=2"), []byte("a=3")},
+		ProducerId:      "producer-1",
+		FirstSequence:   10,
+		IdempotencyKeys: []string{"", "order-42", ""},
+	}
+
+	assert.NoError(t, params.Validate())
+	assert.Equal(t, "producer:producer-1:10", params.GetIdempotencyKey(0))
+	assert.Equal(t, "key:order-42", params.GetIdempotencyKey(1))
+	assert.Equal(t, "producer:producer-1:12", params.GetIdempotencyKey(2))
+
+	params.ProducerId = ""
+	assert.Equal(t, "", params.GetIdempotencyKey(0))
+
+	params.IdempotencyKeys = []string{"order-42"}
+	assert.Error(t, params.Validate())
+
+	params.IdempotencyKeys = nil
+	params.Headers = []map[string]string{{"key": "order-42"}}
+	assert.Error(t, params.Validate())
+}
+
+func TestRedisStreamService_PublishBatch(t *testing.T) {
+	streamName := "test-stream"
+	keys := []string{streamName, "{test-stream}:dedup_ids", "{test-stream}:dedup_window"}
+	messages := [][]byte{
+		[]byte("user=1 event_name=login"),
+		[]byte("event_name=logout"),
+	}
+	scriptArgs := func(atomic int, idempotencyKeys ...string) interface{} {
+		return mock.MatchedBy(func(args []interface{}) bool {
+			expected := []interface{}{idempotencyKeys[0], 4, "event_name", "login", "user", "1", idempotencyKeys[1], 2, "event_name", "logout"}
+			return args[0] == atomic && args[2] == int64(DEFAULT_DEDUP_WINDOW) && args[3] == int64(DEFAULT_DEDUP_MAX_ENTRIES) &&
+				assert.ObjectsAreEqual(expected, args[4:])
+		})
+	}
+
+	setup := func() (*RedisStreamServiceImpl, *MockRedisClient) {
+		service, client, metadataService := setupRedisStreamService()
+		metadataService.On("GetStreamMetadata", mock.Anything).Return(&StreamMetadata{MessageFormat: MESSAGE_FORMAT_KEY_VALUE}, nil)
+		infoCmd := &rdb.XInfoStreamCmd{}
+		infoCmd.SetVal(&rdb.XInfoStream{})
+		client.On("XInfoStream", mock.Anything, streamName).Return(infoCmd)
+		return service.(*RedisStreamServiceImpl), client
+	}
+
+	t.Run("Return the original ID of messages published before", func(t *testing.T) {
+		service, client := setup()
+		client.On("Eval", mock.Anything, publishScriptSource, keys, scriptArgs(0, "producer:producer-1:7", "producer:producer-1:8")).
+			Return(rdb.NewCmdResult([]interface{}{int64(-1), []interface{}{
+				[]interface{}{"duplicate", "1-0"},
+				[]interface{}{"published", "2-0"},
+			}}, nil))
+
+		result, err := service.PublishBatch(&PublishBatchParameters{
+			Name:          streamName,
+			Messages:      messages,
+			ProducerId:    "producer-1",
+			FirstSequence: 7,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.Published)
+		assert.Equal(t, 1, result.Duplicates)
+		assert.Equal(t, []string{"1-0", "2-0"}, result.MessageIds)
+		client.AssertExpectations(t)
+	})
+
+	t.Run("Report failed messages of non atomic batches", func(t *testing.T) {
+		service, client := setup()
+		client.On("Eval", mock.Anything, publishScriptSource, keys, scriptArgs(0, "key:a", "key:b")).
+			Return(rdb.NewCmdResult([]interface{}{int64(-1), []interface{}{
+				[]interface{}{"published", "1-0"},
+				[]interface{}{"error", "OOM command not allowed"},
+			}}, nil))
+
+		result, err := service.PublishBatch(&PublishBatchParameters{
+			Name:            streamName,
+			Messages:        messages,
+			IdempotencyKeys: []string{"a", "b"},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.Published)
+		assert.Equal(t, 1, result.Failed)
+		assert.Equal(t, StreamPublishError(1, errors.New("OOM command not allowed")), result.Errors[0])
+	})
+
+	t.Run("Append every message of an atomic batch in one script", func(t *testing.T) {
+		service, client := setup()
+		client.On("Eval", mock.Anything, publishScriptSource, keys, scriptArgs(1, "", "")).
+			Return(rdb.NewCmdResult([]interface{}{int64(-1), []interface{}{
+				[]interface{}{"published", "1-0"},
+				[]interface{}{"published", "1-1"},
+			}}, nil))
+
+		result, err := service.PublishBatch(&PublishBatchParameters{Name: streamName, Messages: messages, Atomic: true})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, result.Published)
+		assert.Equal(t, []string{"1-0", "1-1"}, result.MessageIds)
+		client.AssertExpectations(t)
+	})
+
+	t.Run("Fail the whole atomic batch with the error of the failed message", func(t *testing.T) {
+		service, client := setup()
+		client.On("Eval", mock.Anything, publishScriptSource, keys, scriptArgs(1, "", "")).
+			Return(rdb.NewCmdResult([]interface{}{int64(1), "OOM command not allowed"}, nil))
+
+		result, err := service.PublishBatch(&PublishBatchParameters{Name: streamName, Messages: messages, Atomic: true})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, result.Published)
+		assert.Equal(t, 2, result.Failed)
+		assert.Empty(t, result.MessageIds)
+		assert.Equal(t, StreamPublishError(1, errors.New("OOM command not allowed")), result.Errors[0])
+	})
+
+	t.Run("Store the content of raw streams untouched next to its headers", func(t *testing.T) {
+		service, client, metadataService := setupRedisStreamService()
+		metadataService.On("GetStreamMetadata", mock.Anything).Return(&StreamMetadata{MessageFormat: MESSAGE_FORMAT_RAW}, nil)
+		infoCmd := &rdb.XInfoStreamCmd{}
+		infoCmd.SetVal(&rdb.XInfoStream{})
+		client.On("XInfoStream", mock.Anything, streamName).Return(infoCmd)
+		payload := `{"user": 1, "event name": "login"}`
+		client.On("Eval", mock.Anything, publishScriptSource, keys, mock.MatchedBy(func(args []interface{}) bool {
+			return assert.ObjectsAreEqual([]interface{}{"key:a", 4, "header:content-type", "application/json", "payload", payload}, args[4:])
+		})).Return(rdb.NewCmdResult([]interface{}{int64(-1), []interface{}{[]interface{}{"published", "1-0"}}}, nil))
+
+		result, err := service.PublishBatch(&PublishBatchParameters{
+			Name:            streamName,
+			Messages:        [][]byte{[]byte(payload)},
+			IdempotencyKeys: []string{"a"},
+			Headers:         []map[string]string{{"content-type": "application/json"}},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.Published)
+		client.AssertExpectations(t)
+	})
+
+	t.Run("Publish key=value fields to streams without metadata", func(t *testing.T) {
+		service, client, metadataService := setupRedisStreamService()
+		metadataService.On("GetStreamMetadata", mock.Anything).Return((*StreamMetadata)(nil), StreamNotFoundError("test-stream"))
+		infoCmd := &rdb.XInfoStreamCmd{}
+		infoCmd.SetVal(&rdb.XInfoStream{})
+		client.On("XInfoStream", mock.Anything, streamName).Return(infoCmd)
+		client.On("Eval", mock.Anything, publishScriptSource, keys, scriptArgs(1, "", "")).
+			Return(rdb.NewCmdResult([]interface{}{int64(-1), []interface{}{
+				[]interface{}{"published", "1-0"},
+				[]interface{}{"published", "1-1"},
+			}}, nil))
+
+		result, err := service.PublishBatch(&PublishBatchParameters{Name: streamName, Messages: messages, Atomic: true})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, result.Published)
+		client.AssertExpectations(t)
+	})
+
+	t.Run("Fail the publish when the stream metadata cannot be read", func(t *testing.T) {
+		service, client, metadataService := setupRedisStreamService()
+		metadataService.On("GetStreamMetadata", mock.Anything).Return((*StreamMetadata)(nil), errors.New("i/o timeout"))
+		infoCmd := &rdb.XInfoStreamCmd{}
+		infoCmd.SetVal(&rdb.XInfoStream{})
+		client.On("XInfoStream", mock.Anything, streamName).Return(infoCmd)
+
+		result, err := service.PublishBatch(&PublishBatchParameters{Name: streamName, Messages: messages, Atomic: true})
+
+		assert.Nil(t, result)
+		assert.ErrorContains(t, err, "i/o timeout")
+		client.AssertNotCalled(t, "Eval")
+		client.AssertNotCalled(t, "XAdd")
+	})
+
+	t.Run("Reject atomic batches larger than the max batch size", func(t *testing.T) {
+		service, client := setup()
+		service.MaxPublishBatchSize = 1
+
+		result, err := service.PublishBatch(&PublishBatchParameters{Name: streamName, Messages: messages, Atomic: true})
+
+		assert.Nil(t, result)
+		assert.IsType(t, &RedisPublishBatchTooLargeError{}, err)
+		client.AssertNotCalled(t, "Eval")
+	})
+}
+
+func TestRedisStreamService_ValidateMessages(t *testing.T) {
+	latest := &Schema{Id: 2, Subject: "orders", Version: 2}
+	messages := [][]byte{[]byte(`{"id": 1}`), []byte(`{"id": "1"}`)}
+
+	setup := func() (*RedisStreamServiceImpl, *SchemaRegistryServiceMock) {
+		service, _, _ := setupRedisStreamService()
+		registry := NewSchemaRegistryServiceMock()
+		registry.On("GetSubjectSchema", "orders", int64(0)).Return(latest, nil)
+		service.(*RedisStreamServiceImpl).SchemaRegistry = registry
+		return service.(*RedisStreamServiceImpl), registry
+	}
+
+	t.Run("Add the ID of the schema messages were validated against", func(t *testing.T) {
+		service, registry := setup()
+		previous := &Schema{Id: 1, Subject: "orders", Version: 1}
+		registry.On("GetSchema", int64(1)).Return(previous, nil)
+		registry.On("ValidateMessage", previous, messages[0]).Return(nil)
+		registry.On("ValidateMessage", latest, messages[1]).Return(nil)
+
+		headers, err := service.ValidateMessages("orders", messages, []map[string]string{{"schema_id": "1"}})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []map[string]string{{"schema_id": "1"}, {"schema_id": "2"}}, headers)
+		registry.AssertExpectations(t)
+	})
+
+	t.Run("Reject the batch with the index of the invalid message", func(t *testing.T) {
+		service, registry := setup()
+		validationErr := errors.New("expected integer, but got string")
+		registry.On("ValidateMessage", latest, messages[0]).Return(nil)
+		registry.On("ValidateMessage", latest, messages[1]).Return(validationErr)
+
+		_, err := service.ValidateMessages("orders", messages, nil)
+
+		assert.Equal(t, SchemaValidationError(1, validationErr), err)
+	})
+
+	t.Run("Reject messages naming a schema of another subject", func(t *testing.T) {
+		service, registry := setup()
+		registry.On("GetSchema", int64(7)).Return(&Schema{Id: 7, Subject: "payments"}, nil)
+
+		_, err := service.ValidateMessages("orders", messages, []map[string]string{{"schema_id": "7"}, nil})
+
+		assert.Equal(t, SchemaValidationError(0, SchemaNotFoundError(7)), err)
+	})
+
+	t.Run("Publish nothing when a message of a bound stream is invalid", func(t *testing.T) {
+		service, registry := setup()
+		metadataService := service.StreamMetadataService.(*StreamMetadataServiceMock)
+		metadataService.On("GetStreamMetadata", mock.Anything).Return(&StreamMetadata{MessageFormat: MESSAGE_FORMAT_RAW, SchemaSubject: "orders"}, nil)
+		infoCmd := &rdb.XInfoStreamCmd{}
+		infoCmd.SetVal(&rdb.XInfoStream{})
+		service.Client.(*MockRedisClient).On("XInfoStream", mock.Anything, "orders").Return(infoCmd)
+		registry.On("ValidateMessage", latest, mock.Anything).Return(errors.New("invalid"))
+
+		result, err := service.PublishBatch(&PublishBatchParameters{Name: "orders", Messages: messages})
+
+		assert.Nil(t, result)
+		assert.IsType(t, &RedisSchemaValidationError{}, err)
+		service.Client.(*MockRedisClient).AssertNotCalled(t, "Pipelined", mock.Anything)
+	})
+}
@@ -3,47 +3,188 @@ package redis
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 	"github.com/streamweaverio/broker/internal/config"
 	"github.com/streamweaverio/broker/internal/logging"
+	"github.com/streamweaverio/broker/internal/schedule"
 	"github.com/streamweaverio/broker/pkg/utils"
 	"go.uber.org/zap"
 )
 
+// Rejects a fencing token ARGV[1] older than the one in KEYS[2], in the slot of the stream KEYS[1], with -1 and records
+// it otherwise, a zero token is not fenced
+const streamFenceCheck = `
+local token = tonumber(ARGV[1])
+if token > 0 then
+	local seen = tonumber(redis.call("GET", KEYS[2]) or "0")
+	if token < seen then
+		return -1
+	end
+	redis.call("SET", KEYS[2], token)
+end
+`
+
+// Trims messages older than the min ID ARGV[2] from the stream, returns the number of trimmed messages
+const fencedTrimScriptSource = streamFenceCheck + `
+return redis.call("XTRIM", KEYS[1], "MINID", ARGV[2])
+`
+
+// Deletes the messages with IDs ARGV[2..] from the stream, returns the number of deleted messages
+const fencedDeleteScriptSource = streamFenceCheck + `
+return redis.call("XDEL", KEYS[1], unpack(ARGV, 2))
+`
+
+var fencedTrimScript = redis.NewScript(fencedTrimScriptSource)
+var fencedDeleteScript = redis.NewScript(fencedDeleteScriptSource)
+
 type CreateStreamParameters struct {
 	Name          string
 	CleanupPolicy string
 	MaxAge        int64
+	// Number of deliveries to a consumer group after which a message is moved to the dead letter stream, zero disables dead lettering
+	MaxDeliveries int64
+	// Stream that receives dead lettered messages, defaults to the stream name with the dead letter suffix
+	DeadLetterStream string
+	// Memory budget in bytes of the stream, zero falls back to the global retention max size
+	MaxSize int64
+	// Maximum number of messages kept in the stream, zero falls back to the global retention max messages
+	MaxMessages int64
+	// Compaction settings of streams with the compact cleanup policy, zero values fall back to the global retention settings
+	CompactionKeyField string
+	CompactionLag      int64
+	TombstoneRetention int64
+	// Cron expression of when retention runs on the stream, empty falls back to the global retention schedule
+	Schedule string
+	// Daily UTC windows (HH:MM-HH:MM) archival is allowed in, empty falls back to the global archive windows
+	ArchiveWindows []string
+	// How published content is stored, defaults to raw
+	MessageFormat string
+	// Schema registry subject published messages are validated against, empty disables validation
+	SchemaSubject string
+}
+
+type UpdateStreamParameters struct {
+	Name string
+	// Settings left nil keep their current value
+	MaxAge             *int64
+	CleanupPolicy      *string
+	MaxSize            *int64
+	MaxMessages        *int64
+	CompactionKeyField *string
+	CompactionLag      *int64
+	TombstoneRetention *int64
+	Schedule           *string
+	ArchiveWindows     []string
+	// Whether ArchiveWindows replaces the current archive windows, an empty list removes them
+	SetArchiveWindows bool
+	MessageFormat     *string
+	// An empty subject unbinds the stream from its schema
+	SchemaSubject *string
 }
 
 type StreamMetadata struct {
-	Name          string
-	MaxAge        int64
+	Name             string
+	MaxAge           int64
+	CleanupPolicy    string
+	CreatedAt        int64
+	UpdatedAt        int64
+	MaxDeliveries    int64
+	DeadLetterStream string
+	MaxSize          int64
+	MaxMessages      int64
+	// ID of the last message written to storage, only advanced once its block is archived
+	LastArchivedId     string
+	CompactionKeyField string
+	CompactionLag      int64
+	TombstoneRetention int64
+	// Cron expression of when retention runs on the stream, empty runs it on every retention interval
+	Schedule string
+	// Daily UTC windows archival is allowed in, empty allows it at any time
+	ArchiveWindows []string
+	// How published content is stored, either raw or key_value
+	MessageFormat string
+	// Schema registry subject published messages are validated against
+	SchemaSubject string
+}
+
+// Settings of an existing stream changed by an update, fields not in the update keep their current value
+type StreamMetadataUpdate struct {
+	Name string
+	// Cleanup policy the stream moves to, empty keeps the current policy and cleanup bucket
 	CleanupPolicy string
-	CreatedAt     int64
-	UpdatedAt     int64
+	// Metadata field value pairs to set
+	Fields []interface{}
+	// Compaction field value pairs set on compacted streams whose field is empty or zero after the update
+	CompactionDefaults []interface{}
 }
 
 type StreamPublishResult struct {
 	MessageIds []string
 	Published  int
 	Failed     int
+	// Messages already published within the dedup window, their original IDs are part of MessageIds
+	Duplicates int
 	Errors     []error
 }
 
+type StreamRestoreResult struct {
+	Restored int
+	// Messages appended with a new ID because their original ID was not newer than the stream's last entry
+	Reassigned int
+	// ID of the last restored message
+	LastId string
+}
+
+type StreamSize struct {
+	// Estimated memory used by the stream in bytes
+	Bytes int64
+	// Number of messages in the stream
+	Length int64
+}
+
 type RedisStreamService interface {
 	// Create a new Redis stream for producing and consuming messages
 	CreateStream(params *CreateStreamParameters) error
+	// Change the retention and cleanup policy of an existing stream, returns the updated metadata
+	UpdateStream(params *UpdateStreamParameters) (*StreamMetadata, error)
 	// Count messages older than a given ID in a stream
 	CountMessagesOlderThan(streamName string, minId string, batchSize int64) (int64, error)
-	// Delete messages older than a given ID from a stream
-	DeleteMessagesOlderThan(streamName string, minId string) error
+	// Delete messages older than a given ID from a stream, fails when a newer fencing token trimmed the stream. A zero token is not fenced
+	DeleteMessagesOlderThan(streamName string, minId string, token int64) error
 	// Get messages older than a given ID from a stream
 	GetMessagesOlderThan(streamName string, minId string, count int64) ([]redis.XMessage, error)
+	// Get the number of messages in a stream
+	GetStreamLength(streamName string) (int64, error)
+	// Get up to count messages newer than afterId from a stream, an empty afterId starts from the oldest message
+	ScanMessages(streamName string, afterId string, count int64) ([]redis.XMessage, error)
+	// Delete messages by ID from a stream, returns the number of deleted messages. Fails when a newer fencing token
+	// trimmed the stream, a zero token is not fenced
+	DeleteMessages(streamName string, ids []string, token int64) (int64, error)
+	// Get messages newer than afterId and older than minId from a stream, an empty afterId starts from the oldest message
+	GetMessagesBetween(streamName string, afterId string, minId string, count int64) ([]redis.XMessage, error)
+	// Get the estimated memory usage and length of a stream
+	GetStreamSize(streamName string) (*StreamSize, error)
+	// Get the ID of the message at a zero based offset from the oldest message, empty when the stream is shorter
+	GetMessageIDAt(streamName string, offset int64, batchSize int64) (string, error)
+	// Get the ID of the oldest message still in a stream, empty when the stream has no messages
+	GetFirstMessageID(streamName string) (string, error)
+	// Get the ID of the last message appended to a stream
+	GetLastMessageID(streamName string) (string, error)
+	// Read messages newer than a given ID from a stream, blocking until messages arrive or the block duration elapses
+	ReadMessages(ctx context.Context, streamName string, lastId string, count int64, block time.Duration) ([]redis.XMessage, error)
+	// Set the number of deliveries after which messages of a stream are moved to a dead letter stream, zero disables dead lettering
+	SetDeadLetterPolicy(streamName string, maxDeliveries int64, deadLetterStream string) error
+	// Append previously read messages to a stream, keeping their original IDs when keepIds is set and the stream allows it
+	RestoreMessages(streamName string, messages []redis.XMessage, keepIds bool) (*StreamRestoreResult, error)
 	// Publish messages to a stream
 	PublishMessages(streamName string, messages [][]byte) (*StreamPublishResult, error)
+	// Publish messages to a stream in a single script, optionally deduplicating them by idempotency key or appending all of them or none
+	PublishBatch(params *PublishBatchParameters) (*StreamPublishResult, error)
 }
 
 // Implements RedisStreamServiceContract
@@ -53,6 +194,11 @@ type RedisStreamServiceImpl struct {
 	StreamMetadataService  StreamMetadataService
 	Logger                 logging.LoggerContract
 	GlobalRetentionOptions *config.RetentionConfig
+	MaxPublishBatchSize    int
+	DedupWindow            int64
+	DedupMaxEntries        int64
+	DefaultMessageFormat   string
+	SchemaRegistry         SchemaRegistryService
 }
 
 type RedisStreamServiceOptions struct {
@@ -60,15 +206,46 @@ type RedisStreamServiceOptions struct {
 	MetadataService        StreamMetadataService
 	RedisClient            RedisStreamClient
 	GlobalRetentionOptions *config.RetentionConfig
+	// Maximum number of messages sent to Redis in one pipelined round trip, defaults to DEFAULT_MAX_PUBLISH_BATCH_SIZE
+	MaxPublishBatchSize int
+	// How long in milliseconds idempotency keys are remembered, defaults to DEFAULT_DEDUP_WINDOW
+	DedupWindow int64
+	// Maximum number of idempotency keys remembered per stream, defaults to DEFAULT_DEDUP_MAX_ENTRIES
+	DedupMaxEntries int64
+	// Message format of streams created without one, defaults to MESSAGE_FORMAT_RAW
+	DefaultMessageFormat string
+	// Registry messages of streams bound to a schema subject are validated with, nil disables validation
+	SchemaRegistry SchemaRegistryService
 }
 
 func NewRedisStreamService(opts *RedisStreamServiceOptions, logger logging.LoggerContract) RedisStreamService {
+	if opts.MaxPublishBatchSize <= 0 {
+		opts.MaxPublishBatchSize = DEFAULT_MAX_PUBLISH_BATCH_SIZE
+	}
+
+	if opts.DedupWindow <= 0 {
+		opts.DedupWindow = DEFAULT_DEDUP_WINDOW
+	}
+
+	if opts.DedupMaxEntries <= 0 {
+		opts.DedupMaxEntries = DEFAULT_DEDUP_MAX_ENTRIES
+	}
+
+	if opts.DefaultMessageFormat == "" {
+		opts.DefaultMessageFormat = MESSAGE_FORMAT_RAW
+	}
+
 	return &RedisStreamServiceImpl{
 		Client:                 opts.RedisClient,
 		StreamMetadataService:  opts.MetadataService,
 		Logger:                 logger,
 		Ctx:                    opts.Ctx,
 		GlobalRetentionOptions: opts.GlobalRetentionOptions,
+		MaxPublishBatchSize:    opts.MaxPublishBatchSize,
+		DedupWindow:            opts.DedupWindow,
+		DedupMaxEntries:        opts.DedupMaxEntries,
+		DefaultMessageFormat:   opts.DefaultMessageFormat,
+		SchemaRegistry:         opts.SchemaRegistry,
 	}
 }
 
@@ -77,9 +254,102 @@ func (p *CreateStreamParameters) Validate() error {
 		return fmt.Errorf("stream name is required")
 	}
 
+	if p.MaxSize < 0 {
+		return fmt.Errorf("max size cannot be negative")
+	}
+
+	if p.MaxMessages < 0 {
+		return fmt.Errorf("max messages cannot be negative")
+	}
+
+	if p.CleanupPolicy == "compact" && p.CompactionKeyField == "" {
+		return fmt.Errorf("compaction key field is required for the compact cleanup policy")
+	}
+
+	if p.CompactionLag < 0 || p.TombstoneRetention < 0 {
+		return fmt.Errorf("compaction lag and tombstone retention cannot be negative")
+	}
+
+	if p.Schedule != "" {
+		if _, err := schedule.ParseCron(p.Schedule); err != nil {
+			return err
+		}
+	}
+
+	if _, err := schedule.ParseWindows(p.ArchiveWindows); err != nil {
+		return err
+	}
+
+	if !slices.Contains(config.VALID_MESSAGE_FORMATS, p.MessageFormat) {
+		return fmt.Errorf("message format must be one of %v", config.VALID_MESSAGE_FORMATS)
+	}
+
+	return ValidateDeadLetterPolicy(p.Name, p.MaxDeliveries, p.DeadLetterStream)
+}
+
+func (p *UpdateStreamParameters) Validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("stream name is required")
+	}
+
+	if p.CleanupPolicy != nil && !slices.Contains(config.VALID_CLEANUP_POLICIES, *p.CleanupPolicy) {
+		return fmt.Errorf("cleanup policy must be one of %v", config.VALID_CLEANUP_POLICIES)
+	}
+
+	if p.MaxAge != nil && *p.MaxAge <= 0 {
+		return fmt.Errorf("max age must be greater than 0")
+	}
+
+	for _, value := range []*int64{p.MaxSize, p.MaxMessages, p.CompactionLag, p.TombstoneRetention} {
+		if value != nil && *value < 0 {
+			return fmt.Errorf("max size, max messages, compaction lag and tombstone retention cannot be negative")
+		}
+	}
+
+	if p.Schedule != nil && *p.Schedule != "" {
+		if _, err := schedule.ParseCron(*p.Schedule); err != nil {
+			return err
+		}
+	}
+
+	if _, err := schedule.ParseWindows(p.ArchiveWindows); err != nil {
+		return err
+	}
+
+	if p.MessageFormat != nil && !slices.Contains(config.VALID_MESSAGE_FORMATS, *p.MessageFormat) {
+		return fmt.Errorf("message format must be one of %v", config.VALID_MESSAGE_FORMATS)
+	}
+
 	return nil
 }
 
+// Validates the dead letter settings of a stream
+func ValidateDeadLetterPolicy(streamName string, maxDeliveries int64, deadLetterStream string) error {
+	if maxDeliveries < 0 {
+		return fmt.Errorf("max deliveries cannot be negative")
+	}
+
+	if deadLetterStream == streamName {
+		return fmt.Errorf("dead letter stream must differ from the stream it receives messages from")
+	}
+
+	// Messages are moved by a script touching both streams, which Redis Cluster only allows within one slot
+	if deadLetterStream != "" && !InSameSlot(streamName, deadLetterStream) {
+		return fmt.Errorf("dead letter stream must share the hash tag of the stream it receives messages from, such as %s", GetSlotPrefix(streamName)+STREAM_DEAD_LETTER_SUFFIX)
+	}
+
+	return nil
+}
+
+// Gets the dead letter stream of a stream, falling back to the default name when dead lettering is enabled without one
+func GetDeadLetterStreamName(streamName string, maxDeliveries int64, deadLetterStream string) string {
+	if maxDeliveries > 0 && deadLetterStream == "" {
+		return GetSlotPrefix(streamName) + STREAM_DEAD_LETTER_SUFFIX
+	}
+
+	return deadLetterStream
+}
+
 func (r *StreamPublishResult) IncrementPublished() {
 	r.Published++
 }
@@ -112,7 +382,6 @@ func (s *RedisStreamServiceImpl) StreamExists(streamName string) (bool, error) {
 
 func (s *RedisStreamServiceImpl) CreateStream(params *CreateStreamParameters) error {
 	s.Logger.Debug("Creating stream...", zap.String("name", params.Name))
-	var cleanupPolicyBucket string
 
 	if params.MaxAge == 0 {
 		params.MaxAge = s.GlobalRetentionOptions.MaxAge
@@ -122,22 +391,50 @@ func (s *RedisStreamServiceImpl) CreateStream(params *CreateStreamParameters) er
 		params.CleanupPolicy = s.GlobalRetentionOptions.CleanupPolicy
 	}
 
+	if params.MaxMessages == 0 {
+		params.MaxMessages = s.GlobalRetentionOptions.MaxMessages
+	}
+
+	if params.CleanupPolicy == "compact" {
+		if params.CompactionKeyField == "" {
+			params.CompactionKeyField = s.GlobalRetentionOptions.CompactionKeyField
+		}
+
+		if params.CompactionLag == 0 {
+			params.CompactionLag = s.GlobalRetentionOptions.CompactionLag
+		}
+
+		if params.TombstoneRetention == 0 {
+			params.TombstoneRetention = s.GlobalRetentionOptions.TombstoneRetention
+		}
+	}
+
+	if params.Schedule == "" {
+		params.Schedule = s.GlobalRetentionOptions.Schedule
+	}
+
+	if len(params.ArchiveWindows) == 0 {
+		params.ArchiveWindows = s.GlobalRetentionOptions.ArchiveWindows
+	}
+
+	if params.MessageFormat == "" {
+		params.MessageFormat = s.DefaultMessageFormat
+	}
+
+	params.DeadLetterStream = GetDeadLetterStreamName(params.Name, params.MaxDeliveries, params.DeadLetterStream)
+
 	err := params.Validate()
 	if err != nil {
 		return err
 	}
 
-	switch params.CleanupPolicy {
-	case "delete":
-		cleanupPolicyBucket = STREAM_CLEANUP_BUCKET_DELETE
-	case "archive":
-		cleanupPolicyBucket = STREAM_CLEANUP_BUCKET_ARCHIVE
-	case "delete,archive":
-		cleanupPolicyBucket = STREAM_CLEANUP_BUCKET_DELETE_ARCHIVE
-	default:
-		cleanupPolicyBucket = STREAM_CLEANUP_BUCKET_DELETE
+	err = s.CheckSchemaSubject(params.SchemaSubject)
+	if err != nil {
+		return err
 	}
 
+	cleanupPolicyBucket := GetCleanupBucketKey(params.CleanupPolicy)
+
 	args := &redis.XAddArgs{
 		Stream: params.Name,
 		Values: map[string]interface{}{
@@ -155,10 +452,21 @@ func (s *RedisStreamServiceImpl) CreateStream(params *CreateStreamParameters) er
 	}
 
 	err = s.StreamMetadataService.WriteStreamMetadata(&StreamMetadata{
-		Name:          params.Name,
-		MaxAge:        params.MaxAge,
-		CleanupPolicy: params.CleanupPolicy,
-		CreatedAt:     time.Now().Unix(),
+		Name:               params.Name,
+		MaxAge:             params.MaxAge,
+		CleanupPolicy:      params.CleanupPolicy,
+		CreatedAt:          time.Now().Unix(),
+		MaxDeliveries:      params.MaxDeliveries,
+		DeadLetterStream:   params.DeadLetterStream,
+		MaxSize:            params.MaxSize,
+		MaxMessages:        params.MaxMessages,
+		CompactionKeyField: params.CompactionKeyField,
+		CompactionLag:      params.CompactionLag,
+		TombstoneRetention: params.TombstoneRetention,
+		Schedule:           params.Schedule,
+		ArchiveWindows:     params.ArchiveWindows,
+		MessageFormat:      params.MessageFormat,
+		SchemaSubject:      params.SchemaSubject,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to write stream metadata: %w", err)
@@ -175,10 +483,168 @@ func (s *RedisStreamServiceImpl) CreateStream(params *CreateStreamParameters) er
 		return fmt.Errorf("failed to create stream: %w", err)
 	}
 
+	if params.MaxDeliveries > 0 {
+		err = s.EnsureDeadLetterStream(params.DeadLetterStream, params.MaxAge, params.CleanupPolicy)
+		if err != nil {
+			return err
+		}
+	}
+
 	s.Logger.Debug("Stream created", zap.String("name", params.Name))
 	return nil
 }
 
+func (s *RedisStreamServiceImpl) SetDeadLetterPolicy(streamName string, maxDeliveries int64, deadLetterStream string) error {
+	deadLetterStream = GetDeadLetterStreamName(streamName, maxDeliveries, deadLetterStream)
+
+	err := ValidateDeadLetterPolicy(streamName, maxDeliveries, deadLetterStream)
+	if err != nil {
+		return err
+	}
+
+	exists, err := s.StreamExists(streamName)
+	if err != nil {
+		return fmt.Errorf("failed to check if stream exists: %w", err)
+	}
+
+	if !exists {
+		return StreamNotFoundError(streamName)
+	}
+
+	metadata, err := s.StreamMetadataService.GetStreamMetadata(utils.HashString(streamName))
+	if err != nil {
+		return fmt.Errorf("failed to get metadata for stream %s: %w", streamName, err)
+	}
+
+	if maxDeliveries > 0 {
+		err = s.EnsureDeadLetterStream(deadLetterStream, metadata.MaxAge, metadata.CleanupPolicy)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = s.StreamMetadataService.WriteDeadLetterPolicy(streamName, maxDeliveries, deadLetterStream)
+	if err != nil {
+		return fmt.Errorf("failed to write stream metadata: %w", err)
+	}
+
+	s.Logger.Debug("Dead letter policy updated", zap.String("stream", streamName), zap.Int64("max_deliveries", maxDeliveries), zap.String("dead_letter_stream", deadLetterStream))
+	return nil
+}
+
+func (s *RedisStreamServiceImpl) UpdateStream(params *UpdateStreamParameters) (*StreamMetadata, error) {
+	err := params.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := s.StreamExists(params.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check if stream exists: %w", err)
+	}
+
+	if !exists {
+		return nil, StreamNotFoundError(params.Name)
+	}
+
+	if params.SchemaSubject != nil {
+		if err := s.CheckSchemaSubject(*params.SchemaSubject); err != nil {
+			return nil, err
+		}
+	}
+
+	update := GetStreamMetadataUpdate(params)
+	// Like at creation, compaction settings left at zero fall back to the global retention settings
+	update.CompactionDefaults = []interface{}{
+		"compaction_key_field", s.GlobalRetentionOptions.CompactionKeyField,
+		"compaction_lag", strconv.FormatInt(s.GlobalRetentionOptions.CompactionLag, 10),
+		"tombstone_retention", strconv.FormatInt(s.GlobalRetentionOptions.TombstoneRetention, 10),
+	}
+
+	err = s.StreamMetadataService.UpdateStreamMetadata(update)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := s.StreamMetadataService.GetStreamMetadata(utils.HashString(params.Name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get metadata for stream %s: %w", params.Name, err)
+	}
+
+	s.Logger.Debug("Stream updated", zap.String("stream", params.Name), zap.String("cleanup_policy", metadata.CleanupPolicy), zap.Int64("max_age", metadata.MaxAge))
+	return metadata, nil
+}
+
+// Gets the metadata fields of the settings set in the update parameters, other settings are left untouched
+func GetStreamMetadataUpdate(params *UpdateStreamParameters) *StreamMetadataUpdate {
+	update := &StreamMetadataUpdate{Name: params.Name}
+
+	setInt := func(field string, value *int64) {
+		if value != nil {
+			update.Fields = append(update.Fields, field, strconv.FormatInt(*value, 10))
+		}
+	}
+	setString := func(field string, value *string) {
+		if value != nil {
+			update.Fields = append(update.Fields, field, *value)
+		}
+	}
+
+	if params.CleanupPolicy != nil {
+		update.CleanupPolicy = *params.CleanupPolicy
+	}
+
+	setInt("max_age", params.MaxAge)
+	setString("cleanup_policy", params.CleanupPolicy)
+	setInt("max_size", params.MaxSize)
+	setInt("max_messages", params.MaxMessages)
+	setString("compaction_key_field", params.CompactionKeyField)
+	setInt("compaction_lag", params.CompactionLag)
+	setInt("tombstone_retention", params.TombstoneRetention)
+	setString("schedule", params.Schedule)
+	if params.SetArchiveWindows {
+		update.Fields = append(update.Fields, "archive_windows", strings.Join(params.ArchiveWindows, ","))
+	}
+	setString("message_format", params.MessageFormat)
+	setString("schema_subject", params.SchemaSubject)
+	update.Fields = append(update.Fields, "updated_at", strconv.FormatInt(time.Now().Unix(), 10))
+
+	return update
+}
+
+// Checks that a stream can be bound to a schema subject, the subject needs at least one registered schema
+func (s *RedisStreamServiceImpl) CheckSchemaSubject(subject string) error {
+	if subject == "" || s.SchemaRegistry == nil {
+		return nil
+	}
+
+	_, err := s.SchemaRegistry.GetSubjectSchema(subject, 0)
+	return err
+}
+
+// Creates a dead letter stream with the retention of its source stream unless it already exists
+func (s *RedisStreamServiceImpl) EnsureDeadLetterStream(name string, maxAge int64, cleanupPolicy string) error {
+	exists, err := s.StreamExists(name)
+	if err != nil {
+		return fmt.Errorf("failed to check if dead letter stream exists: %w", err)
+	}
+
+	if exists {
+		return nil
+	}
+
+	err = s.CreateStream(&CreateStreamParameters{
+		Name:          name,
+		MaxAge:        maxAge,
+		CleanupPolicy: cleanupPolicy,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create dead letter stream %s: %w", name, err)
+	}
+
+	return nil
+}
+
 func (s *RedisStreamServiceImpl) CountMessagesOlderThan(streamName string, minId string, batchSize int64) (int64, error) {
 	if streamName == "" {
 		return 0, fmt.Errorf("stream name cannot be empty")
@@ -248,17 +714,23 @@ func (s *RedisStreamServiceImpl) CountMessagesOlderThan(streamName string, minId
 			break
 		}
 
-		lastId = messages[len(messages)-1].ID
+		// Exclusive start so the last message of the batch is not counted twice
+		lastId = "(" + messages[len(messages)-1].ID
 	}
 
 	return count, nil
 }
 
-func (s *RedisStreamServiceImpl) DeleteMessagesOlderThan(streamName string, minId string) error {
-	_, err := s.Client.XTrimMinID(s.Ctx, streamName, minId).Result()
+func (s *RedisStreamServiceImpl) DeleteMessagesOlderThan(streamName string, minId string, token int64) error {
+	keys := []string{streamName, GetStreamFencingTokenKey(streamName)}
+	trimmed, err := fencedTrimScript.Run(s.Ctx, s.Client, keys, token, minId).Int64()
 	if err != nil {
 		return fmt.Errorf("failed to delete messages from stream %s: %w", streamName, err)
 	}
+
+	if trimmed == -1 {
+		return FencedError(streamName, token)
+	}
 	return nil
 }
 
@@ -271,44 +743,258 @@ func (s *RedisStreamServiceImpl) GetMessagesOlderThan(streamName string, minId s
 	return messages, nil
 }
 
+func (s *RedisStreamServiceImpl) GetMessagesBetween(streamName string, afterId string, minId string, count int64) ([]redis.XMessage, error) {
+	// Use "-" to start from beginning, and minId as the end (exclusive) to match XTRIM MINID
+	start := "-"
+	if afterId != "" {
+		start = "(" + afterId
+	}
+
+	messages, err := s.Client.XRangeN(s.Ctx, streamName, start, "("+minId, count).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get messages from stream %s: %w", streamName, err)
+	}
+	return messages, nil
+}
+
+func (s *RedisStreamServiceImpl) ScanMessages(streamName string, afterId string, count int64) ([]redis.XMessage, error) {
+	start := "-"
+	if afterId != "" {
+		start = "(" + afterId
+	}
+
+	messages, err := s.Client.XRangeN(s.Ctx, streamName, start, "+", count).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get messages from stream %s: %w", streamName, err)
+	}
+	return messages, nil
+}
+
+func (s *RedisStreamServiceImpl) DeleteMessages(streamName string, ids []string, token int64) (int64, error) {
+	keys := []string{streamName, GetStreamFencingTokenKey(streamName)}
+	total := int64(0)
+
+	for batch := range slices.Chunk(ids, DELETE_MESSAGES_BATCH_SIZE) {
+		args := make([]interface{}, 0, len(batch)+1)
+		args = append(args, token)
+		for _, id := range batch {
+			args = append(args, id)
+		}
+
+		deleted, err := fencedDeleteScript.Run(s.Ctx, s.Client, keys, args...).Int64()
+		if err != nil {
+			return total, fmt.Errorf("failed to delete messages from stream %s: %w", streamName, err)
+		}
+
+		if deleted == -1 {
+			return total, FencedError(streamName, token)
+		}
+		total += deleted
+	}
+
+	return total, nil
+}
+
+// Gets the key holding the newest fencing token a stream was trimmed with, tagged so it is in the stream's cluster slot
+func GetStreamFencingTokenKey(streamName string) string {
+	return GetSlotPrefix(streamName) + STREAM_FENCING_TOKEN_SUFFIX
+}
+
+func (s *RedisStreamServiceImpl) GetStreamLength(streamName string) (int64, error) {
+	info, err := s.Client.XInfoStream(s.Ctx, streamName).Result()
+	if err != nil {
+		if err == redis.Nil || err.Error() == "ERR no such key" {
+			return 0, StreamNotFoundError(streamName)
+		}
+		return 0, fmt.Errorf("failed to get stream info for %s: %w", streamName, err)
+	}
+
+	return info.Length, nil
+}
+
+func (s *RedisStreamServiceImpl) GetStreamSize(streamName string) (*StreamSize, error) {
+	info, err := s.Client.XInfoStream(s.Ctx, streamName).Result()
+	if err != nil {
+		if err == redis.Nil || err.Error() == "ERR no such key" {
+			return nil, StreamNotFoundError(streamName)
+		}
+		return nil, fmt.Errorf("failed to get stream info for %s: %w", streamName, err)
+	}
+
+	// MEMORY USAGE samples a few entries of the stream, the result is an estimate
+	bytes, err := s.Client.MemoryUsage(s.Ctx, streamName).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get memory usage of stream %s: %w", streamName, err)
+	}
+
+	return &StreamSize{
+		Bytes:  bytes,
+		Length: info.Length,
+	}, nil
+}
+
+func (s *RedisStreamServiceImpl) GetMessageIDAt(streamName string, offset int64, batchSize int64) (string, error) {
+	if offset < 0 {
+		return "", fmt.Errorf("offset cannot be negative")
+	}
+
+	var skipped int64
+	start := "-"
+
+	for {
+		messages, err := s.Client.XRangeN(s.Ctx, streamName, start, "+", batchSize).Result()
+		if err != nil {
+			return "", fmt.Errorf("failed to get messages from stream %s: %w", streamName, err)
+		}
+
+		if skipped+int64(len(messages)) > offset {
+			return messages[offset-skipped].ID, nil
+		}
+
+		if int64(len(messages)) < batchSize {
+			return "", nil
+		}
+
+		skipped += int64(len(messages))
+		start = "(" + messages[len(messages)-1].ID
+	}
+}
+
+func (s *RedisStreamServiceImpl) GetFirstMessageID(streamName string) (string, error) {
+	info, err := s.Client.XInfoStream(s.Ctx, streamName).Result()
+	if err != nil {
+		if err == redis.Nil || err.Error() == "ERR no such key" {
+			return "", StreamNotFoundError(streamName)
+		}
+		return "", fmt.Errorf("failed to get stream info for %s: %w", streamName, err)
+	}
+
+	return info.FirstEntry.ID, nil
+}
+
+func (s *RedisStreamServiceImpl) GetLastMessageID(streamName string) (string, error) {
+	info, err := s.Client.XInfoStream(s.Ctx, streamName).Result()
+	if err != nil {
+		if err == redis.Nil || err.Error() == "ERR no such key" {
+			return "", StreamNotFoundError(streamName)
+		}
+		return "", fmt.Errorf("failed to get stream info for %s: %w", streamName, err)
+	}
+
+	return info.LastGeneratedID, nil
+}
+
+func (s *RedisStreamServiceImpl) ReadMessages(ctx context.Context, streamName string, lastId string, count int64, block time.Duration) ([]redis.XMessage, error) {
+	streams, err := s.Client.XRead(ctx, &redis.XReadArgs{
+		Streams: []string{streamName, lastId},
+		Count:   count,
+		Block:   block,
+	}).Result()
+	if err != nil {
+		// XREAD returns a nil reply when the block duration elapses without new messages
+		if err == redis.Nil {
+			return []redis.XMessage{}, nil
+		}
+		return nil, fmt.Errorf("failed to read messages from stream %s: %w", streamName, err)
+	}
+
+	if len(streams) == 0 {
+		return []redis.XMessage{}, nil
+	}
+
+	return streams[0].Messages, nil
+}
+
 // Publish messages to a stream
 func (s *RedisStreamServiceImpl) PublishMessages(streamName string, messages [][]byte) (*StreamPublishResult, error) {
-	result := StreamPublishResult{
-		MessageIds: make([]string, 0),
-		Published:  0,
-		Failed:     0,
-		Errors:     make([]error, 0),
+	return s.PublishBatch(&PublishBatchParameters{
+		Name:     streamName,
+		Messages: messages,
+	})
+}
+
+// Pipelines the XADDs of every batch of messages, failed messages do not stop the others from being appended
+func (s *RedisStreamServiceImpl) PublishPipelined(streamName string, messages []map[string]interface{}, result *StreamPublishResult) {
+	// The stream key maps to a single cluster slot, so every batch is pipelined to one node in one round trip
+	for start := 0; start < len(messages); start += s.MaxPublishBatchSize {
+		end := min(start+s.MaxPublishBatchSize, len(messages))
+		batch := messages[start:end]
+		cmds := make([]*redis.StringCmd, len(batch))
+
+		// Every command carries its own result, the pipeline error only repeats the first failed one
+		_, _ = s.Client.Pipelined(s.Ctx, func(pipe redis.Pipeliner) error {
+			for i, message := range batch {
+				cmds[i] = pipe.XAdd(s.Ctx, &redis.XAddArgs{
+					Stream: streamName,
+					Values: message,
+				})
+			}
+			return nil
+		})
+
+		for i, cmd := range cmds {
+			id, err := cmd.Result()
+			if err != nil {
+				result.IncrementFailed()
+				result.AddError(StreamPublishError(start+i, err))
+				continue
+			}
+
+			result.IncrementPublished()
+			result.AddMessageId(id)
+		}
 	}
-	// Check if stream exists
-	exists, err := s.StreamExists(streamName)
+}
+
+// Gets the metadata messages are published with, streams without metadata keep splitting content into key=value fields
+// and are not validated. Any other error fails the publish rather than storing messages in the wrong format
+func (s *RedisStreamServiceImpl) GetPublishMetadata(streamName string) (*StreamMetadata, error) {
+	metadata, err := s.StreamMetadataService.GetStreamMetadata(utils.HashString(streamName))
 	if err != nil {
-		return nil, fmt.Errorf("failed to check if stream exists: %w", err)
+		if _, ok := err.(*RedisStreamNotFoundError); ok {
+			// Streams created outside the broker have no metadata
+			s.Logger.Debug("Stream has no metadata, publishing key=value fields", zap.String("stream", streamName))
+			return &StreamMetadata{Name: streamName, MessageFormat: MESSAGE_FORMAT_KEY_VALUE}, nil
+		}
+		return nil, fmt.Errorf("failed to get metadata for stream %s: %w", streamName, err)
 	}
 
-	if !exists {
-		return nil, StreamNotFoundError(streamName)
-	}
+	return metadata, nil
+}
 
-	messagesToPublish := ByteSliceToRedisMessageMapSlice(messages)
+func (s *RedisStreamServiceImpl) RestoreMessages(streamName string, messages []redis.XMessage, keepIds bool) (*StreamRestoreResult, error) {
+	result := &StreamRestoreResult{}
 
-	for _, message := range messagesToPublish {
+	for _, msg := range messages {
 		args := &redis.XAddArgs{
 			Stream: streamName,
-			Values: message,
+			// Never create the stream implicitly, it would have no metadata or retention
+			NoMkStream: true,
+			Values:     msg.Values,
+		}
+		if keepIds {
+			args.ID = msg.ID
 		}
 
 		id, err := s.Client.XAdd(s.Ctx, args).Result()
+		// Streams only accept IDs newer than their last entry, fall back to a new ID otherwise
+		if err != nil && keepIds && strings.Contains(err.Error(), "equal or smaller than the target stream top item") {
+			args.ID = ""
+			id, err = s.Client.XAdd(s.Ctx, args).Result()
+			if err == nil {
+				result.Reassigned++
+			}
+		}
 		if err != nil {
-			streamPublishError := StreamPublishError(err)
-			result.IncrementFailed()
-			result.AddError(streamPublishError)
-			continue
+			if err == redis.Nil {
+				return result, StreamNotFoundError(streamName)
+			}
+			return result, fmt.Errorf("failed to restore message %s to stream %s: %w", msg.ID, streamName, err)
 		}
 
-		result.IncrementPublished()
-
-		result.AddMessageId(id)
+		result.Restored++
+		result.LastId = id
 	}
 
-	return &result, nil
+	return result, nil
 }
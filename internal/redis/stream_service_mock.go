@@ -1,6 +1,9 @@
 package redis
 
 import (
+	"context"
+	"time"
+
 	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/mock"
 )
@@ -18,13 +21,21 @@ func (m *RedisStreamServiceMock) StreamExists(streamName string) (bool, error) {
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *RedisStreamServiceMock) UpdateStream(params *UpdateStreamParameters) (*StreamMetadata, error) {
+	args := m.Called(params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*StreamMetadata), args.Error(1)
+}
+
 func (m *RedisStreamServiceMock) CreateStream(params *CreateStreamParameters) error {
 	args := m.Called(params)
 	return args.Error(0)
 }
 
-func (m *RedisStreamServiceMock) DeleteMessagesOlderThan(streamName string, minId string) error {
-	args := m.Called(streamName, minId)
+func (m *RedisStreamServiceMock) DeleteMessagesOlderThan(streamName string, minId string, token int64) error {
+	args := m.Called(streamName, minId, token)
 	return args.Error(0)
 }
 
@@ -38,6 +49,52 @@ func (m *RedisStreamServiceMock) GetMessagesOlderThan(streamName string, minId s
 	return args.Get(0).([]redis.XMessage), args.Error(1)
 }
 
+func (m *RedisStreamServiceMock) GetStreamLength(streamName string) (int64, error) {
+	args := m.Called(streamName)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *RedisStreamServiceMock) GetStreamSize(streamName string) (*StreamSize, error) {
+	args := m.Called(streamName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*StreamSize), args.Error(1)
+}
+
+func (m *RedisStreamServiceMock) GetMessageIDAt(streamName string, offset int64, batchSize int64) (string, error) {
+	args := m.Called(streamName, offset, batchSize)
+	return args.String(0), args.Error(1)
+}
+
+func (m *RedisStreamServiceMock) ScanMessages(streamName string, afterId string, count int64) ([]redis.XMessage, error) {
+	args := m.Called(streamName, afterId, count)
+	return args.Get(0).([]redis.XMessage), args.Error(1)
+}
+
+func (m *RedisStreamServiceMock) DeleteMessages(streamName string, ids []string, token int64) (int64, error) {
+	args := m.Called(streamName, ids, token)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *RedisStreamServiceMock) GetMessagesBetween(streamName string, afterId string, minId string, count int64) ([]redis.XMessage, error) {
+	args := m.Called(streamName, afterId, minId, count)
+	return args.Get(0).([]redis.XMessage), args.Error(1)
+}
+
+func (m *RedisStreamServiceMock) GetLastMessageID(streamName string) (string, error) {
+	args := m.Called(streamName)
+	return args.String(0), args.Error(1)
+}
+
+func (m *RedisStreamServiceMock) ReadMessages(ctx context.Context, streamName string, lastId string, count int64, block time.Duration) ([]redis.XMessage, error) {
+	args := m.Called(ctx, streamName, lastId, count, block)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]redis.XMessage), args.Error(1)
+}
+
 func (m *RedisStreamServiceMock) PublishMessages(streamName string, messages [][]byte) (*StreamPublishResult, error) {
 	args := m.Called(streamName, messages)
 	if args.Get(0) == nil {
@@ -45,3 +102,26 @@ func (m *RedisStreamServiceMock) PublishMessages(streamName string, messages [][
 	}
 	return args.Get(0).(*StreamPublishResult), args.Error(1)
 }
+
+func (m *RedisStreamServiceMock) PublishBatch(params *PublishBatchParameters) (*StreamPublishResult, error) {
+	args := m.Called(params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*StreamPublishResult), args.Error(1)
+}
+
+func (m *RedisStreamServiceMock) SetDeadLetterPolicy(streamName string, maxDeliveries int64, deadLetterStream string) error {
+	args := m.Called(streamName, maxDeliveries, deadLetterStream)
+	return args.Error(0)
+}
+
+func (m *RedisStreamServiceMock) GetFirstMessageID(streamName string) (string, error) {
+	args := m.Called(streamName)
+	return args.String(0), args.Error(1)
+}
+
+func (m *RedisStreamServiceMock) RestoreMessages(streamName string, messages []redis.XMessage, keepIds bool) (*StreamRestoreResult, error) {
+	args := m.Called(streamName, messages, keepIds)
+	return args.Get(0).(*StreamRestoreResult), args.Error(1)
+}
@@ -2,11 +2,15 @@ package redis
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	rdb "github.com/redis/go-redis/v9"
 	"github.com/streamweaverio/broker/internal/config"
 	"github.com/streamweaverio/broker/internal/testutils"
+	"github.com/streamweaverio/broker/pkg/utils"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -58,11 +62,166 @@ func TestRedisStreamService_CreateStream(t *testing.T) {
 
 		client.AssertExpectations(t)
 	})
+
+	t.Run("CreateStream falls back to the global max messages", func(t *testing.T) {
+		service, client, metadataService := setupRedisStreamService()
+		service.(*RedisStreamServiceImpl).GlobalRetentionOptions.MaxMessages = 5000
+		params := &CreateStreamParameters{
+			Name:          "test-stream",
+			CleanupPolicy: "delete",
+		}
+
+		metadataService.On("AddToRegistry", params.Name).Return(nil)
+		metadataService.On("WriteStreamMetadata", mock.MatchedBy(func(value *StreamMetadata) bool {
+			return value.MaxMessages == 5000
+		})).Return(nil)
+		metadataService.On("AddToCleanupBucket", params.Name, STREAM_CLEANUP_BUCKET_DELETE).Return(nil)
+		client.On("XAdd", mock.Anything, mock.Anything).Return(&rdb.StringCmd{})
+		client.On("XDel", mock.Anything, "test-stream", mock.Anything).Return(&rdb.IntCmd{})
+
+		err := service.CreateStream(params)
+		assert.NoError(t, err)
+
+		metadataService.AssertExpectations(t)
+	})
+}
+
+func TestRedisStreamService_CreateStreamMessageFormat(t *testing.T) {
+	testCases := []struct {
+		name          string
+		defaultFormat string
+		messageFormat string
+		expected      string
+	}{
+		{name: "Default to raw payloads", expected: MESSAGE_FORMAT_RAW},
+		{name: "Default to the configured message format", defaultFormat: MESSAGE_FORMAT_KEY_VALUE, expected: MESSAGE_FORMAT_KEY_VALUE},
+		{name: "Opt a stream into key=value fields", messageFormat: MESSAGE_FORMAT_KEY_VALUE, expected: MESSAGE_FORMAT_KEY_VALUE},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			client := &MockRedisClient{}
+			metadataService := NewStreamMetadataServiceMock()
+			service := NewRedisStreamService(&RedisStreamServiceOptions{
+				Ctx:                    context.Background(),
+				MetadataService:        metadataService,
+				RedisClient:            client,
+				GlobalRetentionOptions: &config.RetentionConfig{MaxAge: 3600000},
+				DefaultMessageFormat:   testCase.defaultFormat,
+			}, testutils.NewMockLogger())
+
+			metadataService.On("AddToRegistry", "test-stream").Return(nil)
+			metadataService.On("WriteStreamMetadata", mock.MatchedBy(func(value *StreamMetadata) bool {
+				return value.MessageFormat == testCase.expected
+			})).Return(nil)
+			metadataService.On("AddToCleanupBucket", "test-stream", STREAM_CLEANUP_BUCKET_DELETE).Return(nil)
+			client.On("XAdd", mock.Anything, mock.Anything).Return(&rdb.StringCmd{})
+			client.On("XDel", mock.Anything, "test-stream", mock.Anything).Return(&rdb.IntCmd{})
+
+			err := service.CreateStream(&CreateStreamParameters{Name: "test-stream", CleanupPolicy: "delete", MessageFormat: testCase.messageFormat})
+
+			assert.NoError(t, err)
+			metadataService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRedisStreamService_CreateStreamWithDeadLetterStream(t *testing.T) {
+	service, client, metadataService := setupRedisStreamService()
+	params := &CreateStreamParameters{
+		Name:          "test-stream",
+		MaxAge:        3600000,
+		CleanupPolicy: "delete",
+		MaxDeliveries: 5,
+	}
+
+	metadataService.On("AddToRegistry", mock.Anything).Return(nil)
+	metadataService.On("WriteStreamMetadata", mock.MatchedBy(func(value *StreamMetadata) bool {
+		return value.Name == "test-stream" && value.MaxDeliveries == 5 && value.DeadLetterStream == "{test-stream}.dlq"
+	})).Return(nil).Once()
+	metadataService.On("WriteStreamMetadata", mock.MatchedBy(func(value *StreamMetadata) bool {
+		return value.Name == "{test-stream}.dlq" && value.MaxAge == params.MaxAge && value.MaxDeliveries == 0
+	})).Return(nil).Once()
+	metadataService.On("AddToCleanupBucket", mock.Anything, STREAM_CLEANUP_BUCKET_DELETE).Return(nil)
+	client.On("XAdd", mock.Anything, mock.Anything).Return(&rdb.StringCmd{})
+	client.On("XDel", mock.Anything, mock.Anything, mock.Anything).Return(&rdb.IntCmd{})
+
+	// The dead letter stream does not exist yet
+	infoCmd := &rdb.XInfoStreamCmd{}
+	infoCmd.SetErr(errors.New("ERR no such key"))
+	client.On("XInfoStream", mock.Anything, "{test-stream}.dlq").Return(infoCmd)
+
+	err := service.CreateStream(params)
+
+	assert.NoError(t, err)
+	metadataService.AssertExpectations(t)
+	client.AssertExpectations(t)
+}
+
+func TestRedisStreamService_UpdateStream(t *testing.T) {
+	t.Run("Change the retention and cleanup policy of a stream", func(t *testing.T) {
+		service, client, metadataService := setupRedisStreamService()
+		maxAge := int64(7200000)
+		cleanupPolicy := "archive"
+
+		infoCmd := &rdb.XInfoStreamCmd{}
+		infoCmd.SetVal(&rdb.XInfoStream{})
+		client.On("XInfoStream", mock.Anything, "test-stream").Return(infoCmd)
+		// Only the settings in the update are written, so concurrent changes of other settings are kept
+		metadataService.On("UpdateStreamMetadata", mock.MatchedBy(func(update *StreamMetadataUpdate) bool {
+			return update.Name == "test-stream" && update.CleanupPolicy == cleanupPolicy && len(update.Fields) == 6 &&
+				update.Fields[0] == "max_age" && update.Fields[1] == "7200000" &&
+				update.Fields[2] == "cleanup_policy" && update.Fields[3] == cleanupPolicy && update.Fields[4] == "updated_at"
+		})).Return(nil)
+		metadataService.On("GetStreamMetadata", utils.HashString("test-stream")).Return(&StreamMetadata{
+			Name:          "test-stream",
+			MaxAge:        maxAge,
+			CleanupPolicy: cleanupPolicy,
+			MaxMessages:   100,
+		}, nil)
+
+		metadata, err := service.UpdateStream(&UpdateStreamParameters{
+			Name:          "test-stream",
+			MaxAge:        &maxAge,
+			CleanupPolicy: &cleanupPolicy,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "archive", metadata.CleanupPolicy)
+		metadataService.AssertExpectations(t)
+	})
+
+	t.Run("Reject an unknown cleanup policy", func(t *testing.T) {
+		service, _, metadataService := setupRedisStreamService()
+		cleanupPolicy := "shred"
+
+		_, err := service.UpdateStream(&UpdateStreamParameters{
+			Name:          "test-stream",
+			CleanupPolicy: &cleanupPolicy,
+		})
+
+		assert.Error(t, err)
+		metadataService.AssertNotCalled(t, "UpdateStreamMetadata", mock.Anything)
+	})
+
+	t.Run("Reject an unknown message format", func(t *testing.T) {
+		service, _, metadataService := setupRedisStreamService()
+		messageFormat := "csv"
+
+		_, err := service.UpdateStream(&UpdateStreamParameters{
+			Name:          "test-stream",
+			MessageFormat: &messageFormat,
+		})
+
+		assert.Error(t, err)
+		metadataService.AssertNotCalled(t, "UpdateStreamMetadata", mock.Anything)
+	})
 }
 
 func TestRedisStreamService_PublishMessages(t *testing.T) {
 	t.Run("Publish multiple messages successfully", func(t *testing.T) {
-		service, client, _ := setupRedisStreamService()
+		service, client, metadataService := setupRedisStreamService()
+		metadataService.On("GetStreamMetadata", mock.Anything).Return(&StreamMetadata{MessageFormat: MESSAGE_FORMAT_KEY_VALUE}, nil)
 		streamName := "test-stream"
 		messages := [][]byte{
 			[]byte("event_name=login"),
@@ -85,6 +244,7 @@ func TestRedisStreamService_PublishMessages(t *testing.T) {
 				return args.Stream == streamName && ok && len(values) > 0
 			})).Return(cmdVal).Once()
 		}
+		client.On("Pipelined", mock.Anything).Return(nil)
 
 		result, err := service.PublishMessages(streamName, messages)
 
@@ -92,6 +252,53 @@ func TestRedisStreamService_PublishMessages(t *testing.T) {
 		assert.Equal(t, len(messages), result.Published)
 		assert.Equal(t, expectedIds, result.MessageIds)
 		client.AssertExpectations(t)
+		client.AssertNumberOfCalls(t, "Pipelined", 1)
+	})
+
+	t.Run("Split messages into batches and report failures per message", func(t *testing.T) {
+		client := &MockRedisClient{}
+		metadataService := NewStreamMetadataServiceMock()
+		metadataService.On("GetStreamMetadata", mock.Anything).Return(&StreamMetadata{MessageFormat: MESSAGE_FORMAT_KEY_VALUE}, nil)
+		service := NewRedisStreamService(&RedisStreamServiceOptions{
+			Ctx:                    context.Background(),
+			MetadataService:        metadataService,
+			RedisClient:            client,
+			GlobalRetentionOptions: &config.RetentionConfig{},
+			MaxPublishBatchSize:    2,
+		}, testutils.NewMockLogger())
+		streamName := "test-stream"
+		messages := [][]byte{
+			[]byte("n=0"),
+			[]byte("n=1"),
+			[]byte("n=2"),
+		}
+
+		infoCmd := &rdb.XInfoStreamCmd{}
+		infoCmd.SetVal(&rdb.XInfoStream{})
+		client.On("XInfoStream", mock.Anything, streamName).Return(infoCmd)
+
+		failedCmd := &rdb.StringCmd{}
+		failedCmd.SetErr(errors.New("OOM command not allowed"))
+		for i, id := range []string{"1-0", "", "2-0"} {
+			cmd := rdb.NewStringResult(id, nil)
+			if id == "" {
+				cmd = failedCmd
+			}
+			value := fmt.Sprintf("%d", i)
+			client.On("XAdd", mock.Anything, mock.MatchedBy(func(args *rdb.XAddArgs) bool {
+				return args.Values.(map[string]interface{})["n"] == value
+			})).Return(cmd).Once()
+		}
+		client.On("Pipelined", mock.Anything).Return(nil)
+
+		result, err := service.PublishMessages(streamName, messages)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, result.Published)
+		assert.Equal(t, 1, result.Failed)
+		assert.Equal(t, []string{"1-0", "2-0"}, result.MessageIds)
+		assert.Equal(t, 1, result.Errors[0].(*RedisStreamPublishError).Index)
+		client.AssertNumberOfCalls(t, "Pipelined", 2)
 	})
 
 	t.Run("Return an error if stream does not exist", func(t *testing.T) {
@@ -116,3 +323,168 @@ func TestRedisStreamService_PublishMessages(t *testing.T) {
 		client.AssertExpectations(t)
 	})
 }
+
+func TestRedisStreamService_ReadMessages(t *testing.T) {
+	t.Run("Return messages newer than the last ID", func(t *testing.T) {
+		service, client, _ := setupRedisStreamService()
+		streamName := "test-stream"
+
+		cmd := &rdb.XStreamSliceCmd{}
+		cmd.SetVal([]rdb.XStream{
+			{Stream: streamName, Messages: []rdb.XMessage{{ID: "2-0"}, {ID: "3-0"}}},
+		})
+		client.On("XRead", mock.Anything, mock.MatchedBy(func(args *rdb.XReadArgs) bool {
+			return args.Streams[0] == streamName && args.Streams[1] == "1-0" && args.Count == 10
+		})).Return(cmd)
+
+		messages, err := service.ReadMessages(context.Background(), streamName, "1-0", 10, time.Second)
+
+		assert.NoError(t, err)
+		assert.Len(t, messages, 2)
+		client.AssertExpectations(t)
+	})
+
+	t.Run("Return no messages when the block timeout elapses", func(t *testing.T) {
+		service, client, _ := setupRedisStreamService()
+		streamName := "test-stream"
+
+		cmd := &rdb.XStreamSliceCmd{}
+		cmd.SetErr(rdb.Nil)
+		client.On("XRead", mock.Anything, mock.Anything).Return(cmd)
+
+		messages, err := service.ReadMessages(context.Background(), streamName, "1-0", 10, time.Second)
+
+		assert.NoError(t, err)
+		assert.Empty(t, messages)
+		client.AssertExpectations(t)
+	})
+}
+
+func TestRedisStreamService_RestoreMessages(t *testing.T) {
+	service, client, _ := setupRedisStreamService()
+	messages := []rdb.XMessage{
+		{ID: "1-0", Values: map[string]interface{}{"event_name": "login"}},
+		{ID: "2-0", Values: map[string]interface{}{"event_name": "logout"}},
+	}
+
+	client.On("XAdd", mock.Anything, mock.MatchedBy(func(args *rdb.XAddArgs) bool {
+		return args.ID == "1-0" && args.NoMkStream
+	})).Return(rdb.NewStringResult("1-0", nil)).Once()
+	// 2-0 is older than the stream's last entry, so it is appended with a new ID
+	client.On("XAdd", mock.Anything, mock.MatchedBy(func(args *rdb.XAddArgs) bool {
+		return args.ID == "2-0"
+	})).Return(rdb.NewStringResult("", errors.New("ERR The ID specified in XADD is equal or smaller than the target stream top item"))).Once()
+	client.On("XAdd", mock.Anything, mock.MatchedBy(func(args *rdb.XAddArgs) bool {
+		return args.ID == ""
+	})).Return(rdb.NewStringResult("9-0", nil)).Once()
+
+	result, err := service.RestoreMessages("test-stream", messages, true)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.Restored)
+	assert.Equal(t, 1, result.Reassigned)
+	assert.Equal(t, "9-0", result.LastId)
+	client.AssertExpectations(t)
+}
+
+func TestRedisStreamService_DeleteMessagesOlderThan(t *testing.T) {
+	t.Run("Trim the stream with the fencing token", func(t *testing.T) {
+		service, client, _ := setupRedisStreamService()
+		client.On("Eval", mock.Anything, fencedTrimScriptSource, []string{"test-stream", "{test-stream}:fencing_token"}, []interface{}{int64(4), "10-0"}).
+			Return(rdb.NewCmdResult(int64(3), nil))
+
+		err := service.DeleteMessagesOlderThan("test-stream", "10-0", 4)
+
+		assert.NoError(t, err)
+		client.AssertExpectations(t)
+	})
+
+	t.Run("Fail when a newer fencing token trimmed the stream", func(t *testing.T) {
+		service, client, _ := setupRedisStreamService()
+		client.On("Eval", mock.Anything, fencedTrimScriptSource, mock.Anything, mock.Anything).Return(rdb.NewCmdResult(int64(-1), nil))
+
+		err := service.DeleteMessagesOlderThan("test-stream", "10-0", 4)
+
+		assert.Equal(t, FencedError("test-stream", 4), err)
+	})
+}
+
+func TestRedisStreamService_DeleteMessages(t *testing.T) {
+	t.Run("Delete messages by ID", func(t *testing.T) {
+		service, client, _ := setupRedisStreamService()
+		client.On("Eval", mock.Anything, fencedDeleteScriptSource, []string{"test-stream", "{test-stream}:fencing_token"}, []interface{}{int64(4), "1-0", "2-0"}).
+			Return(rdb.NewCmdResult(int64(2), nil))
+
+		deleted, err := service.DeleteMessages("test-stream", []string{"1-0", "2-0"}, 4)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), deleted)
+	})
+
+	t.Run("Delete more IDs than a script can unpack in batches", func(t *testing.T) {
+		service, client, _ := setupRedisStreamService()
+		ids := make([]string, 8500)
+		for i := range ids {
+			ids[i] = fmt.Sprintf("%d-0", i+1)
+		}
+
+		client.On("Eval", mock.Anything, fencedDeleteScriptSource, []string{"test-stream", "{test-stream}:fencing_token"}, mock.MatchedBy(func(args []interface{}) bool {
+			return args[0] == int64(4) && len(args) == DELETE_MESSAGES_BATCH_SIZE+1
+		})).Return(rdb.NewCmdResult(int64(DELETE_MESSAGES_BATCH_SIZE), nil)).Times(8)
+		client.On("Eval", mock.Anything, fencedDeleteScriptSource, []string{"test-stream", "{test-stream}:fencing_token"}, mock.MatchedBy(func(args []interface{}) bool {
+			return len(args) == 501 && args[1] == "8001-0" && args[500] == "8500-0"
+		})).Return(rdb.NewCmdResult(int64(500), nil)).Once()
+
+		deleted, err := service.DeleteMessages("test-stream", ids, 4)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(8500), deleted)
+		client.AssertExpectations(t)
+	})
+}
+
+func TestRedisStreamService_GetStreamSize(t *testing.T) {
+	service, client, _ := setupRedisStreamService()
+
+	info := &rdb.XInfoStreamCmd{}
+	info.SetVal(&rdb.XInfoStream{Length: 1000})
+	client.On("XInfoStream", mock.Anything, "test-stream").Return(info)
+	client.On("MemoryUsage", mock.Anything, "test-stream", mock.Anything).Return(rdb.NewIntResult(512000, nil))
+
+	size, err := service.GetStreamSize("test-stream")
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(512000), size.Bytes)
+	assert.Equal(t, int64(1000), size.Length)
+	client.AssertExpectations(t)
+}
+
+func TestRedisStreamService_GetMessageIDAt(t *testing.T) {
+	t.Run("Page through the stream until the offset is reached", func(t *testing.T) {
+		service, client, _ := setupRedisStreamService()
+
+		client.On("XRangeN", mock.Anything, "test-stream", "-", "+", int64(2)).
+			Return(rdb.NewXMessageSliceCmdResult([]rdb.XMessage{{ID: "1-0"}, {ID: "2-0"}}, nil))
+		client.On("XRangeN", mock.Anything, "test-stream", "(2-0", "+", int64(2)).
+			Return(rdb.NewXMessageSliceCmdResult([]rdb.XMessage{{ID: "3-0"}, {ID: "4-0"}}, nil))
+
+		id, err := service.GetMessageIDAt("test-stream", 3, 2)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "4-0", id)
+		client.AssertExpectations(t)
+	})
+
+	t.Run("Return an empty ID when the stream is shorter than the offset", func(t *testing.T) {
+		service, client, _ := setupRedisStreamService()
+
+		client.On("XRangeN", mock.Anything, "test-stream", "-", "+", int64(10)).
+			Return(rdb.NewXMessageSliceCmdResult([]rdb.XMessage{{ID: "1-0"}}, nil))
+
+		id, err := service.GetMessageIDAt("test-stream", 5, 10)
+
+		assert.NoError(t, err)
+		assert.Empty(t, id)
+		client.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,182 @@
+package retention
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/streamweaverio/broker/internal/archiver"
+	"github.com/streamweaverio/broker/internal/logging"
+	"github.com/streamweaverio/broker/internal/redis"
+	"github.com/streamweaverio/broker/internal/schedule"
+	"go.uber.org/zap"
+)
+
+// Applies the cleanup policy of a stream to its messages older than a minimum ID, shared by all retention policies
+type StreamCleaner struct {
+	Metadataservice  redis.StreamMetadataService
+	Streamservice    redis.RedisStreamService
+	Archiver         archiver.Archiver
+	Logger           logging.LoggerContract
+	MessageBatchSize int64
+	WorkerPool       *WorkerPoolOptions
+	// Streams the policy is still being applied to, possibly from a previous run that timed out
+	inFlight sync.Map
+}
+
+type StreamCleanerOptions struct {
+	StreamMetadataservice redis.StreamMetadataService
+	Streamservice         redis.RedisStreamService
+	Archiver              archiver.Archiver
+	MessageBatchSize      int64
+	WorkerPool            *WorkerPoolOptions
+}
+
+func NewStreamCleaner(opts *StreamCleanerOptions, logger logging.LoggerContract) *StreamCleaner {
+	if opts.MessageBatchSize <= 0 {
+		opts.MessageBatchSize = 1000
+	}
+
+	return &StreamCleaner{
+		Metadataservice:  opts.StreamMetadataservice,
+		Streamservice:    opts.Streamservice,
+		Archiver:         opts.Archiver,
+		Logger:           logger,
+		MessageBatchSize: opts.MessageBatchSize,
+		WorkerPool:       NewWorkerPoolOptions(opts.WorkerPool),
+	}
+}
+
+// Archives messages older than the minID from the stream which are newer than the stream's archive watermark. Every
+// block is fenced with the token so an instance that lost the retention lease stops archiving
+func (s *StreamCleaner) ArchiveMessages(ctx context.Context, stream string, minID string, token int64) error {
+	watermark, err := s.Metadataservice.GetArchiveWatermark(stream)
+	if err != nil {
+		return fmt.Errorf("failed to get archive watermark of stream %s: %w", stream, err)
+	}
+
+	var archived int
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		messages, err := s.Streamservice.GetMessagesBetween(stream, watermark, minID, s.MessageBatchSize)
+		if err != nil {
+			return fmt.Errorf("failed to get messages from stream %s: %w", stream, err)
+		}
+
+		if len(messages) == 0 {
+			break
+		}
+
+		if token != 0 {
+			if err := s.Metadataservice.CheckFencingToken(stream, token); err != nil {
+				return fmt.Errorf("failed to archive messages: %w", err)
+			}
+		}
+
+		// Block IDs derive from the first and last message ID, so a batch archived again after a crash finds its block
+		// already complete and leaves it as is
+		err = s.Archiver.Archive(ctx, stream, messages)
+		if err != nil {
+			return fmt.Errorf("failed to archive messages: %w", err)
+		}
+
+		// Another archiver moving the watermark stops this one, readers skip messages of blocks that overlap
+		last := messages[len(messages)-1].ID
+		err = s.Metadataservice.SetArchiveWatermark(stream, watermark, last, token)
+		if err != nil {
+			return fmt.Errorf("failed to advance archive watermark of stream %s: %w", stream, err)
+		}
+		watermark = last
+
+		archived += len(messages)
+
+		if int64(len(messages)) < s.MessageBatchSize {
+			break
+		}
+	}
+
+	if archived == 0 {
+		s.Logger.Info("No messages to archive", zap.String("stream", stream))
+		return nil
+	}
+
+	s.Logger.Debug("Archived messages", zap.String("stream", stream), zap.Int("count", archived), zap.String("last_archived_id", watermark))
+	return nil
+}
+
+// Deletes messages older than the minID from the stream
+func (s *StreamCleaner) DeleteMessages(stream string, minID string, token int64) error {
+	err := s.Streamservice.DeleteMessagesOlderThan(stream, minID, token)
+	if err != nil {
+		return fmt.Errorf("failed to delete messages from stream %s: %w", stream, err)
+	}
+	return nil
+}
+
+// Deletes and archives messages older than the minID from the stream
+func (s *StreamCleaner) DeleteAndArchiveMessages(ctx context.Context, stream string, minID string, token int64) error {
+	// Archive messages first
+	err := s.ArchiveMessages(ctx, stream, minID, token)
+	if err != nil {
+		return err
+	}
+	// Delete messages
+	err = s.DeleteMessages(stream, minID, token)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Cleans up the messages older than the minID of a stream over its size or count limit. Compacted streams are compacted
+// once per run by the time policy, so their limits trim the oldest messages left after compaction instead
+func (s *StreamCleaner) ApplyLimitPolicy(ctx context.Context, meta *redis.StreamMetadata, minID string, token int64) error {
+	if meta.CleanupPolicy == "compact" {
+		s.Logger.Info("Trimming compacted stream to its limit...", zap.String("stream", meta.Name), zap.String("min_id", minID))
+		return s.DeleteMessages(meta.Name, minID, token)
+	}
+
+	return s.ApplyCleanupPolicy(ctx, meta, minID, token)
+}
+
+// Applies the cleanup policy to the stream, cleanup policies archiving messages are deferred while the stream is outside its archive windows.
+// Writes are fenced with the token of the retention lease, zero when there is no leader election
+func (s *StreamCleaner) ApplyCleanupPolicy(ctx context.Context, meta *redis.StreamMetadata, minID string, token int64) error {
+	stream := meta.Name
+	policy := meta.CleanupPolicy
+
+	if policy == "archive" || policy == "delete,archive" {
+		windows, err := schedule.ParseWindows(meta.ArchiveWindows)
+		if err != nil {
+			return fmt.Errorf("invalid archive windows of stream %s: %w", stream, err)
+		}
+
+		if !schedule.InWindows(windows, time.Now()) {
+			s.Logger.Debug("Deferring cleanup, stream is outside its archive windows", zap.String("stream", stream), zap.Strings("archive_windows", meta.ArchiveWindows))
+			return nil
+		}
+	}
+
+	switch policy {
+	case "delete":
+		s.Logger.Info("Deleting older messages from stream...", zap.String("stream", stream), zap.String("min_id", minID))
+		return s.DeleteMessages(stream, minID, token)
+	case "archive":
+		s.Logger.Info("Archiving older messages from stream...", zap.String("stream", stream), zap.String("min_id", minID))
+		return s.ArchiveMessages(ctx, stream, minID, token)
+	case "delete,archive":
+		s.Logger.Info("Deleting and archiving older messages from stream...", zap.String("stream", stream), zap.String("min_id", minID))
+		return s.DeleteAndArchiveMessages(ctx, stream, minID, token)
+	case "compact":
+		// Compaction is bounded by the stream's compaction lag instead of the retention policy's min ID
+		s.Logger.Info("Compacting stream...", zap.String("stream", stream))
+		return s.CompactMessages(ctx, stream, token)
+	default:
+		return fmt.Errorf("unknown cleanup policy: %s", policy)
+	}
+}
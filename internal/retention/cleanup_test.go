@@ -0,0 +1,152 @@
+package retention
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	rdb "github.com/redis/go-redis/v9"
+	"github.com/streamweaverio/broker/internal/archiver"
+	"github.com/streamweaverio/broker/internal/redis"
+	"github.com/streamweaverio/broker/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupStreamCleaner() (*StreamCleaner, *redis.StreamMetadataServiceMock, *redis.RedisStreamServiceMock, *archiver.ArchiverMock) {
+	metadataService := redis.NewStreamMetadataServiceMock()
+	streamService := redis.NewRedisStreamServiceMock()
+	archiverMock := archiver.NewArchiverMock()
+	cleaner := NewStreamCleaner(&StreamCleanerOptions{
+		StreamMetadataservice: metadataService,
+		Streamservice:         streamService,
+		Archiver:              archiverMock,
+		MessageBatchSize:      2,
+	}, testutils.NewMockLogger())
+
+	return cleaner, metadataService, streamService, archiverMock
+}
+
+func TestStreamCleaner_ArchiveMessages(t *testing.T) {
+	t.Run("Archive batches after the watermark and advance it after each block", func(t *testing.T) {
+		cleaner, metadataService, streamService, archiverMock := setupStreamCleaner()
+		firstBatch := []rdb.XMessage{{ID: "3-0"}, {ID: "4-0"}}
+		secondBatch := []rdb.XMessage{{ID: "5-0"}}
+
+		metadataService.On("GetArchiveWatermark", "test-stream").Return("2-0", nil)
+		streamService.On("GetMessagesBetween", "test-stream", "2-0", "10-0", int64(2)).Return(firstBatch, nil)
+		streamService.On("GetMessagesBetween", "test-stream", "4-0", "10-0", int64(2)).Return(secondBatch, nil)
+		archiverMock.On("Archive", mock.Anything, "test-stream", firstBatch).Return(nil)
+		archiverMock.On("Archive", mock.Anything, "test-stream", secondBatch).Return(nil)
+		metadataService.On("SetArchiveWatermark", "test-stream", "2-0", "4-0", int64(0)).Return(nil)
+		metadataService.On("SetArchiveWatermark", "test-stream", "4-0", "5-0", int64(0)).Return(nil)
+
+		err := cleaner.ArchiveMessages(context.Background(), "test-stream", "10-0", 0)
+
+		assert.NoError(t, err)
+		archiverMock.AssertExpectations(t)
+		metadataService.AssertExpectations(t)
+	})
+
+	t.Run("Keep the watermark when archiving fails", func(t *testing.T) {
+		cleaner, metadataService, streamService, archiverMock := setupStreamCleaner()
+		batch := []rdb.XMessage{{ID: "3-0"}}
+
+		metadataService.On("GetArchiveWatermark", "test-stream").Return("", nil)
+		streamService.On("GetMessagesBetween", "test-stream", "", "10-0", int64(2)).Return(batch, nil)
+		archiverMock.On("Archive", mock.Anything, "test-stream", batch).Return(errors.New("upload failed"))
+
+		err := cleaner.ArchiveMessages(context.Background(), "test-stream", "10-0", 0)
+
+		assert.Error(t, err)
+		metadataService.AssertNotCalled(t, "SetArchiveWatermark", "test-stream", "", "3-0", int64(0))
+	})
+
+	t.Run("Stop when another archiver moved the watermark", func(t *testing.T) {
+		cleaner, metadataService, streamService, archiverMock := setupStreamCleaner()
+		batch := []rdb.XMessage{{ID: "3-0"}, {ID: "4-0"}}
+
+		metadataService.On("GetArchiveWatermark", "test-stream").Return("2-0", nil)
+		streamService.On("GetMessagesBetween", "test-stream", "2-0", "10-0", int64(2)).Return(batch, nil)
+		archiverMock.On("Archive", mock.Anything, "test-stream", batch).Return(nil)
+		metadataService.On("SetArchiveWatermark", "test-stream", "2-0", "4-0", int64(0)).Return(redis.ArchiveWatermarkConflictError("test-stream", "2-0"))
+
+		err := cleaner.ArchiveMessages(context.Background(), "test-stream", "10-0", 0)
+
+		assert.ErrorAs(t, err, new(*redis.RedisArchiveWatermarkConflictError))
+		streamService.AssertNotCalled(t, "GetMessagesBetween", "test-stream", "4-0", "10-0", int64(2))
+	})
+
+	t.Run("Stop archiving when the retention lease changes hands mid-run", func(t *testing.T) {
+		cleaner, metadataService, streamService, archiverMock := setupStreamCleaner()
+		firstBatch := []rdb.XMessage{{ID: "3-0"}, {ID: "4-0"}}
+		secondBatch := []rdb.XMessage{{ID: "5-0"}}
+
+		metadataService.On("GetArchiveWatermark", "test-stream").Return("2-0", nil)
+		streamService.On("GetMessagesBetween", "test-stream", "2-0", "10-0", int64(2)).Return(firstBatch, nil)
+		streamService.On("GetMessagesBetween", "test-stream", "4-0", "10-0", int64(2)).Return(secondBatch, nil)
+		// The leader with fencing token 5 touches the stream after the first block
+		metadataService.On("CheckFencingToken", "test-stream", int64(4)).Return(nil).Once()
+		metadataService.On("CheckFencingToken", "test-stream", int64(4)).Return(redis.FencedError("test-stream", 4)).Once()
+		archiverMock.On("Archive", mock.Anything, "test-stream", firstBatch).Return(nil)
+		metadataService.On("SetArchiveWatermark", "test-stream", "2-0", "4-0", int64(4)).Return(nil)
+
+		err := cleaner.ArchiveMessages(context.Background(), "test-stream", "10-0", 4)
+
+		assert.ErrorAs(t, err, new(*redis.RedisFencedError))
+		archiverMock.AssertNotCalled(t, "Archive", mock.Anything, "test-stream", secondBatch)
+		metadataService.AssertExpectations(t)
+	})
+}
+
+func TestStreamCleaner_DeleteAndArchiveMessages(t *testing.T) {
+	cleaner, metadataService, streamService, archiverMock := setupStreamCleaner()
+	batch := []rdb.XMessage{{ID: "3-0"}}
+
+	metadataService.On("GetArchiveWatermark", "test-stream").Return("", nil)
+	streamService.On("GetMessagesBetween", "test-stream", "", "10-0", int64(2)).Return(batch, nil)
+	archiverMock.On("Archive", mock.Anything, "test-stream", batch).Return(errors.New("upload failed"))
+
+	err := cleaner.DeleteAndArchiveMessages(context.Background(), "test-stream", "10-0", 0)
+
+	// Messages that could not be archived must stay in the stream
+	assert.Error(t, err)
+	streamService.AssertNotCalled(t, "DeleteMessagesOlderThan", "test-stream", "10-0", int64(0))
+}
+
+func TestStreamCleaner_ApplyCleanupPolicy(t *testing.T) {
+	t.Run("Defer archiving outside the archive windows", func(t *testing.T) {
+		cleaner, metadataService, streamService, archiverMock := setupStreamCleaner()
+		now := time.Now().UTC()
+		// A one minute window an hour from now
+		start := now.Add(time.Hour)
+		window := fmt.Sprintf("%s-%s", start.Format("15:04"), start.Add(time.Minute).Format("15:04"))
+
+		err := cleaner.ApplyCleanupPolicy(context.Background(), &redis.StreamMetadata{
+			Name:           "test-stream",
+			CleanupPolicy:  "delete,archive",
+			ArchiveWindows: []string{window},
+		}, "10-0", 0)
+
+		assert.NoError(t, err)
+		metadataService.AssertNotCalled(t, "GetArchiveWatermark", "test-stream")
+		streamService.AssertNotCalled(t, "DeleteMessagesOlderThan", "test-stream", "10-0", int64(0))
+		archiverMock.AssertExpectations(t)
+	})
+
+	t.Run("Delete outside the archive windows", func(t *testing.T) {
+		cleaner, _, streamService, _ := setupStreamCleaner()
+		streamService.On("DeleteMessagesOlderThan", "test-stream", "10-0", int64(0)).Return(nil)
+
+		err := cleaner.ApplyCleanupPolicy(context.Background(), &redis.StreamMetadata{
+			Name:           "test-stream",
+			CleanupPolicy:  "delete",
+			ArchiveWindows: []string{"00:00-00:01"},
+		}, "10-0", 0)
+
+		assert.NoError(t, err)
+		streamService.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,178 @@
+package retention
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	rdb "github.com/redis/go-redis/v9"
+	"github.com/streamweaverio/broker/internal/redis"
+	"github.com/streamweaverio/broker/pkg/utils"
+	"go.uber.org/zap"
+)
+
+// Cutoffs in unix milliseconds a compaction pass removes messages by
+type CompactionCutoffs struct {
+	// Messages newer than the lag cutoff are never removed
+	Lag int64
+	// Tombstones older than the tombstone cutoff remove their key entirely
+	Tombstone int64
+}
+
+// Keeps only the newest message of every key in the stream, removing superseded messages older than the compaction lag
+func (s *StreamCleaner) CompactMessages(ctx context.Context, stream string, token int64) error {
+	_, err := s.compact(ctx, stream, false, token)
+	return err
+}
+
+// Counts the messages compacting the stream would remove without removing them
+func (s *StreamCleaner) CountCompactedMessages(ctx context.Context, stream string) (int64, error) {
+	return s.compact(ctx, stream, true, 0)
+}
+
+func (s *StreamCleaner) compact(ctx context.Context, stream string, dryRun bool, token int64) (int64, error) {
+	meta, err := s.Metadataservice.GetStreamMetadata(utils.HashString(stream))
+	if err != nil {
+		return 0, err
+	}
+
+	if meta.CompactionKeyField == "" {
+		return 0, fmt.Errorf("stream %s has no compaction key field", stream)
+	}
+
+	now := time.Now().UnixMilli()
+	cutoffs := &CompactionCutoffs{
+		Lag:       now - meta.CompactionLag,
+		Tombstone: now - meta.CompactionLag - meta.TombstoneRetention,
+	}
+
+	// Messages added after the first pass are not known to it, so their keys are only compacted on the next run
+	latest, err := s.FindLatestMessageIDs(ctx, stream, meta.CompactionKeyField)
+	if err != nil {
+		return 0, err
+	}
+
+	var deleted int64
+	afterId := ""
+	for {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+
+		messages, err := s.Streamservice.ScanMessages(stream, afterId, s.MessageBatchSize)
+		if err != nil {
+			return 0, fmt.Errorf("failed to scan messages of stream %s: %w", stream, err)
+		}
+
+		if len(messages) == 0 {
+			break
+		}
+
+		ids := SelectCompactedMessages(messages, meta.CompactionKeyField, latest, cutoffs)
+		if dryRun {
+			deleted += int64(len(ids))
+		} else if len(ids) > 0 {
+			count, err := s.Streamservice.DeleteMessages(stream, ids, token)
+			if err != nil {
+				return 0, fmt.Errorf("failed to compact stream %s: %w", stream, err)
+			}
+			deleted += count
+		}
+
+		afterId = messages[len(messages)-1].ID
+		if timestamp, _ := utils.SplitStreamMessageID(afterId); timestamp >= cutoffs.Lag {
+			break
+		}
+
+		if int64(len(messages)) < s.MessageBatchSize {
+			break
+		}
+	}
+
+	if !dryRun {
+		s.Logger.Debug("Compacted stream", zap.String("stream", stream), zap.Int("keys", len(latest)), zap.Int64("deleted", deleted))
+	}
+	return deleted, nil
+}
+
+// Maps every key of the stream to the ID of its newest message
+func (s *StreamCleaner) FindLatestMessageIDs(ctx context.Context, stream string, keyField string) (map[string]string, error) {
+	latest := make(map[string]string)
+
+	afterId := ""
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		messages, err := s.Streamservice.ScanMessages(stream, afterId, s.MessageBatchSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan messages of stream %s: %w", stream, err)
+		}
+
+		for _, message := range messages {
+			if key, ok := GetCompactionKey(message, keyField); ok {
+				latest[key] = message.ID
+			}
+		}
+
+		if int64(len(messages)) < s.MessageBatchSize {
+			break
+		}
+
+		afterId = messages[len(messages)-1].ID
+	}
+
+	return latest, nil
+}
+
+// Selects the IDs of messages superseded by a newer message of the same key, and of expired tombstones.
+// Messages newer than the lag cutoff and messages without a key are always kept
+func SelectCompactedMessages(messages []rdb.XMessage, keyField string, latest map[string]string, cutoffs *CompactionCutoffs) []string {
+	var ids []string
+	for _, message := range messages {
+		timestamp, _ := utils.SplitStreamMessageID(message.ID)
+		if timestamp >= cutoffs.Lag {
+			continue
+		}
+
+		key, ok := GetCompactionKey(message, keyField)
+		if !ok {
+			continue
+		}
+
+		latestId, ok := latest[key]
+		if !ok {
+			continue
+		}
+
+		if latestId != message.ID {
+			ids = append(ids, message.ID)
+			continue
+		}
+
+		if IsTombstone(message, keyField) && timestamp < cutoffs.Tombstone {
+			ids = append(ids, message.ID)
+		}
+	}
+
+	return ids
+}
+
+// Gets the compaction key of a message, raw messages hold it in the header of the same name. False when the message has no key
+func GetCompactionKey(message rdb.XMessage, keyField string) (string, bool) {
+	value, ok := message.Values[keyField]
+	if !ok {
+		value, ok = message.Values[redis.MESSAGE_HEADER_PREFIX+keyField]
+	}
+	if !ok {
+		return "", false
+	}
+	return fmt.Sprint(value), true
+}
+
+// A tombstone is a message holding nothing but its key
+func IsTombstone(message rdb.XMessage, keyField string) bool {
+	_, ok := GetCompactionKey(message, keyField)
+	return ok && len(message.Values) == 1
+}
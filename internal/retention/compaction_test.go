@@ -0,0 +1,95 @@
+package retention
+
+import (
+	"context"
+	"testing"
+
+	rdb "github.com/redis/go-redis/v9"
+	"github.com/streamweaverio/broker/internal/redis"
+	"github.com/streamweaverio/broker/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectCompactedMessages(t *testing.T) {
+	messages := []rdb.XMessage{
+		{ID: "100-0", Values: map[string]interface{}{"key": "a", "value": "1"}},
+		{ID: "200-0", Values: map[string]interface{}{"key": "b", "value": "1"}},
+		{ID: "300-0", Values: map[string]interface{}{"value": "unkeyed"}},
+		{ID: "400-0", Values: map[string]interface{}{"key": "a", "value": "2"}},
+		{ID: "500-0", Values: map[string]interface{}{"key": "b"}},
+		{ID: "600-0", Values: map[string]interface{}{"key": "c", "value": "1"}},
+		{ID: "700-0", Values: map[string]interface{}{"key": "c", "value": "2"}},
+	}
+	latest := map[string]string{"a": "400-0", "b": "500-0", "c": "700-0"}
+
+	tests := []struct {
+		Name     string
+		Cutoffs  *CompactionCutoffs
+		Expected []string
+	}{
+		{
+			Name:     "Remove superseded messages older than the lag",
+			Cutoffs:  &CompactionCutoffs{Lag: 650, Tombstone: 0},
+			Expected: []string{"100-0", "200-0", "600-0"},
+		},
+		{
+			Name:     "Keep superseded messages newer than the lag",
+			Cutoffs:  &CompactionCutoffs{Lag: 150, Tombstone: 0},
+			Expected: []string{"100-0"},
+		},
+		{
+			Name:     "Remove tombstones older than the tombstone retention",
+			Cutoffs:  &CompactionCutoffs{Lag: 1000, Tombstone: 550},
+			Expected: []string{"100-0", "200-0", "500-0", "600-0"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			ids := SelectCompactedMessages(messages, "key", latest, test.Cutoffs)
+			assert.Equal(t, test.Expected, ids)
+		})
+	}
+}
+
+func TestSelectCompactedMessages_RawMessages(t *testing.T) {
+	// Raw messages hold their key in a header, tombstones have no payload
+	messages := []rdb.XMessage{
+		{ID: "100-0", Values: map[string]interface{}{"header:key": "a", "payload": "1"}},
+		{ID: "200-0", Values: map[string]interface{}{"header:key": "a", "payload": "2"}},
+		{ID: "300-0", Values: map[string]interface{}{"header:key": "b"}},
+	}
+	latest := map[string]string{"a": "200-0", "b": "300-0"}
+
+	ids := SelectCompactedMessages(messages, "key", latest, &CompactionCutoffs{Lag: 1000, Tombstone: 1000})
+
+	assert.Equal(t, []string{"100-0", "300-0"}, ids)
+}
+
+func TestStreamCleaner_CompactMessages(t *testing.T) {
+	cleaner, metadataService, streamService, _ := setupStreamCleaner()
+	firstBatch := []rdb.XMessage{
+		{ID: "1-0", Values: map[string]interface{}{"key": "a", "value": "1"}},
+		{ID: "2-0", Values: map[string]interface{}{"key": "b", "value": "1"}},
+	}
+	secondBatch := []rdb.XMessage{
+		{ID: "3-0", Values: map[string]interface{}{"key": "a", "value": "2"}},
+	}
+
+	meta := &redis.StreamMetadata{
+		Name:               "test-stream",
+		CleanupPolicy:      "compact",
+		CompactionKeyField: "key",
+		CompactionLag:      1000,
+	}
+
+	metadataService.On("GetStreamMetadata", utils.HashString("test-stream")).Return(meta, nil)
+	streamService.On("ScanMessages", "test-stream", "", int64(2)).Return(firstBatch, nil)
+	streamService.On("ScanMessages", "test-stream", "2-0", int64(2)).Return(secondBatch, nil)
+	streamService.On("DeleteMessages", "test-stream", []string{"1-0"}, int64(0)).Return(int64(1), nil)
+
+	err := cleaner.ApplyCleanupPolicy(context.Background(), meta, "", 0)
+
+	assert.NoError(t, err)
+	streamService.AssertExpectations(t)
+}
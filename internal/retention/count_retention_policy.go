@@ -0,0 +1,95 @@
+package retention
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/streamweaverio/broker/internal/archiver"
+	"github.com/streamweaverio/broker/internal/logging"
+	"github.com/streamweaverio/broker/internal/redis"
+	"go.uber.org/zap"
+)
+
+// Cleans up the oldest messages of streams holding more messages than their max messages
+type CountRetentionPolicy struct {
+	*StreamCleaner
+}
+
+type CountRetentionPolicyOpts struct {
+	StreamMetadataservice redis.StreamMetadataService
+	Streamservice         redis.RedisStreamService
+	MessageBatchSize      int64
+	Archiver              archiver.Archiver
+	WorkerPool            *WorkerPoolOptions
+}
+
+func NewCountRetentionPolicy(opts *CountRetentionPolicyOpts, logger logging.LoggerContract) *CountRetentionPolicy {
+	return &CountRetentionPolicy{
+		StreamCleaner: NewStreamCleaner(&StreamCleanerOptions{
+			StreamMetadataservice: opts.StreamMetadataservice,
+			Streamservice:         opts.Streamservice,
+			Archiver:              opts.Archiver,
+			MessageBatchSize:      opts.MessageBatchSize,
+			WorkerPool:            opts.WorkerPool,
+		}, logger),
+	}
+}
+
+func (s *CountRetentionPolicy) Enforce(ctx context.Context, streams []string, token int64) (*EnforcementResult, error) {
+	return s.ApplyToStreams(ctx, "count", streams, func(ctx context.Context, stream string) error {
+		return s.ApplyPolicy(ctx, stream, token)
+	})
+}
+
+func (s *CountRetentionPolicy) Plan(ctx context.Context) ([]*StreamPlan, error) {
+	return s.PlanStreams(ctx, "count", s.FindMinID)
+}
+
+func (s *CountRetentionPolicy) ApplyPolicy(ctx context.Context, stream string, token int64) error {
+	meta, err := s.Metadataservice.GetStreamMetadata(stream)
+	if err != nil {
+		return err
+	}
+
+	minID, err := s.FindMinID(meta)
+	if err != nil {
+		return err
+	}
+
+	if minID == "" {
+		return nil
+	}
+
+	s.Logger.Info("Applying count retention policy to stream...", zap.String("name", meta.Name), zap.String("min_id", minID))
+	return s.ApplyLimitPolicy(ctx, meta, minID, token)
+}
+
+// Messages older than the first of the newest max messages are cleaned up, empty when the stream is within its max messages
+func (s *CountRetentionPolicy) FindMinID(meta *redis.StreamMetadata) (string, error) {
+	if meta.MaxMessages <= 0 {
+		return "", nil
+	}
+
+	length, err := s.Streamservice.GetStreamLength(meta.Name)
+	if err != nil {
+		return "", err
+	}
+
+	if length <= meta.MaxMessages {
+		return "", nil
+	}
+
+	overflow := length - meta.MaxMessages
+	s.Logger.Debug("Stream exceeds its max messages",
+		zap.String("name", meta.Name),
+		zap.Int64("length", length),
+		zap.Int64("max_messages", meta.MaxMessages),
+		zap.Int64("overflow", overflow))
+
+	minID, err := s.Streamservice.GetMessageIDAt(meta.Name, overflow, s.MessageBatchSize)
+	if err != nil {
+		return "", fmt.Errorf("failed to find min ID for stream %s: %w", meta.Name, err)
+	}
+
+	return minID, nil
+}
@@ -0,0 +1,71 @@
+package retention
+
+import (
+	"context"
+	"testing"
+
+	"github.com/streamweaverio/broker/internal/redis"
+	"github.com/streamweaverio/broker/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCountRetentionPolicy_ApplyPolicy(t *testing.T) {
+	t.Run("Delete the overflow of a stream over its max messages", func(t *testing.T) {
+		metadataService := redis.NewStreamMetadataServiceMock()
+		streamService := redis.NewRedisStreamServiceMock()
+		policy := NewCountRetentionPolicy(&CountRetentionPolicyOpts{
+			StreamMetadataservice: metadataService,
+			Streamservice:         streamService,
+			MessageBatchSize:      100,
+		}, testutils.NewMockLogger())
+
+		metadataService.On("GetStreamMetadata", "hash").Return(&redis.StreamMetadata{Name: "test-stream", CleanupPolicy: "delete", MaxMessages: 10}, nil)
+		streamService.On("GetStreamLength", "test-stream").Return(int64(15), nil)
+		streamService.On("GetMessageIDAt", "test-stream", int64(5), int64(100)).Return("6-0", nil)
+		streamService.On("DeleteMessagesOlderThan", "test-stream", "6-0", int64(0)).Return(nil)
+
+		err := policy.ApplyPolicy(context.Background(), "hash", 0)
+
+		assert.NoError(t, err)
+		streamService.AssertExpectations(t)
+	})
+
+	t.Run("Trim a compacted stream over its max messages instead of compacting it again", func(t *testing.T) {
+		metadataService := redis.NewStreamMetadataServiceMock()
+		streamService := redis.NewRedisStreamServiceMock()
+		policy := NewCountRetentionPolicy(&CountRetentionPolicyOpts{
+			StreamMetadataservice: metadataService,
+			Streamservice:         streamService,
+			MessageBatchSize:      100,
+		}, testutils.NewMockLogger())
+
+		metadataService.On("GetStreamMetadata", "hash").Return(&redis.StreamMetadata{Name: "test-stream", CleanupPolicy: "compact", CompactionKeyField: "key", MaxMessages: 10}, nil)
+		streamService.On("GetStreamLength", "test-stream").Return(int64(15), nil)
+		streamService.On("GetMessageIDAt", "test-stream", int64(5), int64(100)).Return("6-0", nil)
+		streamService.On("DeleteMessagesOlderThan", "test-stream", "6-0", int64(3)).Return(nil)
+
+		err := policy.ApplyPolicy(context.Background(), "hash", 3)
+
+		assert.NoError(t, err)
+		streamService.AssertExpectations(t)
+		streamService.AssertNotCalled(t, "DeleteMessages", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Skip streams within their max messages", func(t *testing.T) {
+		metadataService := redis.NewStreamMetadataServiceMock()
+		streamService := redis.NewRedisStreamServiceMock()
+		policy := NewCountRetentionPolicy(&CountRetentionPolicyOpts{
+			StreamMetadataservice: metadataService,
+			Streamservice:         streamService,
+		}, testutils.NewMockLogger())
+
+		metadataService.On("GetStreamMetadata", "hash").Return(&redis.StreamMetadata{Name: "test-stream", CleanupPolicy: "delete", MaxMessages: 10}, nil)
+		streamService.On("GetStreamLength", "test-stream").Return(int64(10), nil)
+
+		err := policy.ApplyPolicy(context.Background(), "hash", 0)
+
+		assert.NoError(t, err)
+		streamService.AssertNotCalled(t, "GetMessageIDAt", "test-stream", int64(0), int64(1000))
+	})
+}
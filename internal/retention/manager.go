@@ -1,8 +1,12 @@
 package retention
 
 import (
+	"context"
+	"fmt"
+	"sync"
 	"time"
 
+	"github.com/streamweaverio/broker/internal/leader"
 	"github.com/streamweaverio/broker/internal/logging"
 	"go.uber.org/zap"
 )
@@ -14,34 +18,77 @@ type RetentionPolicy struct {
 	Rule RetentionPolicyRule
 }
 
+// Outcome of the last run of a retention policy
+type PolicyResult struct {
+	Policy string
+	// Number of streams the policy was applied to
+	Streams int
+	// Number of streams the policy failed to apply to
+	Failed int
+	// Number of streams skipped because the previous run of the policy on them is still in progress
+	Skipped   int
+	StartedAt time.Time
+	Duration  time.Duration
+	Err       error
+}
+
 type RetentionManager interface {
 	RegisterPolicy(opts *RetentionPolicy) RetentionManager
-	Start() error
+	// Runs the retention policies on every interval until the context is cancelled or the manager is stopped
+	Start(ctx context.Context) error
+	// Stops the manager, waiting for running policies to finish until the shutdown timeout before cancelling them
 	Stop()
+	// Results of the last run of every policy
+	Results() []*PolicyResult
+	// Reports what every policy would do to every registered stream without modifying anything
+	Plan(ctx context.Context) ([]*StreamPlan, error)
 }
 
 type RetentionManagerOptions struct {
 	// Time interval to run retention policies in seconds
 	Interval int
+	// Time in seconds to wait for running policies to finish when stopping before they are cancelled
+	ShutdownTimeout int
+	// Elects the instance that runs retention policies, every instance runs them when nil
+	Elector leader.Elector
+	// Selects the streams due on every run, policies run on every registered stream when nil
+	Scheduler *StreamScheduler
 }
 
 type RetentionManagerConfig struct {
 	// Time interval to run retention policies in seconds
 	Interval int
+	// Time in seconds to wait for running policies to finish when stopping before they are cancelled
+	ShutdownTimeout int
 }
 
 type RetentionManagerImpl struct {
-	Policies []*RetentionPolicy
-	Config   *RetentionManagerConfig
-	Logger   logging.LoggerContract
+	Policies  []*RetentionPolicy
+	Config    *RetentionManagerConfig
+	Elector   leader.Elector
+	Scheduler *StreamScheduler
+	Logger    logging.LoggerContract
+	mu        sync.Mutex
+	cancel    context.CancelFunc
+	results   map[string]*PolicyResult
+	stop      chan struct{}
+	// Stop can be reached from both the shutdown path and signal handling
+	stopOnce sync.Once
+	stopped  chan struct{}
 }
 
 func NewRetentionManager(opts *RetentionManagerOptions, logger logging.LoggerContract) (RetentionManager, error) {
 	return &RetentionManagerImpl{
 		Config: &RetentionManagerConfig{
-			Interval: opts.Interval,
+			Interval:        opts.Interval,
+			ShutdownTimeout: opts.ShutdownTimeout,
 		},
-		Logger: logger,
+		Elector:   opts.Elector,
+		Scheduler: opts.Scheduler,
+		Logger:    logger,
+		results:   make(map[string]*PolicyResult),
+		stop:      make(chan struct{}),
+		stopped:   make(chan struct{}),
 	}, nil
 }
 
@@ -52,26 +99,160 @@ func (r *RetentionManagerImpl) RegisterPolicy(opts *RetentionPolicy) RetentionMa
 	return r
 }
 
-func (r *RetentionManagerImpl) Start() error {
-	r.Logger.Info("Starting retention manager...")
+func (r *RetentionManagerImpl) Start(ctx context.Context) error {
+	r.Logger.Info("Starting retention manager...", zap.Int("interval", r.Config.Interval))
 	if len(r.Policies) == 0 {
 		r.Logger.Warn("No retention policies registered")
 	}
 
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	r.mu.Lock()
+	r.cancel = cancel
+	r.mu.Unlock()
+	defer close(r.stopped)
+
 	ticker := time.NewTicker(time.Duration(r.Config.Interval) * time.Second)
 	defer ticker.Stop()
 
-	for range ticker.C {
-		r.Logger.Info("Running retention policies...")
-		for _, policy := range r.Policies {
-			if err := policy.Rule.Enforce(); err != nil {
-				r.Logger.Error("Failed to enforce policy", zap.String("policy", policy.Name), zap.Error(err))
-			}
+	for {
+		select {
+		case <-r.stop:
+			return nil
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			r.Run(ctx)
 		}
 	}
-	return nil
 }
 
 func (r *RetentionManagerImpl) Stop() {
 	r.Logger.Info("Stopping retention manager...")
+	r.stopOnce.Do(func() {
+		close(r.stop)
+	})
+
+	r.mu.Lock()
+	cancel := r.cancel
+	r.mu.Unlock()
+
+	// The manager was never started, so nothing is running
+	if cancel == nil {
+		return
+	}
+
+	select {
+	case <-r.stopped:
+	case <-time.After(time.Duration(r.Config.ShutdownTimeout) * time.Second):
+		r.Logger.Warn("Retention policies did not finish before the shutdown timeout, cancelling them", zap.Int("shutdown_timeout", r.Config.ShutdownTimeout))
+		cancel()
+		<-r.stopped
+	}
+
+	r.Logger.Info("Retention manager stopped")
+}
+
+// Runs every registered policy once
+func (r *RetentionManagerImpl) Run(ctx context.Context) {
+	var token int64
+	if r.Elector != nil {
+		token = r.Elector.Token()
+		if token == 0 {
+			r.Logger.Debug("Skipping retention policies, this instance is not the retention leader")
+			return
+		}
+	}
+
+	var streams []string
+	if r.Scheduler != nil {
+		due, err := r.Scheduler.DueStreams(time.Now())
+		if err != nil {
+			r.Logger.Error("Failed to schedule streams", zap.Error(err))
+			return
+		}
+
+		if len(due) == 0 {
+			r.Logger.Debug("Skipping retention policies, no streams are due")
+			return
+		}
+		streams = due
+	}
+
+	r.Logger.Info("Running retention policies...", zap.Int64("fencing_token", token))
+	for _, policy := range r.Policies {
+		if ctx.Err() != nil {
+			return
+		}
+
+		// Leadership may be lost while policies run, another instance takes over from there
+		if r.Elector != nil && !r.Elector.ValidateToken(token) {
+			r.Logger.Warn("Lost retention leadership, skipping remaining policies", zap.Int64("fencing_token", token))
+			return
+		}
+
+		result := r.Enforce(ctx, policy, streams, token)
+		if result.Err != nil {
+			r.Logger.Error("Failed to enforce policy", zap.String("policy", policy.Name), zap.Error(result.Err))
+			continue
+		}
+
+		r.Logger.Info("Enforced retention policy",
+			zap.String("policy", policy.Name),
+			zap.Int("streams", result.Streams),
+			zap.Int("failed", result.Failed),
+			zap.Int("skipped", result.Skipped),
+			zap.Duration("duration", result.Duration))
+	}
+}
+
+// Enforces a single policy on the streams, or on every registered stream when nil, and records its result
+func (r *RetentionManagerImpl) Enforce(ctx context.Context, policy *RetentionPolicy, streams []string, token int64) *PolicyResult {
+	result := &PolicyResult{
+		Policy:    policy.Name,
+		StartedAt: time.Now(),
+	}
+
+	enforcement, err := policy.Rule.Enforce(ctx, streams, token)
+	result.Duration = time.Since(result.StartedAt)
+	result.Err = err
+	if enforcement != nil {
+		result.Streams = enforcement.Streams
+		result.Failed = enforcement.Failed
+		result.Skipped = enforcement.Skipped
+	}
+
+	r.mu.Lock()
+	r.results[policy.Name] = result
+	r.mu.Unlock()
+
+	return result
+}
+
+func (r *RetentionManagerImpl) Results() []*PolicyResult {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	results := make([]*PolicyResult, 0, len(r.Policies))
+	for _, policy := range r.Policies {
+		if result, ok := r.results[policy.Name]; ok {
+			results = append(results, result)
+		}
+	}
+
+	return results
+}
+
+func (r *RetentionManagerImpl) Plan(ctx context.Context) ([]*StreamPlan, error) {
+	var plans []*StreamPlan
+	for _, policy := range r.Policies {
+		policyPlans, err := policy.Rule.Plan(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to plan retention policy %s: %w", policy.Name, err)
+		}
+		plans = append(plans, policyPlans...)
+	}
+
+	return plans, nil
 }
@@ -0,0 +1,145 @@
+package retention
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/streamweaverio/broker/internal/leader"
+	"github.com/streamweaverio/broker/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRetentionManager_Run(t *testing.T) {
+	t.Run("Record the result of every policy", func(t *testing.T) {
+		manager, _ := NewRetentionManager(&RetentionManagerOptions{Interval: 30}, testutils.NewMockLogger())
+		timeRule := NewRetentionPolicyRuleMock()
+		sizeRule := NewRetentionPolicyRuleMock()
+		manager.RegisterPolicy(&RetentionPolicy{Name: "time", Rule: timeRule})
+		manager.RegisterPolicy(&RetentionPolicy{Name: "size", Rule: sizeRule})
+
+		timeRule.On("Enforce", mock.Anything, mock.Anything, mock.Anything).Return(&EnforcementResult{Streams: 3, Failed: 1}, nil)
+		sizeRule.On("Enforce", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("failed to list streams"))
+
+		manager.(*RetentionManagerImpl).Run(context.Background())
+
+		results := manager.Results()
+		assert.Len(t, results, 2)
+		assert.Equal(t, "time", results[0].Policy)
+		assert.Equal(t, 3, results[0].Streams)
+		assert.Equal(t, 1, results[0].Failed)
+		assert.EqualError(t, results[1].Err, "failed to list streams")
+	})
+
+	t.Run("Skip policies when this instance is not the leader", func(t *testing.T) {
+		elector := leader.NewElectorMock()
+		manager, _ := NewRetentionManager(&RetentionManagerOptions{Interval: 30, Elector: elector}, testutils.NewMockLogger())
+		rule := NewRetentionPolicyRuleMock()
+		manager.RegisterPolicy(&RetentionPolicy{Name: "time", Rule: rule})
+
+		elector.On("Token").Return(int64(0))
+
+		manager.(*RetentionManagerImpl).Run(context.Background())
+
+		rule.AssertNotCalled(t, "Enforce", mock.Anything, mock.Anything, mock.Anything)
+		assert.Empty(t, manager.Results())
+	})
+
+	t.Run("Fence policies with the token and stop when the lease changes hands", func(t *testing.T) {
+		elector := leader.NewElectorMock()
+		manager, _ := NewRetentionManager(&RetentionManagerOptions{Interval: 30, Elector: elector}, testutils.NewMockLogger())
+		timeRule := NewRetentionPolicyRuleMock()
+		sizeRule := NewRetentionPolicyRuleMock()
+		manager.RegisterPolicy(&RetentionPolicy{Name: "time", Rule: timeRule})
+		manager.RegisterPolicy(&RetentionPolicy{Name: "size", Rule: sizeRule})
+
+		elector.On("Token").Return(int64(4))
+		// Another instance takes over the lease while the first policy runs
+		elector.On("ValidateToken", int64(4)).Return(true).Once()
+		elector.On("ValidateToken", int64(4)).Return(false).Once()
+		timeRule.On("Enforce", mock.Anything, mock.Anything, int64(4)).Return(&EnforcementResult{Streams: 1}, nil)
+
+		manager.(*RetentionManagerImpl).Run(context.Background())
+
+		timeRule.AssertExpectations(t)
+		sizeRule.AssertNotCalled(t, "Enforce", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestRetentionManager_Stop(t *testing.T) {
+	manager, _ := NewRetentionManager(&RetentionManagerOptions{Interval: 1, ShutdownTimeout: 0}, testutils.NewMockLogger())
+	rule := NewRetentionPolicyRuleMock()
+	manager.RegisterPolicy(&RetentionPolicy{Name: "time", Rule: rule})
+
+	running := make(chan struct{})
+	// The policy only returns once its context is cancelled, like an archive upload in progress
+	rule.On("Enforce", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		close(running)
+		<-args.Get(0).(context.Context).Done()
+	}).Return(&EnforcementResult{}, context.Canceled).Once()
+
+	done := make(chan error)
+	go func() {
+		done <- manager.Start(context.Background())
+	}()
+
+	<-running
+	manager.Stop()
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("retention manager did not stop")
+	}
+
+	results := manager.Results()
+	assert.Len(t, results, 1)
+	assert.ErrorIs(t, results[0].Err, context.Canceled)
+}
+
+func TestRetentionManager_StopTwice(t *testing.T) {
+	t.Run("Do not panic when stopped before being started", func(t *testing.T) {
+		manager, _ := NewRetentionManager(&RetentionManagerOptions{Interval: 1}, testutils.NewMockLogger())
+
+		assert.NotPanics(t, func() {
+			manager.Stop()
+			manager.Stop()
+		})
+	})
+
+	t.Run("Do not panic when stopped again after shutting down", func(t *testing.T) {
+		manager, _ := NewRetentionManager(&RetentionManagerOptions{Interval: 1}, testutils.NewMockLogger())
+
+		done := make(chan error)
+		go func() {
+			done <- manager.Start(context.Background())
+		}()
+
+		assert.NotPanics(t, manager.Stop)
+		assert.NoError(t, <-done)
+		assert.NotPanics(t, manager.Stop)
+	})
+}
+
+func TestRetentionManager_Plan(t *testing.T) {
+	manager, _ := NewRetentionManager(&RetentionManagerOptions{Interval: 30}, testutils.NewMockLogger())
+	timeRule := NewRetentionPolicyRuleMock()
+	countRule := NewRetentionPolicyRuleMock()
+	manager.RegisterPolicy(&RetentionPolicy{Name: "time", Rule: timeRule})
+	manager.RegisterPolicy(&RetentionPolicy{Name: "count", Rule: countRule})
+
+	timeRule.On("Plan", mock.Anything).Return([]*StreamPlan{{Policy: "time", Stream: "orders", MinID: "100-0", Deleted: 5}}, nil)
+	countRule.On("Plan", mock.Anything).Return([]*StreamPlan{{Policy: "count", Stream: "orders"}}, nil)
+
+	plans, err := manager.Plan(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, plans, 2)
+	assert.Equal(t, "time", plans[0].Policy)
+	assert.Equal(t, int64(5), plans[0].Deleted)
+	assert.Equal(t, "count", plans[1].Policy)
+	timeRule.AssertNotCalled(t, "Enforce", mock.Anything, mock.Anything, mock.Anything)
+}
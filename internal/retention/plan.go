@@ -0,0 +1,108 @@
+package retention
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/streamweaverio/broker/internal/redis"
+)
+
+// Computes the plan of a policy for every registered stream one at a time, a failing stream is reported in its plan
+func (s *StreamCleaner) PlanStreams(ctx context.Context, policy string, findMinID func(meta *redis.StreamMetadata) (string, error)) ([]*StreamPlan, error) {
+	streams, err := s.Metadataservice.ListStreams()
+	if err != nil {
+		return nil, err
+	}
+
+	plans := make([]*StreamPlan, 0, len(streams))
+	for _, stream := range streams {
+		if err := ctx.Err(); err != nil {
+			return plans, err
+		}
+
+		meta, err := s.Metadataservice.GetStreamMetadata(stream)
+		if err != nil {
+			plans = append(plans, &StreamPlan{Policy: policy, Stream: stream, Err: err})
+			continue
+		}
+
+		plan := &StreamPlan{
+			Policy:        policy,
+			Stream:        meta.Name,
+			CleanupPolicy: meta.CleanupPolicy,
+		}
+		plans = append(plans, plan)
+
+		plan.MinID, plan.Err = findMinID(meta)
+		if plan.Err != nil || plan.MinID == "" {
+			continue
+		}
+
+		plan.Err = s.PlanCleanupPolicy(ctx, plan)
+	}
+
+	return plans, nil
+}
+
+// Fills in how many messages the cleanup policy of the plan's stream would remove, nothing is modified
+func (s *StreamCleaner) PlanCleanupPolicy(ctx context.Context, plan *StreamPlan) error {
+	var err error
+	switch plan.CleanupPolicy {
+	case "delete":
+		plan.Deleted, err = s.Streamservice.CountMessagesOlderThan(plan.Stream, plan.MinID, s.MessageBatchSize)
+	case "archive":
+		plan.Archived, plan.Blocks, err = s.CountArchivedMessages(ctx, plan.Stream, plan.MinID)
+	case "delete,archive":
+		plan.Archived, plan.Blocks, err = s.CountArchivedMessages(ctx, plan.Stream, plan.MinID)
+		if err != nil {
+			return err
+		}
+		plan.Deleted, err = s.Streamservice.CountMessagesOlderThan(plan.Stream, plan.MinID, s.MessageBatchSize)
+	case "compact":
+		// Like when the policies are enforced, only the time policy compacts and limits trim the oldest messages
+		if plan.Policy == "time" {
+			plan.Deleted, err = s.CountCompactedMessages(ctx, plan.Stream)
+		} else {
+			plan.Deleted, err = s.Streamservice.CountMessagesOlderThan(plan.Stream, plan.MinID, s.MessageBatchSize)
+		}
+	default:
+		err = fmt.Errorf("unknown cleanup policy: %s", plan.CleanupPolicy)
+	}
+
+	return err
+}
+
+// Counts the messages archiving up to the minID would write and the blocks they would be written to
+func (s *StreamCleaner) CountArchivedMessages(ctx context.Context, stream string, minID string) (int64, int64, error) {
+	watermark, err := s.Metadataservice.GetArchiveWatermark(stream)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get archive watermark of stream %s: %w", stream, err)
+	}
+
+	var messages, blocks int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return 0, 0, err
+		}
+
+		batch, err := s.Streamservice.GetMessagesBetween(stream, watermark, minID, s.MessageBatchSize)
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to get messages from stream %s: %w", stream, err)
+		}
+
+		if len(batch) == 0 {
+			break
+		}
+
+		// Every batch is archived to its own block
+		messages += int64(len(batch))
+		blocks++
+		watermark = batch[len(batch)-1].ID
+
+		if int64(len(batch)) < s.MessageBatchSize {
+			break
+		}
+	}
+
+	return messages, blocks, nil
+}
@@ -0,0 +1,37 @@
+package retention
+
+import (
+	"context"
+	"testing"
+
+	rdb "github.com/redis/go-redis/v9"
+	"github.com/streamweaverio/broker/internal/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamCleaner_PlanStreams(t *testing.T) {
+	cleaner, metadataService, streamService, _ := setupStreamCleaner()
+
+	metadataService.On("ListStreams").Return([]string{"orders-hash", "events-hash"}, nil)
+	metadataService.On("GetStreamMetadata", "orders-hash").Return(&redis.StreamMetadata{Name: "orders", CleanupPolicy: "delete,archive"}, nil)
+	metadataService.On("GetStreamMetadata", "events-hash").Return(&redis.StreamMetadata{Name: "events", CleanupPolicy: "delete"}, nil)
+	metadataService.On("GetArchiveWatermark", "orders").Return("1-0", nil)
+	streamService.On("GetMessagesBetween", "orders", "1-0", "10-0", int64(2)).Return([]rdb.XMessage{{ID: "2-0"}, {ID: "3-0"}}, nil)
+	streamService.On("GetMessagesBetween", "orders", "3-0", "10-0", int64(2)).Return([]rdb.XMessage{{ID: "4-0"}}, nil)
+	streamService.On("CountMessagesOlderThan", "orders", "10-0", int64(2)).Return(int64(4), nil)
+
+	findMinID := func(meta *redis.StreamMetadata) (string, error) {
+		if meta.Name == "orders" {
+			return "10-0", nil
+		}
+		return "", nil
+	}
+
+	plans, err := cleaner.PlanStreams(context.Background(), "time", findMinID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []*StreamPlan{
+		{Policy: "time", Stream: "orders", CleanupPolicy: "delete,archive", MinID: "10-0", Deleted: 4, Archived: 3, Blocks: 2},
+		{Policy: "time", Stream: "events", CleanupPolicy: "delete"},
+	}, plans)
+}
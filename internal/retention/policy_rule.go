@@ -1,5 +1,37 @@
 package retention
 
+import "context"
+
 type RetentionPolicyRule interface {
-	Enforce() error
+	// Applies the policy to the given streams, or to every registered stream when nil. Stops early when the context is cancelled.
+	// Writes are fenced with the fencing token of the retention lease, zero when there is no leader election
+	Enforce(ctx context.Context, streams []string, token int64) (*EnforcementResult, error)
+	// Reports what the policy would do to every registered stream without modifying anything
+	Plan(ctx context.Context) ([]*StreamPlan, error)
+}
+
+type EnforcementResult struct {
+	// Number of streams the policy was applied to
+	Streams int
+	// Number of streams the policy failed to apply to
+	Failed int
+	// Number of streams skipped because the previous run of the policy on them is still in progress
+	Skipped int
+}
+
+// Effect a policy would have on a stream
+type StreamPlan struct {
+	Policy        string
+	Stream        string
+	CleanupPolicy string
+	// Messages older than the min ID would be cleaned up, empty when the policy leaves the stream alone
+	MinID string
+	// Number of messages that would be deleted
+	Deleted int64
+	// Number of messages that would be archived
+	Archived int64
+	// Number of storage blocks the archived messages would be written to
+	Blocks int64
+	// Error computing the plan of the stream
+	Err error
 }
@@ -0,0 +1,31 @@
+package retention
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type RetentionPolicyRuleMock struct {
+	mock.Mock
+}
+
+func NewRetentionPolicyRuleMock() *RetentionPolicyRuleMock {
+	return &RetentionPolicyRuleMock{}
+}
+
+func (m *RetentionPolicyRuleMock) Enforce(ctx context.Context, streams []string, token int64) (*EnforcementResult, error) {
+	args := m.Called(ctx, streams, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*EnforcementResult), args.Error(1)
+}
+
+func (m *RetentionPolicyRuleMock) Plan(ctx context.Context) ([]*StreamPlan, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*StreamPlan), args.Error(1)
+}
@@ -0,0 +1,87 @@
+package retention
+
+import (
+	"sync"
+	"time"
+
+	"github.com/streamweaverio/broker/internal/logging"
+	"github.com/streamweaverio/broker/internal/redis"
+	"github.com/streamweaverio/broker/internal/schedule"
+	"go.uber.org/zap"
+)
+
+// Decides which streams retention runs on, streams with a schedule are only due once their cron expression fires
+type StreamScheduler struct {
+	Metadataservice redis.StreamMetadataService
+	Logger          logging.LoggerContract
+	mu              sync.Mutex
+	// Next run of every stream with a schedule
+	runs map[string]*scheduledRun
+}
+
+type scheduledRun struct {
+	schedule string
+	cron     *schedule.Cron
+	next     time.Time
+}
+
+type StreamSchedulerOptions struct {
+	StreamMetadataservice redis.StreamMetadataService
+}
+
+func NewStreamScheduler(opts *StreamSchedulerOptions, logger logging.LoggerContract) *StreamScheduler {
+	return &StreamScheduler{
+		Metadataservice: opts.StreamMetadataservice,
+		Logger:          logger,
+		runs:            make(map[string]*scheduledRun),
+	}
+}
+
+// Streams due at the given time, streams without a schedule are due on every run.
+// A stream with a new schedule is first due when the schedule next fires
+func (s *StreamScheduler) DueStreams(now time.Time) ([]string, error) {
+	streams, err := s.Metadataservice.ListStreams()
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	due := make([]string, 0, len(streams))
+	runs := make(map[string]*scheduledRun, len(s.runs))
+	for _, stream := range streams {
+		meta, err := s.Metadataservice.GetStreamMetadata(stream)
+		if err != nil {
+			// The policies report the error when they fail to read the metadata as well
+			due = append(due, stream)
+			continue
+		}
+
+		if meta.Schedule == "" {
+			due = append(due, stream)
+			continue
+		}
+
+		run := s.runs[stream]
+		if run == nil || run.schedule != meta.Schedule {
+			cron, err := schedule.ParseCron(meta.Schedule)
+			if err != nil {
+				s.Logger.Warn("Invalid retention schedule, running retention on every interval", zap.String("stream", meta.Name), zap.String("schedule", meta.Schedule), zap.Error(err))
+				due = append(due, stream)
+				continue
+			}
+			run = &scheduledRun{schedule: meta.Schedule, cron: cron, next: cron.Next(now)}
+		}
+
+		if !run.next.IsZero() && !now.Before(run.next) {
+			due = append(due, stream)
+			run.next = run.cron.Next(now)
+		}
+		runs[stream] = run
+	}
+
+	// Streams that were deleted are dropped
+	s.runs = runs
+	return due, nil
+}
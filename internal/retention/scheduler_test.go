@@ -0,0 +1,37 @@
+package retention
+
+import (
+	"testing"
+	"time"
+
+	"github.com/streamweaverio/broker/internal/redis"
+	"github.com/streamweaverio/broker/internal/testutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamScheduler_DueStreams(t *testing.T) {
+	metadataService := redis.NewStreamMetadataServiceMock()
+	scheduler := NewStreamScheduler(&StreamSchedulerOptions{
+		StreamMetadataservice: metadataService,
+	}, testutils.NewMockLogger())
+
+	metadataService.On("ListStreams").Return([]string{"orders", "nightly"}, nil)
+	metadataService.On("GetStreamMetadata", "orders").Return(&redis.StreamMetadata{Name: "orders"}, nil)
+	metadataService.On("GetStreamMetadata", "nightly").Return(&redis.StreamMetadata{Name: "nightly", Schedule: "0 2 * * *"}, nil)
+
+	now := time.Date(2024, 12, 4, 1, 59, 30, 0, time.UTC)
+
+	// A new schedule waits for its next run
+	due, err := scheduler.DueStreams(now)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"orders"}, due)
+
+	due, err = scheduler.DueStreams(now.Add(30 * time.Second))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"orders", "nightly"}, due)
+
+	// Only due once per run of the schedule
+	due, err = scheduler.DueStreams(now.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"orders"}, due)
+}
@@ -0,0 +1,117 @@
+package retention
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/streamweaverio/broker/internal/archiver"
+	"github.com/streamweaverio/broker/internal/logging"
+	"github.com/streamweaverio/broker/internal/redis"
+	"go.uber.org/zap"
+)
+
+// Cleans up the oldest messages of streams using more memory than their byte budget
+type SizeRetentionPolicy struct {
+	*StreamCleaner
+	// Byte budget of streams without their own max size, zero disables the policy for those streams
+	MaxSize int64
+}
+
+type SizeRetentionPolicyOpts struct {
+	StreamMetadataservice redis.StreamMetadataService
+	Streamservice         redis.RedisStreamService
+	MaxSize               int64
+	MessageBatchSize      int64
+	Archiver              archiver.Archiver
+	WorkerPool            *WorkerPoolOptions
+}
+
+func NewSizeRetentionPolicy(opts *SizeRetentionPolicyOpts, logger logging.LoggerContract) *SizeRetentionPolicy {
+	return &SizeRetentionPolicy{
+		StreamCleaner: NewStreamCleaner(&StreamCleanerOptions{
+			StreamMetadataservice: opts.StreamMetadataservice,
+			Streamservice:         opts.Streamservice,
+			Archiver:              opts.Archiver,
+			MessageBatchSize:      opts.MessageBatchSize,
+			WorkerPool:            opts.WorkerPool,
+		}, logger),
+		MaxSize: opts.MaxSize,
+	}
+}
+
+func (s *SizeRetentionPolicy) Enforce(ctx context.Context, streams []string, token int64) (*EnforcementResult, error) {
+	return s.ApplyToStreams(ctx, "size", streams, func(ctx context.Context, stream string) error {
+		return s.ApplyPolicy(ctx, stream, token)
+	})
+}
+
+func (s *SizeRetentionPolicy) Plan(ctx context.Context) ([]*StreamPlan, error) {
+	return s.PlanStreams(ctx, "size", s.FindMinID)
+}
+
+func (s *SizeRetentionPolicy) ApplyPolicy(ctx context.Context, stream string, token int64) error {
+	meta, err := s.Metadataservice.GetStreamMetadata(stream)
+	if err != nil {
+		return err
+	}
+
+	minID, err := s.FindMinID(meta)
+	if err != nil {
+		return err
+	}
+
+	if minID == "" {
+		return nil
+	}
+
+	s.Logger.Info("Applying size retention policy to stream...", zap.String("name", meta.Name), zap.String("min_id", minID))
+	return s.ApplyLimitPolicy(ctx, meta, minID, token)
+}
+
+// Messages older than the first message fitting in the stream's byte budget are cleaned up, empty when the stream fits
+func (s *SizeRetentionPolicy) FindMinID(meta *redis.StreamMetadata) (string, error) {
+	maxSize := meta.MaxSize
+	if maxSize == 0 {
+		maxSize = s.MaxSize
+	}
+
+	if maxSize <= 0 {
+		return "", nil
+	}
+
+	size, err := s.Streamservice.GetStreamSize(meta.Name)
+	if err != nil {
+		return "", err
+	}
+
+	overflow := CalculateSizeOverflow(size, maxSize)
+	if overflow == 0 {
+		return "", nil
+	}
+
+	s.Logger.Debug("Stream exceeds its max size",
+		zap.String("name", meta.Name),
+		zap.Int64("size", size.Bytes),
+		zap.Int64("max_size", maxSize),
+		zap.Int64("overflow", overflow))
+
+	minID, err := s.Streamservice.GetMessageIDAt(meta.Name, overflow, s.MessageBatchSize)
+	if err != nil {
+		return "", fmt.Errorf("failed to find min ID for stream %s: %w", meta.Name, err)
+	}
+
+	return minID, nil
+}
+
+// Estimates how many of the oldest messages must be removed for a stream to fit in maxSize bytes, always keeps the newest message
+func CalculateSizeOverflow(size *redis.StreamSize, maxSize int64) int64 {
+	if size.Bytes <= maxSize || size.Length == 0 {
+		return 0
+	}
+
+	// Rounded up so the overflow is never underestimated because of integer division
+	averageMessageSize := (size.Bytes + size.Length - 1) / size.Length
+	overflow := (size.Bytes - maxSize + averageMessageSize - 1) / averageMessageSize
+
+	return min(overflow, size.Length-1)
+}
@@ -0,0 +1,91 @@
+package retention
+
+import (
+	"context"
+	"testing"
+
+	"github.com/streamweaverio/broker/internal/redis"
+	"github.com/streamweaverio/broker/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCalculateSizeOverflow(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Size     *redis.StreamSize
+		MaxSize  int64
+		Expected int64
+	}{
+		{Name: "Stream within budget", Size: &redis.StreamSize{Bytes: 1000, Length: 10}, MaxSize: 1000, Expected: 0},
+		{Name: "Empty stream", Size: &redis.StreamSize{Bytes: 1000, Length: 0}, MaxSize: 500, Expected: 0},
+		{Name: "Stream over budget", Size: &redis.StreamSize{Bytes: 1000, Length: 10}, MaxSize: 750, Expected: 3},
+		{Name: "Newest message is kept", Size: &redis.StreamSize{Bytes: 1000, Length: 10}, MaxSize: 1, Expected: 9},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			assert.Equal(t, testCase.Expected, CalculateSizeOverflow(testCase.Size, testCase.MaxSize))
+		})
+	}
+}
+
+func TestSizeRetentionPolicy_ApplyPolicy(t *testing.T) {
+	t.Run("Delete the oldest messages of a stream over its own budget", func(t *testing.T) {
+		metadataService := redis.NewStreamMetadataServiceMock()
+		streamService := redis.NewRedisStreamServiceMock()
+		policy := NewSizeRetentionPolicy(&SizeRetentionPolicyOpts{
+			StreamMetadataservice: metadataService,
+			Streamservice:         streamService,
+			MaxSize:               1000000,
+			MessageBatchSize:      100,
+		}, testutils.NewMockLogger())
+
+		metadataService.On("GetStreamMetadata", "hash").Return(&redis.StreamMetadata{Name: "test-stream", CleanupPolicy: "delete", MaxSize: 500}, nil)
+		streamService.On("GetStreamSize", "test-stream").Return(&redis.StreamSize{Bytes: 1000, Length: 10}, nil)
+		streamService.On("GetMessageIDAt", "test-stream", int64(5), int64(100)).Return("6-0", nil)
+		streamService.On("DeleteMessagesOlderThan", "test-stream", "6-0", int64(0)).Return(nil)
+
+		err := policy.ApplyPolicy(context.Background(), "hash", 0)
+
+		assert.NoError(t, err)
+		streamService.AssertExpectations(t)
+	})
+
+	t.Run("Trim a compacted stream over its budget instead of compacting it again", func(t *testing.T) {
+		metadataService := redis.NewStreamMetadataServiceMock()
+		streamService := redis.NewRedisStreamServiceMock()
+		policy := NewSizeRetentionPolicy(&SizeRetentionPolicyOpts{
+			StreamMetadataservice: metadataService,
+			Streamservice:         streamService,
+			MessageBatchSize:      100,
+		}, testutils.NewMockLogger())
+
+		metadataService.On("GetStreamMetadata", "hash").Return(&redis.StreamMetadata{Name: "test-stream", CleanupPolicy: "compact", CompactionKeyField: "key", MaxSize: 500}, nil)
+		streamService.On("GetStreamSize", "test-stream").Return(&redis.StreamSize{Bytes: 1000, Length: 10}, nil)
+		streamService.On("GetMessageIDAt", "test-stream", int64(5), int64(100)).Return("6-0", nil)
+		streamService.On("DeleteMessagesOlderThan", "test-stream", "6-0", int64(3)).Return(nil)
+
+		err := policy.ApplyPolicy(context.Background(), "hash", 3)
+
+		assert.NoError(t, err)
+		streamService.AssertExpectations(t)
+		streamService.AssertNotCalled(t, "DeleteMessages", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Skip streams when no budget is set", func(t *testing.T) {
+		metadataService := redis.NewStreamMetadataServiceMock()
+		streamService := redis.NewRedisStreamServiceMock()
+		policy := NewSizeRetentionPolicy(&SizeRetentionPolicyOpts{
+			StreamMetadataservice: metadataService,
+			Streamservice:         streamService,
+		}, testutils.NewMockLogger())
+
+		metadataService.On("GetStreamMetadata", "hash").Return(&redis.StreamMetadata{Name: "test-stream", CleanupPolicy: "delete"}, nil)
+
+		err := policy.ApplyPolicy(context.Background(), "hash", 0)
+
+		assert.NoError(t, err)
+		streamService.AssertNotCalled(t, "GetStreamSize", "test-stream")
+	})
+}
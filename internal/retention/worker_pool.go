@@ -0,0 +1,128 @@
+package retention
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type WorkerPoolOptions struct {
+	// Number of streams a policy is applied to concurrently
+	Workers int
+	// Time a policy may take on a single stream before the run moves on, zero disables the timeout
+	StreamTimeout time.Duration
+	// Upper bound of the random delay before a policy is applied to a stream, spreads the load on Redis and storage
+	Jitter time.Duration
+}
+
+// Fills in defaults for missing worker pool options
+func NewWorkerPoolOptions(opts *WorkerPoolOptions) *WorkerPoolOptions {
+	if opts == nil {
+		opts = &WorkerPoolOptions{}
+	}
+
+	if opts.Workers <= 0 {
+		opts.Workers = 1
+	}
+
+	return opts
+}
+
+// Applies a policy to the streams, or every registered stream when nil, with a bounded number of workers.
+// A failing stream does not stop the remaining ones
+func (s *StreamCleaner) ApplyToStreams(ctx context.Context, policy string, streams []string, apply func(ctx context.Context, stream string) error) (*EnforcementResult, error) {
+	if streams == nil {
+		s.Logger.Debug("Retrieving affected streams...", zap.String("policy", policy))
+		registered, err := s.Metadataservice.ListStreams()
+		if err != nil {
+			return nil, err
+		}
+		streams = registered
+	}
+
+	result := &EnforcementResult{}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	jobs := make(chan string)
+
+	for i := 0; i < s.WorkerPool.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for stream := range jobs {
+				applied, err := s.ApplyToStream(ctx, stream, apply)
+
+				mu.Lock()
+				switch {
+				case !applied:
+					result.Skipped++
+					s.Logger.Debug("Skipping stream, the previous run of the policy is still in progress", zap.String("policy", policy), zap.String("stream_hash", stream))
+				case err != nil:
+					result.Streams++
+					result.Failed++
+					s.Logger.Error("Failed to apply retention policy to stream", zap.String("policy", policy), zap.String("stream_hash", stream), zap.Error(err))
+				default:
+					result.Streams++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+dispatch:
+	for _, stream := range streams {
+		select {
+		case <-ctx.Done():
+			break dispatch
+		case jobs <- stream:
+		}
+	}
+
+	close(jobs)
+	wg.Wait()
+
+	return result, ctx.Err()
+}
+
+// Applies a policy to a single stream unless a previous run is still in progress, returns whether the policy was applied.
+// A run exceeding the stream timeout is reported as failed and left to finish in the background.
+func (s *StreamCleaner) ApplyToStream(ctx context.Context, stream string, apply func(ctx context.Context, stream string) error) (bool, error) {
+	if _, running := s.inFlight.LoadOrStore(stream, struct{}{}); running {
+		return false, nil
+	}
+
+	if s.WorkerPool.Jitter > 0 {
+		select {
+		case <-ctx.Done():
+			s.inFlight.Delete(stream)
+			return true, ctx.Err()
+		case <-time.After(rand.N(s.WorkerPool.Jitter)):
+		}
+	}
+
+	var streamCtx context.Context
+	var cancel context.CancelFunc
+	if s.WorkerPool.StreamTimeout > 0 {
+		streamCtx, cancel = context.WithTimeout(ctx, s.WorkerPool.StreamTimeout)
+	} else {
+		streamCtx, cancel = context.WithCancel(ctx)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		defer s.inFlight.Delete(stream)
+		defer cancel()
+		done <- apply(streamCtx, stream)
+	}()
+
+	select {
+	case err := <-done:
+		return true, err
+	case <-streamCtx.Done():
+		return true, fmt.Errorf("retention policy did not finish on stream: %w", streamCtx.Err())
+	}
+}
@@ -0,0 +1,71 @@
+package retention
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/streamweaverio/broker/internal/redis"
+	"github.com/streamweaverio/broker/internal/testutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupWorkerPoolCleaner(workerPool *WorkerPoolOptions) (*StreamCleaner, *redis.StreamMetadataServiceMock) {
+	metadataService := redis.NewStreamMetadataServiceMock()
+	cleaner := NewStreamCleaner(&StreamCleanerOptions{
+		StreamMetadataservice: metadataService,
+		Streamservice:         redis.NewRedisStreamServiceMock(),
+		WorkerPool:            workerPool,
+	}, testutils.NewMockLogger())
+
+	return cleaner, metadataService
+}
+
+func TestStreamCleaner_ApplyToStreams(t *testing.T) {
+	t.Run("Apply the policy to streams concurrently up to the number of workers", func(t *testing.T) {
+		cleaner, metadataService := setupWorkerPoolCleaner(&WorkerPoolOptions{Workers: 2})
+		metadataService.On("ListStreams").Return([]string{"a", "b", "c", "d"}, nil)
+
+		var running, maxRunning atomic.Int32
+		result, err := cleaner.ApplyToStreams(context.Background(), "time", nil, func(ctx context.Context, stream string) error {
+			current := running.Add(1)
+			defer running.Add(-1)
+			for {
+				previous := maxRunning.Load()
+				if current <= previous || maxRunning.CompareAndSwap(previous, current) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 4, result.Streams)
+		assert.LessOrEqual(t, maxRunning.Load(), int32(2))
+	})
+
+	t.Run("Skip streams still running after a timeout in the previous run", func(t *testing.T) {
+		cleaner, metadataService := setupWorkerPoolCleaner(&WorkerPoolOptions{Workers: 1, StreamTimeout: 10 * time.Millisecond})
+		metadataService.On("ListStreams").Return([]string{"slow"}, nil)
+
+		release := make(chan struct{})
+		// Ignores its context like a request that cannot be interrupted
+		apply := func(ctx context.Context, stream string) error {
+			<-release
+			return nil
+		}
+
+		first, err := cleaner.ApplyToStreams(context.Background(), "time", nil, apply)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, first.Failed)
+
+		second, err := cleaner.ApplyToStreams(context.Background(), "time", nil, apply)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, second.Skipped)
+		assert.Equal(t, 0, second.Streams)
+
+		close(release)
+	})
+}
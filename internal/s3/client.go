@@ -3,6 +3,7 @@ package s3
 import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
 	S3 "github.com/aws/aws-sdk-go/service/s3"
 )
@@ -10,20 +11,33 @@ import (
 // S3Client defines common S3 operations
 type Client interface {
 	GetObject(input *S3.GetObjectInput) (*S3.GetObjectOutput, error)
+	GetObjectWithContext(ctx aws.Context, input *S3.GetObjectInput, opts ...request.Option) (*S3.GetObjectOutput, error)
+	ListObjectsV2WithContext(ctx aws.Context, input *S3.ListObjectsV2Input, opts ...request.Option) (*S3.ListObjectsV2Output, error)
+	PutObjectWithContext(ctx aws.Context, input *S3.PutObjectInput, opts ...request.Option) (*S3.PutObjectOutput, error)
+	CreateMultipartUploadWithContext(ctx aws.Context, input *S3.CreateMultipartUploadInput, opts ...request.Option) (*S3.CreateMultipartUploadOutput, error)
+	UploadPartWithContext(ctx aws.Context, input *S3.UploadPartInput, opts ...request.Option) (*S3.UploadPartOutput, error)
+	CompleteMultipartUploadWithContext(ctx aws.Context, input *S3.CompleteMultipartUploadInput, opts ...request.Option) (*S3.CompleteMultipartUploadOutput, error)
+	AbortMultipartUploadWithContext(ctx aws.Context, input *S3.AbortMultipartUploadInput, opts ...request.Option) (*S3.AbortMultipartUploadOutput, error)
+	DeleteObjectWithContext(ctx aws.Context, input *S3.DeleteObjectInput, opts ...request.Option) (*S3.DeleteObjectOutput, error)
 }
 
 type S3ClientOptions struct {
+	// When the access key is omitted the default AWS credential chain is used; environment, shared profile and instance role
 	AccessKeyId     string
 	AccessKeySecret string
+	SessionToken    string
 	Region          string
+	// Custom endpoint for S3-compatible stores
+	Endpoint       string
+	ForcePathStyle bool
+	DisableSSL     bool
 }
 
 func NewClient(opts *S3ClientOptions) (Client, error) {
 	sess, err := session.NewSessionWithOptions(session.Options{
-		Config: aws.Config{
-			Region:      aws.String(opts.Region),
-			Credentials: credentials.NewStaticCredentials(opts.AccessKeyId, opts.AccessKeySecret, ""),
-		},
+		Config: *NewClientConfig(opts),
+		// Load the shared config file so profiles can provide credentials
+		SharedConfigState: session.SharedConfigEnable,
 	})
 
 	if err != nil {
@@ -34,3 +48,23 @@ func NewClient(opts *S3ClientOptions) (Client, error) {
 
 	return client, nil
 }
+
+// Creates the AWS configuration for the client options
+func NewClientConfig(opts *S3ClientOptions) *aws.Config {
+	cfg := &aws.Config{
+		Region:           aws.String(opts.Region),
+		S3ForcePathStyle: aws.Bool(opts.ForcePathStyle),
+		DisableSSL:       aws.Bool(opts.DisableSSL),
+	}
+
+	if opts.Endpoint != "" {
+		cfg.Endpoint = aws.String(opts.Endpoint)
+	}
+
+	// Leaving the credentials unset makes the session fall back to the default credential chain
+	if opts.AccessKeyId != "" {
+		cfg.Credentials = credentials.NewStaticCredentials(opts.AccessKeyId, opts.AccessKeySecret, opts.SessionToken)
+	}
+
+	return cfg
+}
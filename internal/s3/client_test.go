@@ -0,0 +1,38 @@
+package s3
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewClientConfig(t *testing.T) {
+	t.Run("Use static credentials and a custom endpoint", func(t *testing.T) {
+		cfg := NewClientConfig(&S3ClientOptions{
+			AccessKeyId:     "minioadmin",
+			AccessKeySecret: "minioadmin",
+			SessionToken:    "token",
+			Region:          "us-east-1",
+			Endpoint:        "http://minio:9000",
+			ForcePathStyle:  true,
+			DisableSSL:      true,
+		})
+
+		assert.Equal(t, "http://minio:9000", aws.StringValue(cfg.Endpoint))
+		assert.True(t, aws.BoolValue(cfg.S3ForcePathStyle))
+		assert.True(t, aws.BoolValue(cfg.DisableSSL))
+
+		value, err := cfg.Credentials.Get()
+		assert.NoError(t, err)
+		assert.Equal(t, "minioadmin", value.AccessKeyID)
+		assert.Equal(t, "token", value.SessionToken)
+	})
+
+	t.Run("Leave credentials unset to use the default credential chain", func(t *testing.T) {
+		cfg := NewClientConfig(&S3ClientOptions{Region: "us-west-2"})
+
+		assert.Nil(t, cfg.Credentials)
+		assert.Nil(t, cfg.Endpoint)
+	})
+}
@@ -0,0 +1,250 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	S3 "github.com/aws/aws-sdk-go/service/s3"
+)
+
+// In-process stand-in for an S3-compatible object store which keeps objects in memory
+type FakeClient struct {
+	mu           sync.Mutex
+	objects      map[string][]byte
+	uploads      map[string]*fakeMultipartUpload
+	nextUploadId int
+	// Number of multipart uploads that were completed
+	CompletedMultipartUploads int
+	// Number of multipart uploads that were aborted
+	AbortedMultipartUploads int
+}
+
+type fakeMultipartUpload struct {
+	Bucket string
+	Key    string
+	Parts  map[int64][]byte
+}
+
+func NewFakeClient() *FakeClient {
+	return &FakeClient{
+		objects: make(map[string][]byte),
+		uploads: make(map[string]*fakeMultipartUpload),
+	}
+}
+
+func fakeObjectKey(bucket string, key string) string {
+	return bucket + "/" + key
+}
+
+func fakeETag(data []byte) *string {
+	return aws.String(fmt.Sprintf("\"%x\"", md5.Sum(data)))
+}
+
+// Gets the content of an object, returns false when the object does not exist
+func (c *FakeClient) Object(bucket string, key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data, ok := c.objects[fakeObjectKey(bucket, key)]
+	return data, ok
+}
+
+// Number of multipart uploads that were created but neither completed nor aborted
+func (c *FakeClient) PendingMultipartUploads() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.uploads)
+}
+
+func (c *FakeClient) GetObject(input *S3.GetObjectInput) (*S3.GetObjectOutput, error) {
+	return c.GetObjectWithContext(context.Background(), input)
+}
+
+func (c *FakeClient) GetObjectWithContext(ctx aws.Context, input *S3.GetObjectInput, opts ...request.Option) (*S3.GetObjectOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data, ok := c.objects[fakeObjectKey(aws.StringValue(input.Bucket), aws.StringValue(input.Key))]
+	if !ok {
+		return nil, awserr.New(S3.ErrCodeNoSuchKey, "The specified key does not exist.", nil)
+	}
+
+	return &S3.GetObjectOutput{
+		Body:          io.NopCloser(bytes.NewReader(data)),
+		ContentLength: aws.Int64(int64(len(data))),
+		ETag:          fakeETag(data),
+	}, nil
+}
+
+// Lists objects in key order, pages hold at most MaxKeys objects and default to 1000
+func (c *FakeClient) ListObjectsV2WithContext(ctx aws.Context, input *S3.ListObjectsV2Input, opts ...request.Option) (*S3.ListObjectsV2Output, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	bucketPrefix := fakeObjectKey(aws.StringValue(input.Bucket), "")
+	prefix := aws.StringValue(input.Prefix)
+	start := aws.StringValue(input.ContinuationToken)
+
+	keys := make([]string, 0)
+	for objectKey := range c.objects {
+		key, ok := strings.CutPrefix(objectKey, bucketPrefix)
+		if ok && strings.HasPrefix(key, prefix) && key > start {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	maxKeys := int(aws.Int64Value(input.MaxKeys))
+	if maxKeys <= 0 {
+		maxKeys = 1000
+	}
+
+	output := &S3.ListObjectsV2Output{
+		Name:        input.Bucket,
+		Prefix:      input.Prefix,
+		IsTruncated: aws.Bool(len(keys) > maxKeys),
+	}
+
+	if len(keys) > maxKeys {
+		keys = keys[:maxKeys]
+		// The continuation token is the last key of the page
+		output.NextContinuationToken = aws.String(keys[len(keys)-1])
+	}
+
+	for _, key := range keys {
+		output.Contents = append(output.Contents, &S3.Object{
+			Key:  aws.String(key),
+			Size: aws.Int64(int64(len(c.objects[bucketPrefix+key]))),
+		})
+	}
+	output.KeyCount = aws.Int64(int64(len(output.Contents)))
+
+	return output, nil
+}
+
+func (c *FakeClient) PutObjectWithContext(ctx aws.Context, input *S3.PutObjectInput, opts ...request.Option) (*S3.PutObjectOutput, error) {
+	var data []byte
+	if input.Body != nil {
+		var err error
+		data, err = io.ReadAll(input.Body)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.objects[fakeObjectKey(aws.StringValue(input.Bucket), aws.StringValue(input.Key))] = data
+
+	return &S3.PutObjectOutput{ETag: fakeETag(data)}, nil
+}
+
+func (c *FakeClient) CreateMultipartUploadWithContext(ctx aws.Context, input *S3.CreateMultipartUploadInput, opts ...request.Option) (*S3.CreateMultipartUploadOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.nextUploadId++
+	uploadId := fmt.Sprintf("upload-%d", c.nextUploadId)
+	c.uploads[uploadId] = &fakeMultipartUpload{
+		Bucket: aws.StringValue(input.Bucket),
+		Key:    aws.StringValue(input.Key),
+		Parts:  make(map[int64][]byte),
+	}
+
+	return &S3.CreateMultipartUploadOutput{
+		Bucket:   input.Bucket,
+		Key:      input.Key,
+		UploadId: aws.String(uploadId),
+	}, nil
+}
+
+func (c *FakeClient) UploadPartWithContext(ctx aws.Context, input *S3.UploadPartInput, opts ...request.Option) (*S3.UploadPartOutput, error) {
+	data, err := io.ReadAll(input.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	upload, ok := c.uploads[aws.StringValue(input.UploadId)]
+	if !ok {
+		return nil, awserr.New(S3.ErrCodeNoSuchUpload, "The specified upload does not exist.", nil)
+	}
+
+	upload.Parts[aws.Int64Value(input.PartNumber)] = data
+
+	return &S3.UploadPartOutput{ETag: fakeETag(data)}, nil
+}
+
+func (c *FakeClient) CompleteMultipartUploadWithContext(ctx aws.Context, input *S3.CompleteMultipartUploadInput, opts ...request.Option) (*S3.CompleteMultipartUploadOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	uploadId := aws.StringValue(input.UploadId)
+	upload, ok := c.uploads[uploadId]
+	if !ok {
+		return nil, awserr.New(S3.ErrCodeNoSuchUpload, "The specified upload does not exist.", nil)
+	}
+
+	parts := input.MultipartUpload.Parts
+	sort.Slice(parts, func(i, j int) bool {
+		return aws.Int64Value(parts[i].PartNumber) < aws.Int64Value(parts[j].PartNumber)
+	})
+
+	var data []byte
+	for _, part := range parts {
+		partData, ok := upload.Parts[aws.Int64Value(part.PartNumber)]
+		if !ok || aws.StringValue(fakeETag(partData)) != aws.StringValue(part.ETag) {
+			return nil, awserr.New("InvalidPart", "One or more of the specified parts could not be found.", nil)
+		}
+		data = append(data, partData...)
+	}
+
+	c.objects[fakeObjectKey(upload.Bucket, upload.Key)] = data
+	delete(c.uploads, uploadId)
+	c.CompletedMultipartUploads++
+
+	return &S3.CompleteMultipartUploadOutput{
+		Bucket: input.Bucket,
+		Key:    input.Key,
+		ETag:   fakeETag(data),
+	}, nil
+}
+
+func (c *FakeClient) AbortMultipartUploadWithContext(ctx aws.Context, input *S3.AbortMultipartUploadInput, opts ...request.Option) (*S3.AbortMultipartUploadOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	uploadId := aws.StringValue(input.UploadId)
+	if _, ok := c.uploads[uploadId]; !ok {
+		return nil, awserr.New(S3.ErrCodeNoSuchUpload, "The specified upload does not exist.", nil)
+	}
+
+	delete(c.uploads, uploadId)
+	c.AbortedMultipartUploads++
+
+	return &S3.AbortMultipartUploadOutput{}, nil
+}
+
+func (c *FakeClient) DeleteObjectWithContext(ctx aws.Context, input *S3.DeleteObjectInput, opts ...request.Option) (*S3.DeleteObjectOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.objects, fakeObjectKey(aws.StringValue(input.Bucket), aws.StringValue(input.Key)))
+
+	return &S3.DeleteObjectOutput{}, nil
+}
+
+var _ Client = (*FakeClient)(nil)
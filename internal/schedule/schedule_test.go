@@ -0,0 +1,77 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCron(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Value       string
+		ExpectError bool
+	}{
+		{Name: "Every minute", Value: "* * * * *"},
+		{Name: "Ranges, steps and lists", Value: "*/15 1-5 1,15 * 1-5/2"},
+		{Name: "Sunday as 7", Value: "0 3 * * 7"},
+		{Name: "Missing field", Value: "0 3 * *", ExpectError: true},
+		{Name: "Out of range", Value: "60 3 * * *", ExpectError: true},
+		{Name: "Invalid step", Value: "*/0 3 * * *", ExpectError: true},
+		{Name: "Reversed range", Value: "0 5-1 * * *", ExpectError: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			_, err := ParseCron(test.Value)
+			if test.ExpectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCron_Next(t *testing.T) {
+	// Wednesday
+	now := time.Date(2024, 12, 4, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		Name     string
+		Value    string
+		Expected time.Time
+	}{
+		{Name: "Every minute", Value: "* * * * *", Expected: time.Date(2024, 12, 4, 10, 31, 0, 0, time.UTC)},
+		{Name: "Daily at 02:00", Value: "0 2 * * *", Expected: time.Date(2024, 12, 5, 2, 0, 0, 0, time.UTC)},
+		{Name: "Every 15 minutes", Value: "*/15 * * * *", Expected: time.Date(2024, 12, 4, 10, 45, 0, 0, time.UTC)},
+		{Name: "Sundays", Value: "0 3 * * 0", Expected: time.Date(2024, 12, 8, 3, 0, 0, 0, time.UTC)},
+		{Name: "First of the month", Value: "0 0 1 * *", Expected: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{Name: "Day of month or day of week", Value: "0 0 10 * 5", Expected: time.Date(2024, 12, 6, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			cron, err := ParseCron(test.Value)
+			assert.NoError(t, err)
+			assert.Equal(t, test.Expected, cron.Next(now))
+		})
+	}
+}
+
+func TestInWindows(t *testing.T) {
+	windows, err := ParseWindows([]string{"01:00-05:00", "23:00-00:30"})
+	assert.NoError(t, err)
+
+	assert.True(t, InWindows(windows, time.Date(2024, 12, 4, 1, 0, 0, 0, time.UTC)))
+	assert.False(t, InWindows(windows, time.Date(2024, 12, 4, 5, 0, 0, 0, time.UTC)))
+	assert.True(t, InWindows(windows, time.Date(2024, 12, 4, 0, 15, 0, 0, time.UTC)))
+	assert.False(t, InWindows(windows, time.Date(2024, 12, 4, 12, 0, 0, 0, time.UTC)))
+	assert.True(t, InWindows(nil, time.Date(2024, 12, 4, 12, 0, 0, 0, time.UTC)))
+
+	_, err = ParseWindow("01:00")
+	assert.Error(t, err)
+	_, err = ParseWindow("25:00-01:00")
+	assert.Error(t, err)
+}
@@ -0,0 +1,86 @@
+package schedule
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Daily time window in UTC, wraps around midnight when it ends before it starts
+type Window struct {
+	// Minutes since midnight the window starts at, inclusive
+	Start int
+	// Minutes since midnight the window ends at, exclusive
+	End int
+}
+
+// Parses a window in the format HH:MM-HH:MM, e.g. 01:00-05:00
+func ParseWindow(value string) (*Window, error) {
+	bounds := strings.SplitN(value, "-", 2)
+	if len(bounds) != 2 {
+		return nil, fmt.Errorf("invalid time window %q: expected HH:MM-HH:MM", value)
+	}
+
+	start, err := parseTimeOfDay(bounds[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid time window %q: %w", value, err)
+	}
+
+	end, err := parseTimeOfDay(bounds[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid time window %q: %w", value, err)
+	}
+
+	if start == end {
+		return nil, fmt.Errorf("invalid time window %q: start and end cannot be equal", value)
+	}
+
+	return &Window{Start: start, End: end}, nil
+}
+
+// Parses a list of windows
+func ParseWindows(values []string) ([]*Window, error) {
+	windows := make([]*Window, 0, len(values))
+	for _, value := range values {
+		window, err := ParseWindow(value)
+		if err != nil {
+			return nil, err
+		}
+		windows = append(windows, window)
+	}
+	return windows, nil
+}
+
+func parseTimeOfDay(value string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("expected HH:MM, got %q", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func (w *Window) Contains(t time.Time) bool {
+	t = t.UTC()
+	minute := t.Hour()*60 + t.Minute()
+
+	if w.Start < w.End {
+		return minute >= w.Start && minute < w.End
+	}
+
+	return minute >= w.Start || minute < w.End
+}
+
+// Whether t falls in any of the windows, always true without windows
+func InWindows(windows []*Window, t time.Time) bool {
+	if len(windows) == 0 {
+		return true
+	}
+
+	for _, window := range windows {
+		if window.Contains(t) {
+			return true
+		}
+	}
+
+	return false
+}
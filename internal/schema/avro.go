@@ -0,0 +1,272 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/linkedin/goavro/v2"
+)
+
+type AvroValidator struct {
+	codec *goavro.Codec
+}
+
+func CompileAvroSchema(definition []byte) (*AvroValidator, error) {
+	codec, err := goavro.NewCodec(string(definition))
+	if err != nil {
+		return nil, fmt.Errorf("invalid Avro schema: %w", err)
+	}
+
+	return &AvroValidator{codec: codec}, nil
+}
+
+// Payloads are a single datum in the Avro binary encoding
+func (v *AvroValidator) Validate(payload []byte) error {
+	_, remaining, err := v.codec.NativeFromBinary(payload)
+	if err != nil {
+		return fmt.Errorf("payload does not match the Avro schema: %w", err)
+	}
+
+	if len(remaining) > 0 {
+		return fmt.Errorf("payload has %d bytes after the Avro datum", len(remaining))
+	}
+
+	return nil
+}
+
+// Avro numeric promotions and string/bytes conversions allowed by schema resolution, keyed by writer type
+var avroPromotions = map[string][]string{
+	"int":    {"long", "float", "double"},
+	"long":   {"float", "double"},
+	"float":  {"double"},
+	"string": {"bytes"},
+	"bytes":  {"string"},
+}
+
+type avroResolver struct {
+	readerNames map[string]map[string]interface{}
+	writerNames map[string]map[string]interface{}
+	// Named type pairs already being compared, recursive types are compatible unless another part differs
+	seen map[string]bool
+}
+
+// Checks whether data written with the writer schema can be read with the reader schema following Avro schema resolution
+func CanReadAvro(reader []byte, writer []byte) error {
+	var readerSchema, writerSchema interface{}
+	if err := json.Unmarshal(reader, &readerSchema); err != nil {
+		return fmt.Errorf("invalid Avro schema: %w", err)
+	}
+
+	if err := json.Unmarshal(writer, &writerSchema); err != nil {
+		return fmt.Errorf("invalid Avro schema: %w", err)
+	}
+
+	resolver := &avroResolver{
+		readerNames: make(map[string]map[string]interface{}),
+		writerNames: make(map[string]map[string]interface{}),
+		seen:        make(map[string]bool),
+	}
+	collectAvroNames(readerSchema, "", resolver.readerNames)
+	collectAvroNames(writerSchema, "", resolver.writerNames)
+
+	return resolver.canRead("", readerSchema, writerSchema)
+}
+
+func (r *avroResolver) canRead(path string, reader interface{}, writer interface{}) error {
+	reader = resolveAvroType(reader, r.readerNames)
+	writer = resolveAvroType(writer, r.writerNames)
+
+	// Every branch a writer union may have written must be readable
+	if writerUnion, ok := writer.([]interface{}); ok {
+		for _, branch := range writerUnion {
+			if err := r.canRead(path, reader, branch); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if readerUnion, ok := reader.([]interface{}); ok {
+		for _, branch := range readerUnion {
+			if r.canRead(path, branch, writer) == nil {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s: no union branch can read %s", avroPath(path), getAvroTypeName(writer))
+	}
+
+	readerType := getAvroTypeName(reader)
+	writerType := getAvroTypeName(writer)
+	if readerType != writerType {
+		if slices.Contains(avroPromotions[writerType], readerType) {
+			return nil
+		}
+		return fmt.Errorf("%s: type changed from %s to %s", avroPath(path), writerType, readerType)
+	}
+
+	readerObject, _ := reader.(map[string]interface{})
+	writerObject, _ := writer.(map[string]interface{})
+
+	switch readerType {
+	case "record":
+		return r.canReadRecord(path, readerObject, writerObject)
+	case "enum":
+		if err := checkAvroNames(path, readerObject, writerObject); err != nil {
+			return err
+		}
+
+		if _, ok := readerObject["default"]; ok {
+			return nil
+		}
+
+		readerSymbols := getJSONStrings(readerObject["symbols"])
+		for _, symbol := range getJSONStrings(writerObject["symbols"]) {
+			if !slices.Contains(readerSymbols, symbol) {
+				return fmt.Errorf("%s: enum symbol %s was removed", avroPath(path), symbol)
+			}
+		}
+	case "fixed":
+		if err := checkAvroNames(path, readerObject, writerObject); err != nil {
+			return err
+		}
+
+		if readerObject["size"] != writerObject["size"] {
+			return fmt.Errorf("%s: fixed size changed from %v to %v", avroPath(path), writerObject["size"], readerObject["size"])
+		}
+	case "array":
+		return r.canRead(path+"[]", readerObject["items"], writerObject["items"])
+	case "map":
+		return r.canRead(path+"{}", readerObject["values"], writerObject["values"])
+	}
+
+	return nil
+}
+
+func (r *avroResolver) canReadRecord(path string, reader map[string]interface{}, writer map[string]interface{}) error {
+	if err := checkAvroNames(path, reader, writer); err != nil {
+		return err
+	}
+
+	key := fmt.Sprintf("%v|%v", reader["name"], writer["name"])
+	if r.seen[key] {
+		return nil
+	}
+	r.seen[key] = true
+
+	writerFields := make(map[string]map[string]interface{})
+	for _, field := range getAvroFields(writer) {
+		writerFields[fmt.Sprint(field["name"])] = field
+	}
+
+	for _, field := range getAvroFields(reader) {
+		name := fmt.Sprint(field["name"])
+		fieldPath := strings.TrimPrefix(path+"."+name, ".")
+
+		writerField, ok := writerFields[name]
+		if !ok {
+			if _, ok := field["default"]; !ok {
+				return fmt.Errorf("%s: field was added without a default", fieldPath)
+			}
+			continue
+		}
+
+		if err := r.canRead(fieldPath, field["type"], writerField["type"]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Registers every named type of a schema by its name and full name
+func collectAvroNames(schema interface{}, namespace string, names map[string]map[string]interface{}) {
+	switch value := schema.(type) {
+	case []interface{}:
+		for _, branch := range value {
+			collectAvroNames(branch, namespace, names)
+		}
+	case map[string]interface{}:
+		if name, ok := value["name"].(string); ok && slices.Contains([]string{"record", "enum", "fixed"}, fmt.Sprint(value["type"])) {
+			if ns, ok := value["namespace"].(string); ok {
+				namespace = ns
+			}
+			names[name] = value
+			if namespace != "" && !strings.Contains(name, ".") {
+				names[namespace+"."+name] = value
+			}
+		}
+
+		for _, field := range getAvroFields(value) {
+			collectAvroNames(field["type"], namespace, names)
+		}
+		collectAvroNames(value["items"], namespace, names)
+		collectAvroNames(value["values"], namespace, names)
+		// Complex types written as {"type": {...}}
+		if nested, ok := value["type"].(map[string]interface{}); ok {
+			collectAvroNames(nested, namespace, names)
+		}
+	}
+}
+
+// Replaces references to named types with their definition and unwraps types written as {"type": ...}
+func resolveAvroType(schema interface{}, names map[string]map[string]interface{}) interface{} {
+	switch value := schema.(type) {
+	case string:
+		if named, ok := names[value]; ok {
+			return named
+		}
+	case map[string]interface{}:
+		if _, ok := value["type"].(string); !ok {
+			return resolveAvroType(value["type"], names)
+		}
+		if name := fmt.Sprint(value["type"]); names[name] != nil {
+			return names[name]
+		}
+	}
+	return schema
+}
+
+func getAvroTypeName(schema interface{}) string {
+	switch value := schema.(type) {
+	case string:
+		return value
+	case map[string]interface{}:
+		return fmt.Sprint(value["type"])
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+func getAvroFields(schema map[string]interface{}) []map[string]interface{} {
+	values, _ := schema["fields"].([]interface{})
+	fields := make([]map[string]interface{}, 0, len(values))
+	for _, value := range values {
+		if field, ok := value.(map[string]interface{}); ok {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// Named types only resolve to each other when their unqualified names match
+func checkAvroNames(path string, reader map[string]interface{}, writer map[string]interface{}) error {
+	readerName := getAvroShortName(fmt.Sprint(reader["name"]))
+	writerName := getAvroShortName(fmt.Sprint(writer["name"]))
+	if readerName != writerName {
+		return fmt.Errorf("%s: named type changed from %s to %s", avroPath(path), writerName, readerName)
+	}
+	return nil
+}
+
+func getAvroShortName(name string) string {
+	return name[strings.LastIndex(name, ".")+1:]
+}
+
+func avroPath(path string) string {
+	if path == "" {
+		return "schema"
+	}
+	return path
+}
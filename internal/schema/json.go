@@ -0,0 +1,177 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+type JSONSchemaValidator struct {
+	schema *jsonschema.Schema
+}
+
+func CompileJSONSchema(definition []byte) (*JSONSchemaValidator, error) {
+	schema, err := jsonschema.CompileString("schema.json", string(definition))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON schema: %w", err)
+	}
+
+	return &JSONSchemaValidator{schema: schema}, nil
+}
+
+func (v *JSONSchemaValidator) Validate(payload []byte) error {
+	value, err := DecodeJSON(payload)
+	if err != nil {
+		return fmt.Errorf("payload is not valid JSON: %w", err)
+	}
+
+	return v.schema.Validate(value)
+}
+
+// Decodes a single JSON document keeping numbers exact
+func DecodeJSON(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, fmt.Errorf("unexpected data after the JSON document")
+	}
+
+	return value, nil
+}
+
+// Checks whether documents valid under the writer schema are valid under the reader schema. This is a conservative
+// structural comparison of types, enums, properties, required properties and array items, other keywords are not compared
+func CanReadJSON(reader []byte, writer []byte) error {
+	readerSchema, err := DecodeJSON(reader)
+	if err != nil {
+		return fmt.Errorf("invalid JSON schema: %w", err)
+	}
+
+	writerSchema, err := DecodeJSON(writer)
+	if err != nil {
+		return fmt.Errorf("invalid JSON schema: %w", err)
+	}
+
+	return canReadJSON("#", readerSchema, writerSchema)
+}
+
+func canReadJSON(path string, reader interface{}, writer interface{}) error {
+	// Boolean schemas accept every document or none
+	if value, ok := reader.(bool); ok {
+		if !value && writer != false {
+			return fmt.Errorf("%s: documents are no longer allowed", path)
+		}
+		return nil
+	}
+
+	if writer == false {
+		return nil
+	}
+
+	readerObject, _ := reader.(map[string]interface{})
+	writerObject, _ := writer.(map[string]interface{})
+	if writerObject == nil {
+		writerObject = map[string]interface{}{}
+	}
+
+	if readerTypes := getJSONTypes(readerObject); readerTypes != nil {
+		writerTypes := getJSONTypes(writerObject)
+		if writerTypes == nil {
+			return fmt.Errorf("%s: type is restricted to %v", path, readerTypes)
+		}
+
+		for _, writerType := range writerTypes {
+			if !slices.Contains(readerTypes, writerType) && !(writerType == "integer" && slices.Contains(readerTypes, "number")) {
+				return fmt.Errorf("%s: type %s is no longer allowed", path, writerType)
+			}
+		}
+	}
+
+	if readerEnum, ok := readerObject["enum"].([]interface{}); ok {
+		writerEnum, ok := writerObject["enum"].([]interface{})
+		if !ok {
+			return fmt.Errorf("%s: values are restricted to an enum", path)
+		}
+
+		for _, value := range writerEnum {
+			if !containsJSONValue(readerEnum, value) {
+				return fmt.Errorf("%s: enum value %v is no longer allowed", path, value)
+			}
+		}
+	}
+
+	readerProperties, _ := readerObject["properties"].(map[string]interface{})
+	writerProperties, _ := writerObject["properties"].(map[string]interface{})
+	for name, writerProperty := range writerProperties {
+		readerProperty, ok := readerProperties[name]
+		if !ok {
+			readerProperty, ok = readerObject["additionalProperties"]
+		}
+
+		if ok {
+			if err := canReadJSON(path+"/properties/"+name, readerProperty, writerProperty); err != nil {
+				return err
+			}
+		}
+	}
+
+	writerRequired := getJSONStrings(writerObject["required"])
+	for _, name := range getJSONStrings(readerObject["required"]) {
+		if !slices.Contains(writerRequired, name) {
+			return fmt.Errorf("%s: property %s is required but was optional", path, name)
+		}
+	}
+
+	if readerItems, ok := readerObject["items"]; ok {
+		if writerItems, ok := writerObject["items"]; ok {
+			if err := canReadJSON(path+"/items", readerItems, writerItems); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Types allowed by a schema, nil when the schema allows every type
+func getJSONTypes(schema map[string]interface{}) []string {
+	switch value := schema["type"].(type) {
+	case string:
+		return []string{value}
+	case []interface{}:
+		return getJSONStrings(value)
+	default:
+		return nil
+	}
+}
+
+func getJSONStrings(value interface{}) []string {
+	values, _ := value.([]interface{})
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if s, ok := value.(string); ok {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+func containsJSONValue(values []interface{}, value interface{}) bool {
+	encoded, _ := json.Marshal(value)
+	for _, candidate := range values {
+		if other, _ := json.Marshal(candidate); bytes.Equal(encoded, other) {
+			return true
+		}
+	}
+	return false
+}
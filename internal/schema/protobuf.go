@@ -0,0 +1,134 @@
+package schema
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+type ProtobufValidator struct {
+	message protoreflect.MessageDescriptor
+}
+
+func CompileProtobufSchema(definition []byte, messageName string) (*ProtobufValidator, error) {
+	message, err := FindProtobufMessage(definition, messageName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ProtobufValidator{message: message}, nil
+}
+
+// Payloads are a single message in the protobuf wire format
+func (v *ProtobufValidator) Validate(payload []byte) error {
+	message := dynamicpb.NewMessage(v.message)
+	if err := proto.Unmarshal(payload, message); err != nil {
+		return fmt.Errorf("payload is not a valid %s message: %w", v.message.FullName(), err)
+	}
+
+	return nil
+}
+
+// Finds a message type in a serialized FileDescriptorSet
+func FindProtobufMessage(definition []byte, messageName string) (protoreflect.MessageDescriptor, error) {
+	if messageName == "" {
+		return nil, fmt.Errorf("message name is required for protobuf schemas")
+	}
+
+	set := &descriptorpb.FileDescriptorSet{}
+	if err := proto.Unmarshal(definition, set); err != nil {
+		return nil, fmt.Errorf("invalid protobuf schema: expected a serialized FileDescriptorSet: %w", err)
+	}
+
+	files, err := protodesc.NewFiles(set)
+	if err != nil {
+		return nil, fmt.Errorf("invalid protobuf schema: %w", err)
+	}
+
+	descriptor, err := files.FindDescriptorByName(protoreflect.FullName(messageName))
+	if err != nil {
+		return nil, fmt.Errorf("invalid protobuf schema: message %s not found", messageName)
+	}
+
+	message, ok := descriptor.(protoreflect.MessageDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("invalid protobuf schema: %s is not a message", messageName)
+	}
+
+	return message, nil
+}
+
+// Groups of field kinds sharing a wire encoding, a field may change between kinds of the same group
+var protobufKindGroups = map[protoreflect.Kind]int{
+	protoreflect.Int32Kind:    1,
+	protoreflect.Uint32Kind:   1,
+	protoreflect.Int64Kind:    1,
+	protoreflect.Uint64Kind:   1,
+	protoreflect.BoolKind:     1,
+	protoreflect.EnumKind:     1,
+	protoreflect.Sint32Kind:   2,
+	protoreflect.Sint64Kind:   2,
+	protoreflect.Fixed32Kind:  3,
+	protoreflect.Sfixed32Kind: 3,
+	protoreflect.Fixed64Kind:  4,
+	protoreflect.Sfixed64Kind: 4,
+	protoreflect.StringKind:   5,
+	protoreflect.BytesKind:    5,
+	protoreflect.FloatKind:    6,
+	protoreflect.DoubleKind:   7,
+	protoreflect.MessageKind:  8,
+	protoreflect.GroupKind:    9,
+}
+
+// Checks whether messages written with the writer schema can be decoded with the reader schema. Fields are matched by
+// number, added and removed fields are always compatible while fields kept under the same number must share their wire type
+func CanReadProtobuf(reader *Definition, writer *Definition) error {
+	readerMessage, err := FindProtobufMessage(reader.Schema, reader.MessageName)
+	if err != nil {
+		return err
+	}
+
+	writerMessage, err := FindProtobufMessage(writer.Schema, writer.MessageName)
+	if err != nil {
+		return err
+	}
+
+	return canReadProtobuf(readerMessage, writerMessage, make(map[string]bool))
+}
+
+func canReadProtobuf(reader protoreflect.MessageDescriptor, writer protoreflect.MessageDescriptor, seen map[string]bool) error {
+	key := string(reader.FullName()) + "|" + string(writer.FullName())
+	if seen[key] {
+		return nil
+	}
+	seen[key] = true
+
+	fields := reader.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		readerField := fields.Get(i)
+		writerField := writer.Fields().ByNumber(readerField.Number())
+		if writerField == nil {
+			continue
+		}
+
+		if protobufKindGroups[readerField.Kind()] != protobufKindGroups[writerField.Kind()] {
+			return fmt.Errorf("%s: field %d changed from %s to %s", reader.FullName(), readerField.Number(), writerField.Kind(), readerField.Kind())
+		}
+
+		if readerField.IsList() != writerField.IsList() || readerField.IsMap() != writerField.IsMap() {
+			return fmt.Errorf("%s: field %d changed cardinality", reader.FullName(), readerField.Number())
+		}
+
+		if readerField.Message() != nil && writerField.Message() != nil {
+			if err := canReadProtobuf(readerField.Message(), writerField.Message(), seen); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package schema
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Schema types
+const TYPE_JSON = "json"
+const TYPE_AVRO = "avro"
+const TYPE_PROTOBUF = "protobuf"
+
+var VALID_TYPES = []string{TYPE_JSON, TYPE_AVRO, TYPE_PROTOBUF}
+
+// Compatibility modes of a subject, backward schemas can read messages written with the previous version, forward
+// schemas can be read by the previous version and full schemas both
+const COMPATIBILITY_NONE = "none"
+const COMPATIBILITY_BACKWARD = "backward"
+const COMPATIBILITY_FORWARD = "forward"
+const COMPATIBILITY_FULL = "full"
+
+var VALID_COMPATIBILITY_MODES = []string{COMPATIBILITY_NONE, COMPATIBILITY_BACKWARD, COMPATIBILITY_FORWARD, COMPATIBILITY_FULL}
+
+type Definition struct {
+	Type string
+	// JSON Schema or Avro schema document, or a serialized protobuf FileDescriptorSet
+	Schema []byte
+	// Fully qualified name of the message type payloads are decoded as, only used by protobuf schemas
+	MessageName string
+}
+
+// Validates message payloads against a compiled schema
+type Validator interface {
+	Validate(payload []byte) error
+}
+
+// Compiles a schema definition, fails when the definition is not a valid schema of its type
+func Compile(def *Definition) (Validator, error) {
+	switch def.Type {
+	case TYPE_JSON:
+		return CompileJSONSchema(def.Schema)
+	case TYPE_AVRO:
+		return CompileAvroSchema(def.Schema)
+	case TYPE_PROTOBUF:
+		return CompileProtobufSchema(def.Schema, def.MessageName)
+	default:
+		return nil, fmt.Errorf("schema type must be one of %v", VALID_TYPES)
+	}
+}
+
+// Checks whether next may follow previous as the latest schema of a subject with the given compatibility mode
+func CheckCompatibility(mode string, next *Definition, previous *Definition) error {
+	switch mode {
+	case COMPATIBILITY_NONE:
+		return nil
+	case COMPATIBILITY_BACKWARD:
+		return CanRead(next, previous)
+	case COMPATIBILITY_FORWARD:
+		return CanRead(previous, next)
+	case COMPATIBILITY_FULL:
+		if err := CanRead(next, previous); err != nil {
+			return err
+		}
+		return CanRead(previous, next)
+	default:
+		return fmt.Errorf("compatibility must be one of %v", VALID_COMPATIBILITY_MODES)
+	}
+}
+
+// Checks whether messages written with the writer schema can be read with the reader schema
+func CanRead(reader *Definition, writer *Definition) error {
+	if reader.Type != writer.Type {
+		return fmt.Errorf("schema type changed from %s to %s", writer.Type, reader.Type)
+	}
+
+	switch reader.Type {
+	case TYPE_JSON:
+		return CanReadJSON(reader.Schema, writer.Schema)
+	case TYPE_AVRO:
+		return CanReadAvro(reader.Schema, writer.Schema)
+	case TYPE_PROTOBUF:
+		return CanReadProtobuf(reader, writer)
+	default:
+		return fmt.Errorf("schema type must be one of %v", VALID_TYPES)
+	}
+}
+
+func IsValidCompatibility(mode string) bool {
+	return slices.Contains(VALID_COMPATIBILITY_MODES, mode)
+}
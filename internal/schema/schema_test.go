@@ -0,0 +1,202 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/linkedin/goavro/v2"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+const userJSONSchema = `{
+	"type": "object",
+	"properties": {"id": {"type": "integer"}, "name": {"type": "string"}},
+	"required": ["id"]
+}`
+
+const userAvroSchema = `{
+	"type": "record",
+	"name": "User",
+	"namespace": "example",
+	"fields": [{"name": "id", "type": "int"}, {"name": "name", "type": "string"}]
+}`
+
+func TestJSONSchemaValidator(t *testing.T) {
+	validator, err := Compile(&Definition{Type: TYPE_JSON, Schema: []byte(userJSONSchema)})
+	assert.NoError(t, err)
+
+	assert.NoError(t, validator.Validate([]byte(`{"id": 1, "name": "ada"}`)))
+	assert.Error(t, validator.Validate([]byte(`{"name": "ada"}`)))
+	assert.Error(t, validator.Validate([]byte(`{"id": "1"}`)))
+	assert.Error(t, validator.Validate([]byte(`id=1`)))
+
+	_, err = Compile(&Definition{Type: TYPE_JSON, Schema: []byte(`{"type": 1}`)})
+	assert.Error(t, err)
+}
+
+func TestAvroValidator(t *testing.T) {
+	validator, err := Compile(&Definition{Type: TYPE_AVRO, Schema: []byte(userAvroSchema)})
+	assert.NoError(t, err)
+
+	codec, err := goavro.NewCodec(userAvroSchema)
+	assert.NoError(t, err)
+	payload, err := codec.BinaryFromNative(nil, map[string]interface{}{"id": 1, "name": "ada"})
+	assert.NoError(t, err)
+
+	assert.NoError(t, validator.Validate(payload))
+	assert.Error(t, validator.Validate(payload[:2]))
+	assert.Error(t, validator.Validate(append(payload, 0)))
+}
+
+func TestProtobufValidator(t *testing.T) {
+	definition := newProtobufDefinition(t, descriptorpb.FieldDescriptorProto_TYPE_INT64)
+
+	validator, err := Compile(definition)
+	assert.NoError(t, err)
+
+	payload, err := proto.Marshal(wrapperspb.Int64(42))
+	assert.NoError(t, err)
+	assert.NoError(t, validator.Validate(payload))
+	assert.Error(t, validator.Validate([]byte{0xff, 0xff}))
+
+	_, err = Compile(&Definition{Type: TYPE_PROTOBUF, Schema: definition.Schema, MessageName: "example.Missing"})
+	assert.Error(t, err)
+}
+
+func TestCheckCompatibility(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Mode        string
+		Next        *Definition
+		Previous    *Definition
+		ExpectError bool
+	}{
+		{
+			Name:     "JSON optional property added",
+			Mode:     COMPATIBILITY_FULL,
+			Previous: &Definition{Type: TYPE_JSON, Schema: []byte(userJSONSchema)},
+			Next: &Definition{Type: TYPE_JSON, Schema: []byte(`{
+				"type": "object",
+				"properties": {"id": {"type": "integer"}, "name": {"type": "string"}, "email": {"type": "string"}},
+				"required": ["id"]
+			}`)},
+		},
+		{
+			Name:     "JSON required property added",
+			Mode:     COMPATIBILITY_BACKWARD,
+			Previous: &Definition{Type: TYPE_JSON, Schema: []byte(userJSONSchema)},
+			Next: &Definition{Type: TYPE_JSON, Schema: []byte(`{
+				"type": "object",
+				"properties": {"id": {"type": "integer"}, "name": {"type": "string"}},
+				"required": ["id", "name"]
+			}`)},
+			ExpectError: true,
+		},
+		{
+			Name:     "JSON property type narrowed is forward compatible",
+			Mode:     COMPATIBILITY_FORWARD,
+			Previous: &Definition{Type: TYPE_JSON, Schema: []byte(`{"properties": {"id": {"type": "number"}}}`)},
+			Next:     &Definition{Type: TYPE_JSON, Schema: []byte(`{"properties": {"id": {"type": "integer"}}}`)},
+		},
+		{
+			Name:        "JSON property type narrowed is not backward compatible",
+			Mode:        COMPATIBILITY_BACKWARD,
+			Previous:    &Definition{Type: TYPE_JSON, Schema: []byte(`{"properties": {"id": {"type": "number"}}}`)},
+			Next:        &Definition{Type: TYPE_JSON, Schema: []byte(`{"properties": {"id": {"type": "integer"}}}`)},
+			ExpectError: true,
+		},
+		{
+			Name:     "Avro field added with a default",
+			Mode:     COMPATIBILITY_FULL,
+			Previous: &Definition{Type: TYPE_AVRO, Schema: []byte(userAvroSchema)},
+			Next: &Definition{Type: TYPE_AVRO, Schema: []byte(`{
+				"type": "record", "name": "User", "namespace": "example",
+				"fields": [{"name": "id", "type": "int"}, {"name": "name", "type": "string"}, {"name": "age", "type": ["null", "int"], "default": null}]
+			}`)},
+		},
+		{
+			Name:     "Avro field added without a default",
+			Mode:     COMPATIBILITY_BACKWARD,
+			Previous: &Definition{Type: TYPE_AVRO, Schema: []byte(userAvroSchema)},
+			Next: &Definition{Type: TYPE_AVRO, Schema: []byte(`{
+				"type": "record", "name": "User", "namespace": "example",
+				"fields": [{"name": "id", "type": "int"}, {"name": "name", "type": "string"}, {"name": "age", "type": "int"}]
+			}`)},
+			ExpectError: true,
+		},
+		{
+			Name:     "Avro int promoted to long is only backward compatible",
+			Mode:     COMPATIBILITY_FULL,
+			Previous: &Definition{Type: TYPE_AVRO, Schema: []byte(userAvroSchema)},
+			Next: &Definition{Type: TYPE_AVRO, Schema: []byte(`{
+				"type": "record", "name": "User", "namespace": "example",
+				"fields": [{"name": "id", "type": "long"}, {"name": "name", "type": "string"}]
+			}`)},
+			ExpectError: true,
+		},
+		{
+			Name:     "Protobuf field kind kept within its wire type",
+			Mode:     COMPATIBILITY_FULL,
+			Previous: newProtobufDefinition(t, descriptorpb.FieldDescriptorProto_TYPE_INT64),
+			Next:     newProtobufDefinition(t, descriptorpb.FieldDescriptorProto_TYPE_UINT64),
+		},
+		{
+			Name:        "Protobuf field changed wire type",
+			Mode:        COMPATIBILITY_BACKWARD,
+			Previous:    newProtobufDefinition(t, descriptorpb.FieldDescriptorProto_TYPE_INT64),
+			Next:        newProtobufDefinition(t, descriptorpb.FieldDescriptorProto_TYPE_STRING),
+			ExpectError: true,
+		},
+		{
+			Name:     "Anything goes without compatibility",
+			Mode:     COMPATIBILITY_NONE,
+			Previous: &Definition{Type: TYPE_AVRO, Schema: []byte(userAvroSchema)},
+			Next:     &Definition{Type: TYPE_JSON, Schema: []byte(userJSONSchema)},
+		},
+		{
+			Name:        "Schema type changed",
+			Mode:        COMPATIBILITY_BACKWARD,
+			Previous:    &Definition{Type: TYPE_AVRO, Schema: []byte(userAvroSchema)},
+			Next:        &Definition{Type: TYPE_JSON, Schema: []byte(userJSONSchema)},
+			ExpectError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			err := CheckCompatibility(test.Mode, test.Next, test.Previous)
+			if test.ExpectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// Builds a schema of a message with a single field "value" numbered 1, wire compatible with the wrapper types
+func newProtobufDefinition(t *testing.T, fieldType descriptorpb.FieldDescriptorProto_Type) *Definition {
+	set := &descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{{
+			Name:    proto.String("example.proto"),
+			Package: proto.String("example"),
+			Syntax:  proto.String("proto3"),
+			MessageType: []*descriptorpb.DescriptorProto{{
+				Name: proto.String("Value"),
+				Field: []*descriptorpb.FieldDescriptorProto{{
+					Name:   proto.String("value"),
+					Number: proto.Int32(1),
+					Label:  descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+					Type:   fieldType.Enum(),
+				}},
+			}},
+		}},
+	}
+
+	data, err := proto.Marshal(set)
+	assert.NoError(t, err)
+
+	return &Definition{Type: TYPE_PROTOBUF, Schema: data, MessageName: "example.Value"}
+}
@@ -0,0 +1,9 @@
+package storage
+
+// Size of the parts a large object is split into when it is uploaded with a multipart upload, S3 requires at least 5 MiB
+const S3_MULTIPART_PART_SIZE int64 = 8 * 1024 * 1024
+
+// Names of the objects a block is made of
+const BLOCK_PARQUET_FILE = "data.parquet"
+const BLOCK_BLOOM_FILE = "filter.bloom"
+const BLOCK_META_FILE = "meta.json"
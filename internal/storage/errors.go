@@ -0,0 +1,19 @@
+package storage
+
+import "fmt"
+
+type StorageBlockNotFoundError struct {
+	StreamName string
+	BlockID    string
+}
+
+func BlockNotFoundError(streamName string, blockId string) *StorageBlockNotFoundError {
+	return &StorageBlockNotFoundError{
+		StreamName: streamName,
+		BlockID:    blockId,
+	}
+}
+
+func (e *StorageBlockNotFoundError) Error() string {
+	return fmt.Sprintf("Block: %s not found in stream: %s", e.BlockID, e.StreamName)
+}
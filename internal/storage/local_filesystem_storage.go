@@ -2,6 +2,7 @@ package storage
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"fmt"
 	"io"
@@ -52,12 +53,21 @@ func (s *LocalFilesystemStorage) ArchiveBlock(ctx context.Context, block *block.
 	}
 
 	// Define paths for block components
-	parquetPath := filepath.Join(blockDir, "data.parquet")
-	bloomPath := filepath.Join(blockDir, "filter.bloom")
-	metaPath := filepath.Join(blockDir, "meta.json")
+	parquetPath := filepath.Join(blockDir, BLOCK_PARQUET_FILE)
+	bloomPath := filepath.Join(blockDir, BLOCK_BLOOM_FILE)
+	metaPath := filepath.Join(blockDir, BLOCK_META_FILE)
+
+	// A block with metadata was completely archived by a previous attempt, so it is left untouched
+	if _, err := os.Stat(metaPath); err == nil {
+		return nil
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("failed to check block metadata: %v", err)
+	}
 
 	// Use a channel to collect errors from goroutines
-	errChan := make(chan error, 3)
+	errChan := make(chan error, 2)
+	// Files written by this attempt, the only files removed on error
+	written := make(chan string, 2)
 
 	// Context with cancellation for cleanup in case of errors
 	ctx, cancel := context.WithCancel(ctx)
@@ -70,6 +80,7 @@ func (s *LocalFilesystemStorage) ArchiveBlock(ctx context.Context, block *block.
 			cancel()
 			return
 		}
+		written <- parquetPath
 		errChan <- nil
 	}()
 
@@ -79,25 +90,32 @@ func (s *LocalFilesystemStorage) ArchiveBlock(ctx context.Context, block *block.
 			cancel()
 			return
 		}
+		written <- bloomPath
 		errChan <- nil
 	}()
 
-	go func() {
-		if err := os.WriteFile(metaPath, block.Meta, 0644); err != nil {
-			errChan <- fmt.Errorf("failed to write metadata: %v", err)
-			cancel()
-			return
+	var writeErr error
+	for i := 0; i < 2; i++ {
+		if err := <-errChan; err != nil && writeErr == nil {
+			writeErr = err
 		}
-		errChan <- nil
-	}()
+	}
+	close(written)
+
+	// The metadata is written last so a block is only listed once its data is complete
+	if writeErr == nil {
+		if err := WriteFile(ctx, metaPath, io.NopCloser(bytes.NewReader(block.Meta))); err != nil {
+			writeErr = fmt.Errorf("failed to write metadata: %v", err)
+		}
+	}
 
-	// Collect results from all goroutines
-	for i := 0; i < 3; i++ {
-		if err := <-errChan; err != nil {
-			// Clean up the block directory on error
-			os.RemoveAll(blockDir)
-			return err
+	if writeErr != nil {
+		// Clean up the files written by this attempt on error, the block directory is only removed once empty
+		for path := range written {
+			os.Remove(path)
 		}
+		os.Remove(blockDir)
+		return writeErr
 	}
 
 	return nil
@@ -158,3 +176,93 @@ func WriteFile(ctx context.Context, path string, reader io.ReadCloser) error {
 		return err
 	}
 }
+
+func (s *LocalFilesystemStorage) ListBlocks(ctx context.Context, streamName string, filter *BlockFilter) ([]*block.BlockMetadata, error) {
+	blocks := make([]*block.BlockMetadata, 0)
+
+	entries, err := os.ReadDir(filepath.Join(s.Directory, streamName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return blocks, nil
+		}
+		return nil, fmt.Errorf("failed to read stream directory: %v", err)
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(s.Directory, streamName, entry.Name(), BLOCK_META_FILE))
+		if err != nil {
+			// Blocks without metadata were never completely written
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to read block metadata: %v", err)
+		}
+
+		meta, err := ParseBlockMetadata(data)
+		if err != nil {
+			return nil, err
+		}
+
+		if filter.Matches(meta) {
+			blocks = append(blocks, meta)
+		}
+	}
+
+	SortBlocks(blocks)
+
+	return blocks, nil
+}
+
+func (s *LocalFilesystemStorage) GetBlock(ctx context.Context, streamName string, blockId string) (*block.Block, error) {
+	blockDir := filepath.Join(s.Directory, streamName, blockId)
+
+	meta, err := os.ReadFile(filepath.Join(blockDir, BLOCK_META_FILE))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, BlockNotFoundError(streamName, blockId)
+		}
+		return nil, fmt.Errorf("failed to read block metadata: %v", err)
+	}
+
+	parquet, err := os.Open(filepath.Join(blockDir, BLOCK_PARQUET_FILE))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open parquet file: %v", err)
+	}
+
+	bloom, err := os.Open(filepath.Join(blockDir, BLOCK_BLOOM_FILE))
+	if err != nil {
+		parquet.Close()
+		return nil, fmt.Errorf("failed to open bloom filter: %v", err)
+	}
+
+	return &block.Block{
+		StreamName: streamName,
+		BlockID:    blockId,
+		Parquet:    parquet,
+		Bloom:      bloom,
+		Meta:       meta,
+	}, nil
+}
+
+func (s *LocalFilesystemStorage) DeleteBlock(ctx context.Context, streamName string, blockId string) error {
+	blockDir := filepath.Join(s.Directory, streamName, blockId)
+
+	if _, err := os.Stat(blockDir); os.IsNotExist(err) {
+		return BlockNotFoundError(streamName, blockId)
+	}
+
+	// Remove the metadata first so a partially deleted block is no longer listed
+	if err := os.Remove(filepath.Join(blockDir, BLOCK_META_FILE)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete block metadata: %v", err)
+	}
+
+	if err := os.RemoveAll(blockDir); err != nil {
+		return fmt.Errorf("failed to delete block directory: %v", err)
+	}
+
+	return nil
+}
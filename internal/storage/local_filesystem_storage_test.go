@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Fails every read, like a block whose data could not be serialized
+type failingReader struct{}
+
+func (failingReader) Read(p []byte) (int, error) {
+	return 0, errors.New("read failed")
+}
+
+func TestLocalFilesystemStorage_ArchiveBlock(t *testing.T) {
+	t.Run("Write the block files and list the block", func(t *testing.T) {
+		dir := t.TempDir()
+		storage, err := NewLocalFilesystemDriver(dir)
+		assert.NoError(t, err)
+
+		err = storage.ArchiveBlock(context.Background(), newTestBlock("block-1", []byte("0123456789"), []byte("bf"), []byte(`{"block_id":"block-1"}`)))
+		assert.NoError(t, err)
+
+		data, err := os.ReadFile(filepath.Join(dir, "test-stream", "block-1", BLOCK_META_FILE))
+		assert.NoError(t, err)
+		assert.Equal(t, []byte(`{"block_id":"block-1"}`), data)
+
+		blocks, err := storage.ListBlocks(context.Background(), "test-stream", nil)
+		assert.NoError(t, err)
+		assert.Len(t, blocks, 1)
+	})
+
+	t.Run("Do not write metadata when a block file fails", func(t *testing.T) {
+		dir := t.TempDir()
+		storage, err := NewLocalFilesystemDriver(dir)
+		assert.NoError(t, err)
+
+		value := newTestBlock("block-1", []byte("0123456789"), nil, []byte(`{"block_id":"block-1"}`))
+		value.Bloom = io.NopCloser(failingReader{})
+
+		err = storage.ArchiveBlock(context.Background(), value)
+		assert.Error(t, err)
+
+		_, err = os.Stat(filepath.Join(dir, "test-stream", "block-1", BLOCK_META_FILE))
+		assert.True(t, os.IsNotExist(err))
+
+		blocks, err := storage.ListBlocks(context.Background(), "test-stream", nil)
+		assert.NoError(t, err)
+		assert.Len(t, blocks, 0)
+	})
+
+	t.Run("Leave a block archived by a previous attempt untouched", func(t *testing.T) {
+		dir := t.TempDir()
+		storage, err := NewLocalFilesystemDriver(dir)
+		assert.NoError(t, err)
+
+		err = storage.ArchiveBlock(context.Background(), newTestBlock("block-1", []byte("0123456789"), []byte("bf"), []byte(`{"block_id":"block-1"}`)))
+		assert.NoError(t, err)
+
+		value := newTestBlock("block-1", nil, []byte("bf"), []byte(`{"block_id":"block-1"}`))
+		value.Parquet = io.NopCloser(failingReader{})
+
+		err = storage.ArchiveBlock(context.Background(), value)
+		assert.NoError(t, err)
+
+		for _, file := range []string{BLOCK_PARQUET_FILE, BLOCK_BLOOM_FILE, BLOCK_META_FILE} {
+			_, err := os.Stat(filepath.Join(dir, "test-stream", "block-1", file))
+			assert.NoError(t, err, file)
+		}
+	})
+}
+
+func TestLocalFilesystemStorage_ReadPath(t *testing.T) {
+	storage, err := NewLocalFilesystemDriver(t.TempDir())
+	assert.NoError(t, err)
+
+	testStorageReadPath(t, storage)
+}
@@ -1,32 +1,392 @@
 package storage
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	S3 "github.com/aws/aws-sdk-go/service/s3"
 	"github.com/streamweaverio/broker/internal/block"
 	"github.com/streamweaverio/broker/internal/logging"
 	"github.com/streamweaverio/broker/internal/s3"
+	"go.uber.org/zap"
 )
 
 type S3Storage struct {
 	Client s3.Client
 	Bucket string
-	Logger logging.LoggerContract
+	// Size of the parts large objects are uploaded in
+	PartSize int64
+	Logger   logging.LoggerContract
 }
 
 type S3StorageOptions struct {
 	Client     s3.Client
 	BucketName string
+	// Size of the parts large objects are uploaded in, defaults to S3_MULTIPART_PART_SIZE
+	PartSize int64
 }
 
 func NewS3Storage(opts *S3StorageOptions, logger logging.LoggerContract) (Storage, error) {
+	partSize := opts.PartSize
+	if partSize <= 0 {
+		partSize = S3_MULTIPART_PART_SIZE
+	}
+
 	return &S3Storage{
-		Client: opts.Client,
-		Bucket: opts.BucketName,
-		Logger: logger,
+		Client:   opts.Client,
+		Bucket:   opts.BucketName,
+		PartSize: partSize,
+		Logger:   logger,
 	}, nil
 }
 
 func (s *S3Storage) ArchiveBlock(ctx context.Context, block *block.Block) error {
+	if block.Parquet == nil || block.Bloom == nil {
+		return fmt.Errorf("nil reader provided")
+	}
+	defer block.Parquet.Close()
+	defer block.Bloom.Close()
+
+	blockPrefix := path.Join(block.StreamName, block.BlockID)
+	parquetKey := path.Join(blockPrefix, BLOCK_PARQUET_FILE)
+	bloomKey := path.Join(blockPrefix, BLOCK_BLOOM_FILE)
+	metaKey := path.Join(blockPrefix, BLOCK_META_FILE)
+
+	// A block with metadata was completely archived by a previous attempt, so it is left untouched
+	if _, err := s.GetObjectBytes(ctx, metaKey); err == nil {
+		s.Logger.Debug("Block already archived to S3", zap.String("bucket", s.Bucket), zap.String("prefix", blockPrefix))
+		return nil
+	} else if !IsS3NotFoundError(err) {
+		return fmt.Errorf("failed to check block metadata: %v", err)
+	}
+
+	// Sizes recorded in the metadata keep the upload buffers of small objects small
+	var parquetSize, bloomSize int64
+	if meta, err := ParseBlockMetadata(block.Meta); err == nil {
+		parquetSize = int64(meta.ParquetFileSize)
+		bloomSize = int64(meta.BloomFilterSize)
+	}
+
+	// Use a channel to collect errors from goroutines
+	errChan := make(chan error, 2)
+	// Keys uploaded by this attempt, the only objects removed on error
+	uploaded := make(chan string, 2)
+
+	// Context with cancellation for cleanup in case of errors
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Upload block data concurrently
+	go func() {
+		if err := s.UploadObject(ctx, parquetKey, block.Parquet, parquetSize); err != nil {
+			errChan <- fmt.Errorf("failed to upload parquet file: %v", err)
+			cancel()
+			return
+		}
+		uploaded <- parquetKey
+		errChan <- nil
+	}()
+
+	go func() {
+		if err := s.UploadObject(ctx, bloomKey, block.Bloom, bloomSize); err != nil {
+			errChan <- fmt.Errorf("failed to upload bloom filter: %v", err)
+			cancel()
+			return
+		}
+		uploaded <- bloomKey
+		errChan <- nil
+	}()
+
+	var uploadErr error
+	for i := 0; i < 2; i++ {
+		if err := <-errChan; err != nil && uploadErr == nil {
+			uploadErr = err
+		}
+	}
+	close(uploaded)
+
+	// The metadata is uploaded last so a block is only visible once its data is complete
+	if uploadErr == nil {
+		if err := s.PutObject(ctx, metaKey, block.Meta); err != nil {
+			uploadErr = fmt.Errorf("failed to upload metadata: %v", err)
+		}
+	}
+
+	if uploadErr != nil {
+		// Clean up the objects uploaded by this attempt on error, the metadata is never written when anything failed
+		keys := make([]string, 0, 2)
+		for key := range uploaded {
+			keys = append(keys, key)
+		}
+		s.DeleteObjects(keys...)
+		return uploadErr
+	}
+
+	s.Logger.Debug("Archived block to S3", zap.String("bucket", s.Bucket), zap.String("prefix", blockPrefix))
+	return nil
+}
+
+// Uploads an object, switching to a multipart upload when it does not fit in a single part. The expected size of the
+// object bounds the buffer it is read into, zero when unknown
+func (s *S3Storage) UploadObject(ctx context.Context, key string, reader io.Reader, size int64) error {
+	bufSize := s.PartSize
+	// One extra byte tells an object of the expected size apart from a larger one
+	if size > 0 && size < bufSize {
+		bufSize = size + 1
+	}
+
+	buf := make([]byte, bufSize)
+	n, err := io.ReadFull(reader, buf)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return s.PutObject(ctx, key, buf[:n])
+	}
+	if err != nil {
+		return err
+	}
+
+	// The object is larger than expected, the first part is read into a buffer of the full part size
+	if int64(n) < s.PartSize {
+		buf = append(buf, make([]byte, s.PartSize-int64(n))...)
+		m, err := io.ReadFull(reader, buf[n:])
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return s.PutObject(ctx, key, buf[:n+m])
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return s.MultipartUpload(ctx, key, buf, reader)
+}
+
+func (s *S3Storage) PutObject(ctx context.Context, key string, data []byte) error {
+	_, err := s.Client.PutObjectWithContext(ctx, &S3.PutObjectInput{
+		Bucket: aws.String(s.Bucket),
+		Key:    aws.String(key),
+		Body:   bytes.NewReader(data),
+	})
+
+	return err
+}
+
+// Uploads an object in parts, the first part has already been read into buf
+func (s *S3Storage) MultipartUpload(ctx context.Context, key string, buf []byte, reader io.Reader) error {
+	upload, err := s.Client.CreateMultipartUploadWithContext(ctx, &S3.CreateMultipartUploadInput{
+		Bucket: aws.String(s.Bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create multipart upload: %w", err)
+	}
+
+	parts := make([]*S3.CompletedPart, 0)
+	part := buf
+
+	for partNumber := int64(1); len(part) > 0; partNumber++ {
+		output, err := s.Client.UploadPartWithContext(ctx, &S3.UploadPartInput{
+			Bucket:     aws.String(s.Bucket),
+			Key:        aws.String(key),
+			UploadId:   upload.UploadId,
+			PartNumber: aws.Int64(partNumber),
+			Body:       bytes.NewReader(part),
+		})
+		if err != nil {
+			s.AbortMultipartUpload(key, upload.UploadId)
+			return fmt.Errorf("failed to upload part %d: %w", partNumber, err)
+		}
+
+		parts = append(parts, &S3.CompletedPart{
+			ETag:       output.ETag,
+			PartNumber: aws.Int64(partNumber),
+		})
+
+		// The part has been uploaded so its buffer can be reused for the next one
+		n, err := io.ReadFull(reader, buf)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			s.AbortMultipartUpload(key, upload.UploadId)
+			return err
+		}
+		part = buf[:n]
+	}
+
+	_, err = s.Client.CompleteMultipartUploadWithContext(ctx, &S3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(s.Bucket),
+		Key:             aws.String(key),
+		UploadId:        upload.UploadId,
+		MultipartUpload: &S3.CompletedMultipartUpload{Parts: parts},
+	})
+	if err != nil {
+		s.AbortMultipartUpload(key, upload.UploadId)
+		return fmt.Errorf("failed to complete multipart upload: %w", err)
+	}
+
+	s.Logger.Debug("Completed multipart upload", zap.String("key", key), zap.Int("parts", len(parts)))
+	return nil
+}
+
+// Aborts a multipart upload so its parts are not stored, a detached context is used so the abort survives cancellation
+func (s *S3Storage) AbortMultipartUpload(key string, uploadId *string) {
+	_, err := s.Client.AbortMultipartUploadWithContext(context.Background(), &S3.AbortMultipartUploadInput{
+		Bucket:   aws.String(s.Bucket),
+		Key:      aws.String(key),
+		UploadId: uploadId,
+	})
+	if err != nil {
+		s.Logger.Error("Failed to abort multipart upload", zap.String("key", key), zap.Error(err))
+	}
+}
+
+// Deletes objects on a best effort basis, a detached context is used so the cleanup survives cancellation
+func (s *S3Storage) DeleteObjects(keys ...string) {
+	for _, key := range keys {
+		_, err := s.Client.DeleteObjectWithContext(context.Background(), &S3.DeleteObjectInput{
+			Bucket: aws.String(s.Bucket),
+			Key:    aws.String(key),
+		})
+		if err != nil {
+			s.Logger.Error("Failed to delete object", zap.String("key", key), zap.Error(err))
+		}
+	}
+}
+
+func (s *S3Storage) ListBlocks(ctx context.Context, streamName string, filter *BlockFilter) ([]*block.BlockMetadata, error) {
+	blocks := make([]*block.BlockMetadata, 0)
+	prefix := streamName + "/"
+	var continuationToken *string
+
+	for {
+		output, err := s.Client.ListObjectsV2WithContext(ctx, &S3.ListObjectsV2Input{
+			Bucket:            aws.String(s.Bucket),
+			Prefix:            aws.String(prefix),
+			ContinuationToken: continuationToken,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list blocks: %w", err)
+		}
+
+		for _, object := range output.Contents {
+			key := aws.StringValue(object.Key)
+			// Only keys shaped like <stream>/<block_id>/meta.json belong to a complete block
+			if path.Base(key) != BLOCK_META_FILE || path.Dir(path.Dir(key)) != streamName {
+				continue
+			}
+
+			data, err := s.GetObjectBytes(ctx, key)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read block metadata: %w", err)
+			}
+
+			meta, err := ParseBlockMetadata(data)
+			if err != nil {
+				return nil, err
+			}
+
+			if filter.Matches(meta) {
+				blocks = append(blocks, meta)
+			}
+		}
+
+		if !aws.BoolValue(output.IsTruncated) {
+			break
+		}
+		continuationToken = output.NextContinuationToken
+	}
+
+	SortBlocks(blocks)
+
+	return blocks, nil
+}
+
+func (s *S3Storage) GetBlock(ctx context.Context, streamName string, blockId string) (*block.Block, error) {
+	blockPrefix := path.Join(streamName, blockId)
+
+	meta, err := s.GetObjectBytes(ctx, path.Join(blockPrefix, BLOCK_META_FILE))
+	if err != nil {
+		if IsS3NotFoundError(err) {
+			return nil, BlockNotFoundError(streamName, blockId)
+		}
+		return nil, fmt.Errorf("failed to read block metadata: %w", err)
+	}
+
+	parquet, err := s.Client.GetObjectWithContext(ctx, &S3.GetObjectInput{
+		Bucket: aws.String(s.Bucket),
+		Key:    aws.String(path.Join(blockPrefix, BLOCK_PARQUET_FILE)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open parquet file: %w", err)
+	}
+
+	bloom, err := s.Client.GetObjectWithContext(ctx, &S3.GetObjectInput{
+		Bucket: aws.String(s.Bucket),
+		Key:    aws.String(path.Join(blockPrefix, BLOCK_BLOOM_FILE)),
+	})
+	if err != nil {
+		parquet.Body.Close()
+		return nil, fmt.Errorf("failed to open bloom filter: %w", err)
+	}
+
+	return &block.Block{
+		StreamName: streamName,
+		BlockID:    blockId,
+		Parquet:    parquet.Body,
+		Bloom:      bloom.Body,
+		Meta:       meta,
+	}, nil
+}
+
+func (s *S3Storage) DeleteBlock(ctx context.Context, streamName string, blockId string) error {
+	blockPrefix := path.Join(streamName, blockId)
+	metaKey := path.Join(blockPrefix, BLOCK_META_FILE)
+
+	// S3 deletes succeed for missing keys, so check the block exists first
+	_, err := s.GetObjectBytes(ctx, metaKey)
+	if err != nil {
+		if IsS3NotFoundError(err) {
+			return BlockNotFoundError(streamName, blockId)
+		}
+		return fmt.Errorf("failed to read block metadata: %w", err)
+	}
+
+	// Remove the metadata first so a partially deleted block is no longer listed
+	for _, key := range []string{metaKey, path.Join(blockPrefix, BLOCK_PARQUET_FILE), path.Join(blockPrefix, BLOCK_BLOOM_FILE)} {
+		_, err := s.Client.DeleteObjectWithContext(ctx, &S3.DeleteObjectInput{
+			Bucket: aws.String(s.Bucket),
+			Key:    aws.String(key),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to delete %s: %w", key, err)
+		}
+	}
+
 	return nil
 }
+
+// Reads an object into memory
+func (s *S3Storage) GetObjectBytes(ctx context.Context, key string) ([]byte, error) {
+	output, err := s.Client.GetObjectWithContext(ctx, &S3.GetObjectInput{
+		Bucket: aws.String(s.Bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer output.Body.Close()
+
+	return io.ReadAll(output.Body)
+}
+
+// Checks if an error returned by S3 means the object does not exist
+func IsS3NotFoundError(err error) bool {
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) {
+		return awsErr.Code() == S3.ErrCodeNoSuchKey || awsErr.Code() == "NotFound"
+	}
+
+	return false
+}
@@ -0,0 +1,139 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	S3 "github.com/aws/aws-sdk-go/service/s3"
+	"github.com/streamweaverio/broker/internal/block"
+	"github.com/streamweaverio/broker/internal/s3"
+	"github.com/streamweaverio/broker/internal/testutils"
+	"github.com/stretchr/testify/assert"
+)
+
+// Fails every part upload after the first one
+type failingPartClient struct {
+	*s3.FakeClient
+	uploaded int
+}
+
+func (c *failingPartClient) UploadPartWithContext(ctx aws.Context, input *S3.UploadPartInput, opts ...request.Option) (*S3.UploadPartOutput, error) {
+	c.uploaded++
+	if c.uploaded > 1 {
+		return nil, errors.New("connection reset by peer")
+	}
+	return c.FakeClient.UploadPartWithContext(ctx, input, opts...)
+}
+
+func newTestBlock(blockId string, parquet []byte, bloom []byte, meta []byte) *block.Block {
+	return &block.Block{
+		StreamName: "test-stream",
+		BlockID:    blockId,
+		Parquet:    io.NopCloser(bytes.NewReader(parquet)),
+		Bloom:      io.NopCloser(bytes.NewReader(bloom)),
+		Meta:       meta,
+	}
+}
+
+func TestS3Storage_ArchiveBlock(t *testing.T) {
+	t.Run("Upload block objects under the stream and block prefix", func(t *testing.T) {
+		client := s3.NewFakeClient()
+		storage, _ := NewS3Storage(&S3StorageOptions{Client: client, BucketName: "archive", PartSize: 4}, testutils.NewMockLogger())
+
+		parquet := []byte("0123456789")
+		err := storage.ArchiveBlock(context.Background(), newTestBlock("block-1", parquet, []byte("bf"), []byte(`{"block_id":"block-1"}`)))
+		assert.NoError(t, err)
+
+		data, ok := client.Object("archive", "test-stream/block-1/data.parquet")
+		assert.True(t, ok)
+		assert.Equal(t, parquet, data)
+
+		data, ok = client.Object("archive", "test-stream/block-1/filter.bloom")
+		assert.True(t, ok)
+		assert.Equal(t, []byte("bf"), data)
+
+		data, ok = client.Object("archive", "test-stream/block-1/meta.json")
+		assert.True(t, ok)
+		assert.Equal(t, []byte(`{"block_id":"block-1"}`), data)
+
+		// Only the parquet file is larger than a single part
+		assert.Equal(t, 1, client.CompletedMultipartUploads)
+		assert.Equal(t, 0, client.PendingMultipartUploads())
+	})
+
+	t.Run("Abort the multipart upload and remove the block when a part fails", func(t *testing.T) {
+		client := &failingPartClient{FakeClient: s3.NewFakeClient()}
+		storage, _ := NewS3Storage(&S3StorageOptions{Client: client, BucketName: "archive", PartSize: 4}, testutils.NewMockLogger())
+
+		err := storage.ArchiveBlock(context.Background(), newTestBlock("block-1", []byte("0123456789"), []byte("bf"), []byte("{}")))
+		assert.Error(t, err)
+
+		assert.Equal(t, 1, client.AbortedMultipartUploads)
+		assert.Equal(t, 0, client.PendingMultipartUploads())
+
+		for _, key := range []string{"data.parquet", "filter.bloom", "meta.json"} {
+			_, ok := client.Object("archive", "test-stream/block-1/"+key)
+			assert.False(t, ok, key)
+		}
+	})
+
+	t.Run("Leave a block archived by a previous attempt untouched", func(t *testing.T) {
+		client := &failingPartClient{FakeClient: s3.NewFakeClient()}
+		storage, _ := NewS3Storage(&S3StorageOptions{Client: client, BucketName: "archive", PartSize: 4}, testutils.NewMockLogger())
+
+		err := storage.ArchiveBlock(context.Background(), newTestBlock("block-1", []byte("012"), []byte("bf"), []byte("{}")))
+		assert.NoError(t, err)
+
+		// Uploading the block again would fail on its second part
+		err = storage.ArchiveBlock(context.Background(), newTestBlock("block-1", []byte("0123456789"), []byte("bf"), []byte("{}")))
+		assert.NoError(t, err)
+
+		data, ok := client.Object("archive", "test-stream/block-1/data.parquet")
+		assert.True(t, ok)
+		assert.Equal(t, []byte("012"), data)
+
+		for _, key := range []string{"filter.bloom", "meta.json"} {
+			_, ok := client.Object("archive", "test-stream/block-1/"+key)
+			assert.True(t, ok, key)
+		}
+	})
+}
+
+func TestS3Storage_UploadObject(t *testing.T) {
+	testCases := []struct {
+		name      string
+		data      []byte
+		size      int64
+		multipart int
+	}{
+		{name: "Put an object of the expected size", data: []byte("bf"), size: 2, multipart: 0},
+		{name: "Put an object larger than expected that fits in a part", data: []byte("012"), size: 1, multipart: 0},
+		{name: "Upload an object larger than expected in parts", data: []byte("0123456789"), size: 1, multipart: 1},
+		{name: "Upload an object of unknown size in parts", data: []byte("0123456789"), size: 0, multipart: 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := s3.NewFakeClient()
+			storage, _ := NewS3Storage(&S3StorageOptions{Client: client, BucketName: "archive", PartSize: 4}, testutils.NewMockLogger())
+
+			err := storage.(*S3Storage).UploadObject(context.Background(), "object", bytes.NewReader(tc.data), tc.size)
+			assert.NoError(t, err)
+
+			data, ok := client.Object("archive", "object")
+			assert.True(t, ok)
+			assert.Equal(t, tc.data, data)
+			assert.Equal(t, tc.multipart, client.CompletedMultipartUploads)
+		})
+	}
+}
+
+func TestS3Storage_ReadPath(t *testing.T) {
+	storage, _ := NewS3Storage(&S3StorageOptions{Client: s3.NewFakeClient(), BucketName: "archive"}, testutils.NewMockLogger())
+	testStorageReadPath(t, storage)
+}
@@ -2,10 +2,63 @@ package storage
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
 
 	"github.com/streamweaverio/broker/internal/block"
+	"github.com/streamweaverio/broker/pkg/utils"
 )
 
 type Storage interface {
+	// Archive a block
 	ArchiveBlock(ctx context.Context, block *block.Block) error
+	// List the metadata of a stream's blocks ordered by their first message, a nil filter lists every block
+	ListBlocks(ctx context.Context, streamName string, filter *BlockFilter) ([]*block.BlockMetadata, error)
+	// Open the parquet, bloom filter and metadata of a block, the caller must close the readers
+	GetBlock(ctx context.Context, streamName string, blockId string) (*block.Block, error)
+	// Delete a block
+	DeleteBlock(ctx context.Context, streamName string, blockId string) error
+}
+
+// Selects blocks by the time range of their messages
+type BlockFilter struct {
+	// Only include blocks with messages at or after this timestamp in milliseconds, zero leaves the range open
+	StartTimestamp int64
+	// Only include blocks with messages at or before this timestamp in milliseconds, zero leaves the range open
+	EndTimestamp int64
+}
+
+// Checks if a block has messages within the filter's time range
+func (f *BlockFilter) Matches(meta *block.BlockMetadata) bool {
+	if f == nil {
+		return true
+	}
+
+	if f.StartTimestamp > 0 && meta.BlockEndTimestamp < f.StartTimestamp {
+		return false
+	}
+
+	if f.EndTimestamp > 0 && meta.BlockStartTimestamp > f.EndTimestamp {
+		return false
+	}
+
+	return true
+}
+
+// Parses the content of a block's meta.json
+func ParseBlockMetadata(data []byte) (*block.BlockMetadata, error) {
+	meta := &block.BlockMetadata{}
+	if err := json.Unmarshal(data, meta); err != nil {
+		return nil, fmt.Errorf("failed to parse block metadata: %w", err)
+	}
+
+	return meta, nil
+}
+
+// Orders blocks by the ID of their first message
+func SortBlocks(blocks []*block.BlockMetadata) {
+	sort.Slice(blocks, func(i, j int) bool {
+		return utils.CompareStreamMessageIDs(blocks[i].BlockStartId, blocks[j].BlockStartId) < 0
+	})
 }
@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/streamweaverio/broker/internal/block"
+	"github.com/stretchr/testify/assert"
+)
+
+// Archives three consecutive blocks of test-stream covering 1000-1999, 2000-2999 and 3000-3999
+func archiveTestBlocks(t *testing.T, storage Storage) {
+	// Archived out of order to check listings are sorted
+	for _, start := range []int64{3000, 1000, 2000} {
+		meta := &block.BlockMetadata{
+			StreamName:          "test-stream",
+			BlockID:             fmt.Sprintf("block-%d", start),
+			BlockStartTimestamp: start,
+			BlockEndTimestamp:   start + 999,
+			BlockStartId:        fmt.Sprintf("%d-0", start),
+			BlockEndId:          fmt.Sprintf("%d-0", start+999),
+		}
+		data, err := json.Marshal(meta)
+		assert.NoError(t, err)
+
+		err = storage.ArchiveBlock(context.Background(), newTestBlock(meta.BlockID, []byte("parquet-"+meta.BlockID), []byte("bloom"), data))
+		assert.NoError(t, err)
+	}
+}
+
+func blockIDs(blocks []*block.BlockMetadata) []string {
+	ids := make([]string, len(blocks))
+	for i, b := range blocks {
+		ids[i] = b.BlockID
+	}
+	return ids
+}
+
+// Runs the read path checks every storage provider must pass
+func testStorageReadPath(t *testing.T, storage Storage) {
+	ctx := context.Background()
+	archiveTestBlocks(t, storage)
+
+	t.Run("List every block in order", func(t *testing.T) {
+		blocks, err := storage.ListBlocks(ctx, "test-stream", nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"block-1000", "block-2000", "block-3000"}, blockIDs(blocks))
+	})
+
+	t.Run("List blocks overlapping a time range", func(t *testing.T) {
+		blocks, err := storage.ListBlocks(ctx, "test-stream", &BlockFilter{StartTimestamp: 2500})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"block-2000", "block-3000"}, blockIDs(blocks))
+
+		blocks, err = storage.ListBlocks(ctx, "test-stream", &BlockFilter{StartTimestamp: 1500, EndTimestamp: 2000})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"block-1000", "block-2000"}, blockIDs(blocks))
+	})
+
+	t.Run("List no blocks for an unknown stream", func(t *testing.T) {
+		blocks, err := storage.ListBlocks(ctx, "unknown-stream", nil)
+		assert.NoError(t, err)
+		assert.Empty(t, blocks)
+	})
+
+	t.Run("Open the readers of a block", func(t *testing.T) {
+		b, err := storage.GetBlock(ctx, "test-stream", "block-2000")
+		assert.NoError(t, err)
+		defer b.Parquet.Close()
+		defer b.Bloom.Close()
+
+		parquet, err := io.ReadAll(b.Parquet)
+		assert.NoError(t, err)
+		assert.Equal(t, "parquet-block-2000", string(parquet))
+
+		meta, err := ParseBlockMetadata(b.Meta)
+		assert.NoError(t, err)
+		assert.Equal(t, "2000-0", meta.BlockStartId)
+	})
+
+	t.Run("Delete a block", func(t *testing.T) {
+		err := storage.DeleteBlock(ctx, "test-stream", "block-1000")
+		assert.NoError(t, err)
+
+		_, err = storage.GetBlock(ctx, "test-stream", "block-1000")
+		assert.IsType(t, &StorageBlockNotFoundError{}, err)
+
+		err = storage.DeleteBlock(ctx, "test-stream", "block-1000")
+		assert.IsType(t, &StorageBlockNotFoundError{}, err)
+
+		blocks, err := storage.ListBlocks(ctx, "test-stream", nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"block-2000", "block-3000"}, blockIDs(blocks))
+	})
+}
@@ -0,0 +1,263 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.35.2
+// 	protoc        v4.23.4
+// source: broker/v1/broker.proto
+
+package brokerv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+var File_broker_v1_broker_proto protoreflect.FileDescriptor
+
+var file_broker_v1_broker_proto_rawDesc = []byte{
+	0x0a, 0x16, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x2f, 0x76, 0x31, 0x2f, 0x62, 0x72, 0x6f, 0x6b,
+	0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x16, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d,
+	0x77, 0x65, 0x61, 0x76, 0x65, 0x72, 0x2e, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x2e, 0x76, 0x31,
+	0x1a, 0x17, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x2f, 0x76, 0x31, 0x2f, 0x61, 0x72, 0x63, 0x68,
+	0x69, 0x76, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1e, 0x62, 0x72, 0x6f, 0x6b, 0x65,
+	0x72, 0x2f, 0x76, 0x31, 0x2f, 0x63, 0x6f, 0x6e, 0x73, 0x75, 0x6d, 0x65, 0x72, 0x5f, 0x67, 0x72,
+	0x6f, 0x75, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x16, 0x62, 0x72, 0x6f, 0x6b, 0x65,
+	0x72, 0x2f, 0x76, 0x31, 0x2f, 0x6c, 0x65, 0x61, 0x64, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x1a, 0x17, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x2f, 0x76, 0x31, 0x2f, 0x70, 0x75, 0x62,
+	0x6c, 0x69, 0x73, 0x68, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x16, 0x62, 0x72, 0x6f, 0x6b,
+	0x65, 0x72, 0x2f, 0x76, 0x31, 0x2f, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x1a, 0x16, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x2f, 0x76, 0x31, 0x2f, 0x73, 0x74,
+	0x72, 0x65, 0x61, 0x6d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x19, 0x62, 0x72, 0x6f, 0x6b,
+	0x65, 0x72, 0x2f, 0x76, 0x31, 0x2f, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x32, 0x99, 0x0e, 0x0a, 0x0d, 0x42, 0x72, 0x6f, 0x6b, 0x65, 0x72,
+	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x62, 0x0a, 0x09, 0x53, 0x75, 0x62, 0x73, 0x63,
+	0x72, 0x69, 0x62, 0x65, 0x12, 0x28, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x77, 0x65, 0x61,
+	0x76, 0x65, 0x72, 0x2e, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x75,
+	0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x29,
+	0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x77, 0x65, 0x61, 0x76, 0x65, 0x72, 0x2e, 0x62, 0x72,
+	0x6f, 0x6b, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62,
+	0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01, 0x12, 0x68, 0x0a, 0x0b, 0x52,
+	0x65, 0x61, 0x64, 0x41, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x12, 0x2a, 0x2e, 0x73, 0x74, 0x72,
+	0x65, 0x61, 0x6d, 0x77, 0x65, 0x61, 0x76, 0x65, 0x72, 0x2e, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72,
+	0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x61, 0x64, 0x41, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2b, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x77,
+	0x65, 0x61, 0x76, 0x65, 0x72, 0x2e, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e,
+	0x52, 0x65, 0x61, 0x64, 0x41, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x30, 0x01, 0x12, 0x69, 0x0a, 0x0c, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68,
+	0x42, 0x61, 0x74, 0x63, 0x68, 0x12, 0x2b, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x77, 0x65,
+	0x61, 0x76, 0x65, 0x72, 0x2e, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x50,
+	0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x2c, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x77, 0x65, 0x61, 0x76, 0x65,
+	0x72, 0x2e, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x75, 0x62, 0x6c,
+	0x69, 0x73, 0x68, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x8a, 0x01, 0x0a, 0x17, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x74, 0x72, 0x65, 0x61,
+	0x6d, 0x57, 0x69, 0x74, 0x68, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x36, 0x2e, 0x73,
+	0x74, 0x72, 0x65, 0x61, 0x6d, 0x77, 0x65, 0x61, 0x76, 0x65, 0x72, 0x2e, 0x62, 0x72, 0x6f, 0x6b,
+	0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x74, 0x72, 0x65,
+	0x61, 0x6d, 0x57, 0x69, 0x74, 0x68, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x37, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x77, 0x65, 0x61,
+	0x76, 0x65, 0x72, 0x2e, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x57, 0x69, 0x74, 0x68, 0x4f, 0x70,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x69, 0x0a,
+	0x0c, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x2b, 0x2e,
+	0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x77, 0x65, 0x61, 0x76, 0x65, 0x72, 0x2e, 0x62, 0x72, 0x6f,
+	0x6b, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x53, 0x74, 0x72,
+	0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2c, 0x2e, 0x73, 0x74, 0x72,
+	0x65, 0x61, 0x6d, 0x77, 0x65, 0x61, 0x76, 0x65, 0x72, 0x2e, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72,
+	0x2e, 0x76, 0x31, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x7e, 0x0a, 0x13, 0x53, 0x65, 0x74, 0x44,
+	0x65, 0x61, 0x64, 0x4c, 0x65, 0x74, 0x74, 0x65, 0x72, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12,
+	0x32, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x77, 0x65, 0x61, 0x76, 0x65, 0x72, 0x2e, 0x62,
+	0x72, 0x6f, 0x6b, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x74, 0x44, 0x65, 0x61, 0x64,
+	0x4c, 0x65, 0x74, 0x74, 0x65, 0x72, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x33, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x77, 0x65, 0x61, 0x76,
+	0x65, 0x72, 0x2e, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x74,
+	0x44, 0x65, 0x61, 0x64, 0x4c, 0x65, 0x74, 0x74, 0x65, 0x72, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x7e, 0x0a, 0x13, 0x44, 0x65, 0x6c, 0x65,
+	0x74, 0x65, 0x43, 0x6f, 0x6e, 0x73, 0x75, 0x6d, 0x65, 0x72, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x12,
+	0x32, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x77, 0x65, 0x61, 0x76, 0x65, 0x72, 0x2e, 0x62,
+	0x72, 0x6f, 0x6b, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x43,
+	0x6f, 0x6e, 0x73, 0x75, 0x6d, 0x65, 0x72, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x33, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x77, 0x65, 0x61, 0x76,
+	0x65, 0x72, 0x2e, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x6c,
+	0x65, 0x74, 0x65, 0x43, 0x6f, 0x6e, 0x73, 0x75, 0x6d, 0x65, 0x72, 0x47, 0x72, 0x6f, 0x75, 0x70,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x7e, 0x0a, 0x13, 0x55, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x43, 0x6f, 0x6e, 0x73, 0x75, 0x6d, 0x65, 0x72, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x12,
+	0x32, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x77, 0x65, 0x61, 0x76, 0x65, 0x72, 0x2e, 0x62,
+	0x72, 0x6f, 0x6b, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x43,
+	0x6f, 0x6e, 0x73, 0x75, 0x6d, 0x65, 0x72, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x33, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x77, 0x65, 0x61, 0x76,
+	0x65, 0x72, 0x2e, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x43, 0x6f, 0x6e, 0x73, 0x75, 0x6d, 0x65, 0x72, 0x47, 0x72, 0x6f, 0x75, 0x70,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x60, 0x0a, 0x09, 0x52, 0x65, 0x61, 0x64,
+	0x47, 0x72, 0x6f, 0x75, 0x70, 0x12, 0x28, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x77, 0x65,
+	0x61, 0x76, 0x65, 0x72, 0x2e, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x52,
+	0x65, 0x61, 0x64, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x29, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x77, 0x65, 0x61, 0x76, 0x65, 0x72, 0x2e, 0x62,
+	0x72, 0x6f, 0x6b, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x61, 0x64, 0x47, 0x72, 0x6f,
+	0x75, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4e, 0x0a, 0x03, 0x41, 0x63,
+	0x6b, 0x12, 0x22, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x77, 0x65, 0x61, 0x76, 0x65, 0x72,
+	0x2e, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x63, 0x6b, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x23, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x77, 0x65,
+	0x61, 0x76, 0x65, 0x72, 0x2e, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x41,
+	0x63, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x51, 0x0a, 0x04, 0x4e, 0x61,
+	0x63, 0x6b, 0x12, 0x23, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x77, 0x65, 0x61, 0x76, 0x65,
+	0x72, 0x2e, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x61, 0x63, 0x6b,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x24, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d,
+	0x77, 0x65, 0x61, 0x76, 0x65, 0x72, 0x2e, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x2e, 0x76, 0x31,
+	0x2e, 0x4e, 0x61, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x7b, 0x0a,
+	0x12, 0x47, 0x65, 0x74, 0x52, 0x65, 0x74, 0x65, 0x6e, 0x74, 0x69, 0x6f, 0x6e, 0x4c, 0x65, 0x61,
+	0x64, 0x65, 0x72, 0x12, 0x31, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x77, 0x65, 0x61, 0x76,
+	0x65, 0x72, 0x2e, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74,
+	0x52, 0x65, 0x74, 0x65, 0x6e, 0x74, 0x69, 0x6f, 0x6e, 0x4c, 0x65, 0x61, 0x64, 0x65, 0x72, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x32, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x77,
+	0x65, 0x61, 0x76, 0x65, 0x72, 0x2e, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e,
+	0x47, 0x65, 0x74, 0x52, 0x65, 0x74, 0x65, 0x6e, 0x74, 0x69, 0x6f, 0x6e, 0x4c, 0x65, 0x61, 0x64,
+	0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x6f, 0x0a, 0x0e, 0x52, 0x65,
+	0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x12, 0x2d, 0x2e, 0x73,
+	0x74, 0x72, 0x65, 0x61, 0x6d, 0x77, 0x65, 0x61, 0x76, 0x65, 0x72, 0x2e, 0x62, 0x72, 0x6f, 0x6b,
+	0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x53, 0x63,
+	0x68, 0x65, 0x6d, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2e, 0x2e, 0x73, 0x74,
+	0x72, 0x65, 0x61, 0x6d, 0x77, 0x65, 0x61, 0x76, 0x65, 0x72, 0x2e, 0x62, 0x72, 0x6f, 0x6b, 0x65,
+	0x72, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x53, 0x63, 0x68,
+	0x65, 0x6d, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x60, 0x0a, 0x09, 0x47,
+	0x65, 0x74, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x12, 0x28, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61,
+	0x6d, 0x77, 0x65, 0x61, 0x76, 0x65, 0x72, 0x2e, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x2e, 0x76,
+	0x31, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x29, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x77, 0x65, 0x61, 0x76, 0x65,
+	0x72, 0x2e, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x53,
+	0x63, 0x68, 0x65, 0x6d, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x75, 0x0a,
+	0x10, 0x47, 0x65, 0x74, 0x53, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x53, 0x63, 0x68, 0x65, 0x6d,
+	0x61, 0x12, 0x2f, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x77, 0x65, 0x61, 0x76, 0x65, 0x72,
+	0x2e, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x75,
+	0x62, 0x6a, 0x65, 0x63, 0x74, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x30, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x77, 0x65, 0x61, 0x76, 0x65,
+	0x72, 0x2e, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x53,
+	0x75, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x8a, 0x01, 0x0a, 0x17, 0x53, 0x65, 0x74, 0x53, 0x75, 0x62, 0x6a,
+	0x65, 0x63, 0x74, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x74, 0x69, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79,
+	0x12, 0x36, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x77, 0x65, 0x61, 0x76, 0x65, 0x72, 0x2e,
+	0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x74, 0x53, 0x75, 0x62,
+	0x6a, 0x65, 0x63, 0x74, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x74, 0x69, 0x62, 0x69, 0x6c, 0x69, 0x74,
+	0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x37, 0x2e, 0x73, 0x74, 0x72, 0x65, 0x61,
+	0x6d, 0x77, 0x65, 0x61, 0x76, 0x65, 0x72, 0x2e, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x2e, 0x76,
+	0x31, 0x2e, 0x53, 0x65, 0x74, 0x53, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x43, 0x6f, 0x6d, 0x70,
+	0x61, 0x74, 0x69, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x42, 0x40, 0x5a, 0x3e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
+	0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x77, 0x65, 0x61, 0x76, 0x65, 0x72, 0x69, 0x6f, 0x2f, 0x62,
+	0x72, 0x6f, 0x6b, 0x65, 0x72, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73,
+	0x2f, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x2f, 0x76, 0x31, 0x3b, 0x62, 0x72, 0x6f, 0x6b, 0x65,
+	0x72, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var file_broker_v1_broker_proto_goTypes = []any{
+	(*SubscribeRequest)(nil),                // 0: streamweaver.broker.v1.SubscribeRequest
+	(*ReadArchiveRequest)(nil),              // 1: streamweaver.broker.v1.ReadArchiveRequest
+	(*PublishBatchRequest)(nil),             // 2: streamweaver.broker.v1.PublishBatchRequest
+	(*CreateStreamWithOptionsRequest)(nil),  // 3: streamweaver.broker.v1.CreateStreamWithOptionsRequest
+	(*UpdateStreamRequest)(nil),             // 4: streamweaver.broker.v1.UpdateStreamRequest
+	(*SetDeadLetterPolicyRequest)(nil),      // 5: streamweaver.broker.v1.SetDeadLetterPolicyRequest
+	(*DeleteConsumerGroupRequest)(nil),      // 6: streamweaver.broker.v1.DeleteConsumerGroupRequest
+	(*UpdateConsumerGroupRequest)(nil),      // 7: streamweaver.broker.v1.UpdateConsumerGroupRequest
+	(*ReadGroupRequest)(nil),                // 8: streamweaver.broker.v1.ReadGroupRequest
+	(*AckRequest)(nil),                      // 9: streamweaver.broker.v1.AckRequest
+	(*NackRequest)(nil),                     // 10: streamweaver.broker.v1.NackRequest
+	(*GetRetentionLeaderRequest)(nil),       // 11: streamweaver.broker.v1.GetRetentionLeaderRequest
+	(*RegisterSchemaRequest)(nil),           // 12: streamweaver.broker.v1.RegisterSchemaRequest
+	(*GetSchemaRequest)(nil),                // 13: streamweaver.broker.v1.GetSchemaRequest
+	(*GetSubjectSchemaRequest)(nil),         // 14: streamweaver.broker.v1.GetSubjectSchemaRequest
+	(*SetSubjectCompatibilityRequest)(nil),  // 15: streamweaver.broker.v1.SetSubjectCompatibilityRequest
+	(*SubscribeResponse)(nil),               // 16: streamweaver.broker.v1.SubscribeResponse
+	(*ReadArchiveResponse)(nil),             // 17: streamweaver.broker.v1.ReadArchiveResponse
+	(*PublishBatchResponse)(nil),            // 18: streamweaver.broker.v1.PublishBatchResponse
+	(*CreateStreamWithOptionsResponse)(nil), // 19: streamweaver.broker.v1.CreateStreamWithOptionsResponse
+	(*UpdateStreamResponse)(nil),            // 20: streamweaver.broker.v1.UpdateStreamResponse
+	(*SetDeadLetterPolicyResponse)(nil),     // 21: streamweaver.broker.v1.SetDeadLetterPolicyResponse
+	(*DeleteConsumerGroupResponse)(nil),     // 22: streamweaver.broker.v1.DeleteConsumerGroupResponse
+	(*UpdateConsumerGroupResponse)(nil),     // 23: streamweaver.broker.v1.UpdateConsumerGroupResponse
+	(*ReadGroupResponse)(nil),               // 24: streamweaver.broker.v1.ReadGroupResponse
+	(*AckResponse)(nil),                     // 25: streamweaver.broker.v1.AckResponse
+	(*NackResponse)(nil),                    // 26: streamweaver.broker.v1.NackResponse
+	(*GetRetentionLeaderResponse)(nil),      // 27: streamweaver.broker.v1.GetRetentionLeaderResponse
+	(*RegisterSchemaResponse)(nil),          // 28: streamweaver.broker.v1.RegisterSchemaResponse
+	(*GetSchemaResponse)(nil),               // 29: streamweaver.broker.v1.GetSchemaResponse
+	(*GetSubjectSchemaResponse)(nil),        // 30: streamweaver.broker.v1.GetSubjectSchemaResponse
+	(*SetSubjectCompatibilityResponse)(nil), // 31: streamweaver.broker.v1.SetSubjectCompatibilityResponse
+}
+var file_broker_v1_broker_proto_depIdxs = []int32{
+	0,  // 0: streamweaver.broker.v1.BrokerService.Subscribe:input_type -> streamweaver.broker.v1.SubscribeRequest
+	1,  // 1: streamweaver.broker.v1.BrokerService.ReadArchive:input_type -> streamweaver.broker.v1.ReadArchiveRequest
+	2,  // 2: streamweaver.broker.v1.BrokerService.PublishBatch:input_type -> streamweaver.broker.v1.PublishBatchRequest
+	3,  // 3: streamweaver.broker.v1.BrokerService.CreateStreamWithOptions:input_type -> streamweaver.broker.v1.CreateStreamWithOptionsRequest
+	4,  // 4: streamweaver.broker.v1.BrokerService.UpdateStream:input_type -> streamweaver.broker.v1.UpdateStreamRequest
+	5,  // 5: streamweaver.broker.v1.BrokerService.SetDeadLetterPolicy:input_type -> streamweaver.broker.v1.SetDeadLetterPolicyRequest
+	6,  // 6: streamweaver.broker.v1.BrokerService.DeleteConsumerGroup:input_type -> streamweaver.broker.v1.DeleteConsumerGroupRequest
+	7,  // 7: streamweaver.broker.v1.BrokerService.UpdateConsumerGroup:input_type -> streamweaver.broker.v1.UpdateConsumerGroupRequest
+	8,  // 8: streamweaver.broker.v1.BrokerService.ReadGroup:input_type -> streamweaver.broker.v1.ReadGroupRequest
+	9,  // 9: streamweaver.broker.v1.BrokerService.Ack:input_type -> streamweaver.broker.v1.AckRequest
+	10, // 10: streamweaver.broker.v1.BrokerService.Nack:input_type -> streamweaver.broker.v1.NackRequest
+	11, // 11: streamweaver.broker.v1.BrokerService.GetRetentionLeader:input_type -> streamweaver.broker.v1.GetRetentionLeaderRequest
+	12, // 12: streamweaver.broker.v1.BrokerService.RegisterSchema:input_type -> streamweaver.broker.v1.RegisterSchemaRequest
+	13, // 13: streamweaver.broker.v1.BrokerService.GetSchema:input_type -> streamweaver.broker.v1.GetSchemaRequest
+	14, // 14: streamweaver.broker.v1.BrokerService.GetSubjectSchema:input_type -> streamweaver.broker.v1.GetSubjectSchemaRequest
+	15, // 15: streamweaver.broker.v1.BrokerService.SetSubjectCompatibility:input_type -> streamweaver.broker.v1.SetSubjectCompatibilityRequest
+	16, // 16: streamweaver.broker.v1.BrokerService.Subscribe:output_type -> streamweaver.broker.v1.SubscribeResponse
+	17, // 17: streamweaver.broker.v1.BrokerService.ReadArchive:output_type -> streamweaver.broker.v1.ReadArchiveResponse
+	18, // 18: streamweaver.broker.v1.BrokerService.PublishBatch:output_type -> streamweaver.broker.v1.PublishBatchResponse
+	19, // 19: streamweaver.broker.v1.BrokerService.CreateStreamWithOptions:output_type -> streamweaver.broker.v1.CreateStreamWithOptionsResponse
+	20, // 20: streamweaver.broker.v1.BrokerService.UpdateStream:output_type -> streamweaver.broker.v1.UpdateStreamResponse
+	21, // 21: streamweaver.broker.v1.BrokerService.SetDeadLetterPolicy:output_type -> streamweaver.broker.v1.SetDeadLetterPolicyResponse
+	22, // 22: streamweaver.broker.v1.BrokerService.DeleteConsumerGroup:output_type -> streamweaver.broker.v1.DeleteConsumerGroupResponse
+	23, // 23: streamweaver.broker.v1.BrokerService.UpdateConsumerGroup:output_type -> streamweaver.broker.v1.UpdateConsumerGroupResponse
+	24, // 24: streamweaver.broker.v1.BrokerService.ReadGroup:output_type -> streamweaver.broker.v1.ReadGroupResponse
+	25, // 25: streamweaver.broker.v1.BrokerService.Ack:output_type -> streamweaver.broker.v1.AckResponse
+	26, // 26: streamweaver.broker.v1.BrokerService.Nack:output_type -> streamweaver.broker.v1.NackResponse
+	27, // 27: streamweaver.broker.v1.BrokerService.GetRetentionLeader:output_type -> streamweaver.broker.v1.GetRetentionLeaderResponse
+	28, // 28: streamweaver.broker.v1.BrokerService.RegisterSchema:output_type -> streamweaver.broker.v1.RegisterSchemaResponse
+	29, // 29: streamweaver.broker.v1.BrokerService.GetSchema:output_type -> streamweaver.broker.v1.GetSchemaResponse
+	30, // 30: streamweaver.broker.v1.BrokerService.GetSubjectSchema:output_type -> streamweaver.broker.v1.GetSubjectSchemaResponse
+	31, // 31: streamweaver.broker.v1.BrokerService.SetSubjectCompatibility:output_type -> streamweaver.broker.v1.SetSubjectCompatibilityResponse
+	16, // [16:32] is the sub-list for method output_type
+	0,  // [0:16] is the sub-list for method input_type
+	0,  // [0:0] is the sub-list for extension type_name
+	0,  // [0:0] is the sub-list for extension extendee
+	0,  // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_broker_v1_broker_proto_init() }
+func file_broker_v1_broker_proto_init() {
+	if File_broker_v1_broker_proto != nil {
+		return
+	}
+	file_broker_v1_archive_proto_init()
+	file_broker_v1_consumer_group_proto_init()
+	file_broker_v1_leader_proto_init()
+	file_broker_v1_publish_proto_init()
+	file_broker_v1_schema_proto_init()
+	file_broker_v1_stream_proto_init()
+	file_broker_v1_subscribe_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_broker_v1_broker_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   0,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_broker_v1_broker_proto_goTypes,
+		DependencyIndexes: file_broker_v1_broker_proto_depIdxs,
+	}.Build()
+	File_broker_v1_broker_proto = out.File
+	file_broker_v1_broker_proto_rawDesc = nil
+	file_broker_v1_broker_proto_goTypes = nil
+	file_broker_v1_broker_proto_depIdxs = nil
+}
@@ -0,0 +1,732 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v4.23.4
+// source: broker/v1/broker.proto
+
+package brokerv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// BrokerServiceClient is the client API for BrokerService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type BrokerServiceClient interface {
+	// Subscribe to a stream and receive new messages as they are appended
+	Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (BrokerService_SubscribeClient, error)
+	// Replay archived messages of a stream in message ID order
+	ReadArchive(ctx context.Context, in *ReadArchiveRequest, opts ...grpc.CallOption) (BrokerService_ReadArchiveClient, error)
+	// Publish a batch of messages to a stream, optionally appending all of them or none
+	PublishBatch(ctx context.Context, in *PublishBatchRequest, opts ...grpc.CallOption) (*PublishBatchResponse, error)
+	// Create a stream with its retention settings
+	CreateStreamWithOptions(ctx context.Context, in *CreateStreamWithOptionsRequest, opts ...grpc.CallOption) (*CreateStreamWithOptionsResponse, error)
+	// Change the retention and cleanup policy of an existing stream
+	UpdateStream(ctx context.Context, in *UpdateStreamRequest, opts ...grpc.CallOption) (*UpdateStreamResponse, error)
+	// Set when messages of a stream are moved to a dead letter stream
+	SetDeadLetterPolicy(ctx context.Context, in *SetDeadLetterPolicyRequest, opts ...grpc.CallOption) (*SetDeadLetterPolicyResponse, error)
+	// Delete a consumer group and its pending entries
+	DeleteConsumerGroup(ctx context.Context, in *DeleteConsumerGroupRequest, opts ...grpc.CallOption) (*DeleteConsumerGroupResponse, error)
+	// Update the settings of a consumer group
+	UpdateConsumerGroup(ctx context.Context, in *UpdateConsumerGroupRequest, opts ...grpc.CallOption) (*UpdateConsumerGroupResponse, error)
+	// Read messages as a named consumer of a consumer group
+	ReadGroup(ctx context.Context, in *ReadGroupRequest, opts ...grpc.CallOption) (*ReadGroupResponse, error)
+	// Acknowledge messages read from a consumer group
+	Ack(ctx context.Context, in *AckRequest, opts ...grpc.CallOption) (*AckResponse, error)
+	// Negatively acknowledge messages read from a consumer group so they are redelivered
+	Nack(ctx context.Context, in *NackRequest, opts ...grpc.CallOption) (*NackResponse, error)
+	// Get the broker instance currently running retention policies
+	GetRetentionLeader(ctx context.Context, in *GetRetentionLeaderRequest, opts ...grpc.CallOption) (*GetRetentionLeaderResponse, error)
+	// Register a schema as the next version of a subject
+	RegisterSchema(ctx context.Context, in *RegisterSchemaRequest, opts ...grpc.CallOption) (*RegisterSchemaResponse, error)
+	// Get a schema by ID
+	GetSchema(ctx context.Context, in *GetSchemaRequest, opts ...grpc.CallOption) (*GetSchemaResponse, error)
+	// Get a version of the schema of a subject
+	GetSubjectSchema(ctx context.Context, in *GetSubjectSchemaRequest, opts ...grpc.CallOption) (*GetSubjectSchemaResponse, error)
+	// Set the compatibility mode new versions of a subject are checked with
+	SetSubjectCompatibility(ctx context.Context, in *SetSubjectCompatibilityRequest, opts ...grpc.CallOption) (*SetSubjectCompatibilityResponse, error)
+}
+
+type brokerServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBrokerServiceClient(cc grpc.ClientConnInterface) BrokerServiceClient {
+	return &brokerServiceClient{cc}
+}
+
+func (c *brokerServiceClient) Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (BrokerService_SubscribeClient, error) {
+	stream, err := c.cc.NewStream(ctx, &BrokerService_ServiceDesc.Streams[0], "/streamweaver.broker.v1.BrokerService/Subscribe", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &brokerServiceSubscribeClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BrokerService_SubscribeClient interface {
+	Recv() (*SubscribeResponse, error)
+	grpc.ClientStream
+}
+
+type brokerServiceSubscribeClient struct {
+	grpc.ClientStream
+}
+
+func (x *brokerServiceSubscribeClient) Recv() (*SubscribeResponse, error) {
+	m := new(SubscribeResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *brokerServiceClient) ReadArchive(ctx context.Context, in *ReadArchiveRequest, opts ...grpc.CallOption) (BrokerService_ReadArchiveClient, error) {
+	stream, err := c.cc.NewStream(ctx, &BrokerService_ServiceDesc.Streams[1], "/streamweaver.broker.v1.BrokerService/ReadArchive", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &brokerServiceReadArchiveClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BrokerService_ReadArchiveClient interface {
+	Recv() (*ReadArchiveResponse, error)
+	grpc.ClientStream
+}
+
+type brokerServiceReadArchiveClient struct {
+	grpc.ClientStream
+}
+
+func (x *brokerServiceReadArchiveClient) Recv() (*ReadArchiveResponse, error) {
+	m := new(ReadArchiveResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *brokerServiceClient) PublishBatch(ctx context.Context, in *PublishBatchRequest, opts ...grpc.CallOption) (*PublishBatchResponse, error) {
+	out := new(PublishBatchResponse)
+	err := c.cc.Invoke(ctx, "/streamweaver.broker.v1.BrokerService/PublishBatch", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *brokerServiceClient) CreateStreamWithOptions(ctx context.Context, in *CreateStreamWithOptionsRequest, opts ...grpc.CallOption) (*CreateStreamWithOptionsResponse, error) {
+	out := new(CreateStreamWithOptionsResponse)
+	err := c.cc.Invoke(ctx, "/streamweaver.broker.v1.BrokerService/CreateStreamWithOptions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *brokerServiceClient) UpdateStream(ctx context.Context, in *UpdateStreamRequest, opts ...grpc.CallOption) (*UpdateStreamResponse, error) {
+	out := new(UpdateStreamResponse)
+	err := c.cc.Invoke(ctx, "/streamweaver.broker.v1.BrokerService/UpdateStream", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *brokerServiceClient) SetDeadLetterPolicy(ctx context.Context, in *SetDeadLetterPolicyRequest, opts ...grpc.CallOption) (*SetDeadLetterPolicyResponse, error) {
+	out := new(SetDeadLetterPolicyResponse)
+	err := c.cc.Invoke(ctx, "/streamweaver.broker.v1.BrokerService/SetDeadLetterPolicy", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *brokerServiceClient) DeleteConsumerGroup(ctx context.Context, in *DeleteConsumerGroupRequest, opts ...grpc.CallOption) (*DeleteConsumerGroupResponse, error) {
+	out := new(DeleteConsumerGroupResponse)
+	err := c.cc.Invoke(ctx, "/streamweaver.broker.v1.BrokerService/DeleteConsumerGroup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *brokerServiceClient) UpdateConsumerGroup(ctx context.Context, in *UpdateConsumerGroupRequest, opts ...grpc.CallOption) (*UpdateConsumerGroupResponse, error) {
+	out := new(UpdateConsumerGroupResponse)
+	err := c.cc.Invoke(ctx, "/streamweaver.broker.v1.BrokerService/UpdateConsumerGroup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *brokerServiceClient) ReadGroup(ctx context.Context, in *ReadGroupRequest, opts ...grpc.CallOption) (*ReadGroupResponse, error) {
+	out := new(ReadGroupResponse)
+	err := c.cc.Invoke(ctx, "/streamweaver.broker.v1.BrokerService/ReadGroup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *brokerServiceClient) Ack(ctx context.Context, in *AckRequest, opts ...grpc.CallOption) (*AckResponse, error) {
+	out := new(AckResponse)
+	err := c.cc.Invoke(ctx, "/streamweaver.broker.v1.BrokerService/Ack", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *brokerServiceClient) Nack(ctx context.Context, in *NackRequest, opts ...grpc.CallOption) (*NackResponse, error) {
+	out := new(NackResponse)
+	err := c.cc.Invoke(ctx, "/streamweaver.broker.v1.BrokerService/Nack", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *brokerServiceClient) GetRetentionLeader(ctx context.Context, in *GetRetentionLeaderRequest, opts ...grpc.CallOption) (*GetRetentionLeaderResponse, error) {
+	out := new(GetRetentionLeaderResponse)
+	err := c.cc.Invoke(ctx, "/streamweaver.broker.v1.BrokerService/GetRetentionLeader", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *brokerServiceClient) RegisterSchema(ctx context.Context, in *RegisterSchemaRequest, opts ...grpc.CallOption) (*RegisterSchemaResponse, error) {
+	out := new(RegisterSchemaResponse)
+	err := c.cc.Invoke(ctx, "/streamweaver.broker.v1.BrokerService/RegisterSchema", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *brokerServiceClient) GetSchema(ctx context.Context, in *GetSchemaRequest, opts ...grpc.CallOption) (*GetSchemaResponse, error) {
+	out := new(GetSchemaResponse)
+	err := c.cc.Invoke(ctx, "/streamweaver.broker.v1.BrokerService/GetSchema", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *brokerServiceClient) GetSubjectSchema(ctx context.Context, in *GetSubjectSchemaRequest, opts ...grpc.CallOption) (*GetSubjectSchemaResponse, error) {
+	out := new(GetSubjectSchemaResponse)
+	err := c.cc.Invoke(ctx, "/streamweaver.broker.v1.BrokerService/GetSubjectSchema", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *brokerServiceClient) SetSubjectCompatibility(ctx context.Context, in *SetSubjectCompatibilityRequest, opts ...grpc.CallOption) (*SetSubjectCompatibilityResponse, error) {
+	out := new(SetSubjectCompatibilityResponse)
+	err := c.cc.Invoke(ctx, "/streamweaver.broker.v1.BrokerService/SetSubjectCompatibility", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// BrokerServiceServer is the server API for BrokerService service.
+// All implementations must embed UnimplementedBrokerServiceServer
+// for forward compatibility
+type BrokerServiceServer interface {
+	// Subscribe to a stream and receive new messages as they are appended
+	Subscribe(*SubscribeRequest, BrokerService_SubscribeServer) error
+	// Replay archived messages of a stream in message ID order
+	ReadArchive(*ReadArchiveRequest, BrokerService_ReadArchiveServer) error
+	// Publish a batch of messages to a stream, optionally appending all of them or none
+	PublishBatch(context.Context, *PublishBatchRequest) (*PublishBatchResponse, error)
+	// Create a stream with its retention settings
+	CreateStreamWithOptions(context.Context, *CreateStreamWithOptionsRequest) (*CreateStreamWithOptionsResponse, error)
+	// Change the retention and cleanup policy of an existing stream
+	UpdateStream(context.Context, *UpdateStreamRequest) (*UpdateStreamResponse, error)
+	// Set when messages of a stream are moved to a dead letter stream
+	SetDeadLetterPolicy(context.Context, *SetDeadLetterPolicyRequest) (*SetDeadLetterPolicyResponse, error)
+	// Delete a consumer group and its pending entries
+	DeleteConsumerGroup(context.Context, *DeleteConsumerGroupRequest) (*DeleteConsumerGroupResponse, error)
+	// Update the settings of a consumer group
+	UpdateConsumerGroup(context.Context, *UpdateConsumerGroupRequest) (*UpdateConsumerGroupResponse, error)
+	// Read messages as a named consumer of a consumer group
+	ReadGroup(context.Context, *ReadGroupRequest) (*ReadGroupResponse, error)
+	// Acknowledge messages read from a consumer group
+	Ack(context.Context, *AckRequest) (*AckResponse, error)
+	// Negatively acknowledge messages read from a consumer group so they are redelivered
+	Nack(context.Context, *NackRequest) (*NackResponse, error)
+	// Get the broker instance currently running retention policies
+	GetRetentionLeader(context.Context, *GetRetentionLeaderRequest) (*GetRetentionLeaderResponse, error)
+	// Register a schema as the next version of a subject
+	RegisterSchema(context.Context, *RegisterSchemaRequest) (*RegisterSchemaResponse, error)
+	// Get a schema by ID
+	GetSchema(context.Context, *GetSchemaRequest) (*GetSchemaResponse, error)
+	// Get a version of the schema of a subject
+	GetSubjectSchema(context.Context, *GetSubjectSchemaRequest) (*GetSubjectSchemaResponse, error)
+	// Set the compatibility mode new versions of a subject are checked with
+	SetSubjectCompatibility(context.Context, *SetSubjectCompatibilityRequest) (*SetSubjectCompatibilityResponse, error)
+	mustEmbedUnimplementedBrokerServiceServer()
+}
+
+// UnimplementedBrokerServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedBrokerServiceServer struct {
+}
+
+func (UnimplementedBrokerServiceServer) Subscribe(*SubscribeRequest, BrokerService_SubscribeServer) error {
+	return status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
+}
+func (UnimplementedBrokerServiceServer) ReadArchive(*ReadArchiveRequest, BrokerService_ReadArchiveServer) error {
+	return status.Errorf(codes.Unimplemented, "method ReadArchive not implemented")
+}
+func (UnimplementedBrokerServiceServer) PublishBatch(context.Context, *PublishBatchRequest) (*PublishBatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PublishBatch not implemented")
+}
+func (UnimplementedBrokerServiceServer) CreateStreamWithOptions(context.Context, *CreateStreamWithOptionsRequest) (*CreateStreamWithOptionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateStreamWithOptions not implemented")
+}
+func (UnimplementedBrokerServiceServer) UpdateStream(context.Context, *UpdateStreamRequest) (*UpdateStreamResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateStream not implemented")
+}
+func (UnimplementedBrokerServiceServer) SetDeadLetterPolicy(context.Context, *SetDeadLetterPolicyRequest) (*SetDeadLetterPolicyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetDeadLetterPolicy not implemented")
+}
+func (UnimplementedBrokerServiceServer) DeleteConsumerGroup(context.Context, *DeleteConsumerGroupRequest) (*DeleteConsumerGroupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteConsumerGroup not implemented")
+}
+func (UnimplementedBrokerServiceServer) UpdateConsumerGroup(context.Context, *UpdateConsumerGroupRequest) (*UpdateConsumerGroupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateConsumerGroup not implemented")
+}
+func (UnimplementedBrokerServiceServer) ReadGroup(context.Context, *ReadGroupRequest) (*ReadGroupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReadGroup not implemented")
+}
+func (UnimplementedBrokerServiceServer) Ack(context.Context, *AckRequest) (*AckResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Ack not implemented")
+}
+func (UnimplementedBrokerServiceServer) Nack(context.Context, *NackRequest) (*NackResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Nack not implemented")
+}
+func (UnimplementedBrokerServiceServer) GetRetentionLeader(context.Context, *GetRetentionLeaderRequest) (*GetRetentionLeaderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRetentionLeader not implemented")
+}
+func (UnimplementedBrokerServiceServer) RegisterSchema(context.Context, *RegisterSchemaRequest) (*RegisterSchemaResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterSchema not implemented")
+}
+func (UnimplementedBrokerServiceServer) GetSchema(context.Context, *GetSchemaRequest) (*GetSchemaResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSchema not implemented")
+}
+func (UnimplementedBrokerServiceServer) GetSubjectSchema(context.Context, *GetSubjectSchemaRequest) (*GetSubjectSchemaResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSubjectSchema not implemented")
+}
+func (UnimplementedBrokerServiceServer) SetSubjectCompatibility(context.Context, *SetSubjectCompatibilityRequest) (*SetSubjectCompatibilityResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetSubjectCompatibility not implemented")
+}
+func (UnimplementedBrokerServiceServer) mustEmbedUnimplementedBrokerServiceServer() {}
+
+// UnsafeBrokerServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to BrokerServiceServer will
+// result in compilation errors.
+type UnsafeBrokerServiceServer interface {
+	mustEmbedUnimplementedBrokerServiceServer()
+}
+
+func RegisterBrokerServiceServer(s grpc.ServiceRegistrar, srv BrokerServiceServer) {
+	s.RegisterService(&BrokerService_ServiceDesc, srv)
+}
+
+func _BrokerService_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BrokerServiceServer).Subscribe(m, &brokerServiceSubscribeServer{stream})
+}
+
+type BrokerService_SubscribeServer interface {
+	Send(*SubscribeResponse) error
+	grpc.ServerStream
+}
+
+type brokerServiceSubscribeServer struct {
+	grpc.ServerStream
+}
+
+func (x *brokerServiceSubscribeServer) Send(m *SubscribeResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _BrokerService_ReadArchive_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ReadArchiveRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BrokerServiceServer).ReadArchive(m, &brokerServiceReadArchiveServer{stream})
+}
+
+type BrokerService_ReadArchiveServer interface {
+	Send(*ReadArchiveResponse) error
+	grpc.ServerStream
+}
+
+type brokerServiceReadArchiveServer struct {
+	grpc.ServerStream
+}
+
+func (x *brokerServiceReadArchiveServer) Send(m *ReadArchiveResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _BrokerService_PublishBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PublishBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BrokerServiceServer).PublishBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/streamweaver.broker.v1.BrokerService/PublishBatch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BrokerServiceServer).PublishBatch(ctx, req.(*PublishBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BrokerService_CreateStreamWithOptions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateStreamWithOptionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BrokerServiceServer).CreateStreamWithOptions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/streamweaver.broker.v1.BrokerService/CreateStreamWithOptions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BrokerServiceServer).CreateStreamWithOptions(ctx, req.(*CreateStreamWithOptionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BrokerService_UpdateStream_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateStreamRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BrokerServiceServer).UpdateStream(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/streamweaver.broker.v1.BrokerService/UpdateStream",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BrokerServiceServer).UpdateStream(ctx, req.(*UpdateStreamRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BrokerService_SetDeadLetterPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetDeadLetterPolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BrokerServiceServer).SetDeadLetterPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/streamweaver.broker.v1.BrokerService/SetDeadLetterPolicy",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BrokerServiceServer).SetDeadLetterPolicy(ctx, req.(*SetDeadLetterPolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BrokerService_DeleteConsumerGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteConsumerGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BrokerServiceServer).DeleteConsumerGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/streamweaver.broker.v1.BrokerService/DeleteConsumerGroup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BrokerServiceServer).DeleteConsumerGroup(ctx, req.(*DeleteConsumerGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BrokerService_UpdateConsumerGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateConsumerGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BrokerServiceServer).UpdateConsumerGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/streamweaver.broker.v1.BrokerService/UpdateConsumerGroup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BrokerServiceServer).UpdateConsumerGroup(ctx, req.(*UpdateConsumerGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BrokerService_ReadGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BrokerServiceServer).ReadGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/streamweaver.broker.v1.BrokerService/ReadGroup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BrokerServiceServer).ReadGroup(ctx, req.(*ReadGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BrokerService_Ack_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AckRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BrokerServiceServer).Ack(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/streamweaver.broker.v1.BrokerService/Ack",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BrokerServiceServer).Ack(ctx, req.(*AckRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BrokerService_Nack_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NackRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BrokerServiceServer).Nack(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/streamweaver.broker.v1.BrokerService/Nack",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BrokerServiceServer).Nack(ctx, req.(*NackRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BrokerService_GetRetentionLeader_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRetentionLeaderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BrokerServiceServer).GetRetentionLeader(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/streamweaver.broker.v1.BrokerService/GetRetentionLeader",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BrokerServiceServer).GetRetentionLeader(ctx, req.(*GetRetentionLeaderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BrokerService_RegisterSchema_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterSchemaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BrokerServiceServer).RegisterSchema(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/streamweaver.broker.v1.BrokerService/RegisterSchema",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BrokerServiceServer).RegisterSchema(ctx, req.(*RegisterSchemaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BrokerService_GetSchema_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSchemaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BrokerServiceServer).GetSchema(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/streamweaver.broker.v1.BrokerService/GetSchema",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BrokerServiceServer).GetSchema(ctx, req.(*GetSchemaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BrokerService_GetSubjectSchema_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSubjectSchemaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BrokerServiceServer).GetSubjectSchema(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/streamweaver.broker.v1.BrokerService/GetSubjectSchema",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BrokerServiceServer).GetSubjectSchema(ctx, req.(*GetSubjectSchemaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BrokerService_SetSubjectCompatibility_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetSubjectCompatibilityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BrokerServiceServer).SetSubjectCompatibility(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/streamweaver.broker.v1.BrokerService/SetSubjectCompatibility",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BrokerServiceServer).SetSubjectCompatibility(ctx, req.(*SetSubjectCompatibilityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// BrokerService_ServiceDesc is the grpc.ServiceDesc for BrokerService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var BrokerService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "streamweaver.broker.v1.BrokerService",
+	HandlerType: (*BrokerServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "PublishBatch",
+			Handler:    _BrokerService_PublishBatch_Handler,
+		},
+		{
+			MethodName: "CreateStreamWithOptions",
+			Handler:    _BrokerService_CreateStreamWithOptions_Handler,
+		},
+		{
+			MethodName: "UpdateStream",
+			Handler:    _BrokerService_UpdateStream_Handler,
+		},
+		{
+			MethodName: "SetDeadLetterPolicy",
+			Handler:    _BrokerService_SetDeadLetterPolicy_Handler,
+		},
+		{
+			MethodName: "DeleteConsumerGroup",
+			Handler:    _BrokerService_DeleteConsumerGroup_Handler,
+		},
+		{
+			MethodName: "UpdateConsumerGroup",
+			Handler:    _BrokerService_UpdateConsumerGroup_Handler,
+		},
+		{
+			MethodName: "ReadGroup",
+			Handler:    _BrokerService_ReadGroup_Handler,
+		},
+		{
+			MethodName: "Ack",
+			Handler:    _BrokerService_Ack_Handler,
+		},
+		{
+			MethodName: "Nack",
+			Handler:    _BrokerService_Nack_Handler,
+		},
+		{
+			MethodName: "GetRetentionLeader",
+			Handler:    _BrokerService_GetRetentionLeader_Handler,
+		},
+		{
+			MethodName: "RegisterSchema",
+			Handler:    _BrokerService_RegisterSchema_Handler,
+		},
+		{
+			MethodName: "GetSchema",
+			Handler:    _BrokerService_GetSchema_Handler,
+		},
+		{
+			MethodName: "GetSubjectSchema",
+			Handler:    _BrokerService_GetSubjectSchema_Handler,
+		},
+		{
+			MethodName: "SetSubjectCompatibility",
+			Handler:    _BrokerService_SetSubjectCompatibility_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Subscribe",
+			Handler:       _BrokerService_Subscribe_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ReadArchive",
+			Handler:       _BrokerService_ReadArchive_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "broker/v1/broker.proto",
+}
@@ -1,8 +1,10 @@
 package utils
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -31,11 +33,109 @@ func GetTimestampFromStreamMessageID(id string) (int64, error) {
 	return timestamp, nil
 }
 
+// Marks message values serialized with base64 encoded field names and values, values without it are plain JSON
+const STREAM_MESSAGE_VALUES_BASE64_PREFIX = "base64:"
+
+// Serializes the values of a stream message, field names and values are base64 encoded so binary payloads are kept as is
 func SerializeStreamMessageValues(values map[string]interface{}) string {
-	bytes, err := json.Marshal(values)
+	encoded := make(map[string]string, len(values))
+	for key, value := range values {
+		encoded[base64.StdEncoding.EncodeToString([]byte(key))] = base64.StdEncoding.EncodeToString([]byte(fmt.Sprint(value)))
+	}
+
+	bytes, err := json.Marshal(encoded)
 	if err != nil {
 		return "{}"
 	}
 
-	return string(bytes)
+	return STREAM_MESSAGE_VALUES_BASE64_PREFIX + string(bytes)
+}
+
+// Deserializes message values written by SerializeStreamMessageValues
+func DeserializeStreamMessageValues(data string) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+
+	encoded, ok := strings.CutPrefix(data, STREAM_MESSAGE_VALUES_BASE64_PREFIX)
+	if !ok {
+		// Values archived before they were base64 encoded are plain JSON
+		if err := json.Unmarshal([]byte(data), &values); err != nil {
+			return nil, err
+		}
+		return values, nil
+	}
+
+	fields := make(map[string]string)
+	if err := json.Unmarshal([]byte(encoded), &fields); err != nil {
+		return nil, err
+	}
+
+	for key, value := range fields {
+		decodedKey, err := base64.StdEncoding.DecodeString(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid field name %q: %w", key, err)
+		}
+
+		decodedValue, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of field %q: %w", decodedKey, err)
+		}
+
+		values[string(decodedKey)] = string(decodedValue)
+	}
+
+	return values, nil
+}
+
+// Converts the values of a Redis stream message into a map of strings
+func StreamMessageValuesToStringMap(values map[string]interface{}) map[string]string {
+	result := make(map[string]string, len(values))
+	for key, value := range values {
+		result[key] = fmt.Sprint(value)
+	}
+
+	return result
+}
+
+// Compares two Redis stream message IDs, returns -1 when a is older than b, 1 when a is newer and 0 when they are equal
+func CompareStreamMessageIDs(a string, b string) int {
+	aTimestamp, aSequence := SplitStreamMessageID(a)
+	bTimestamp, bSequence := SplitStreamMessageID(b)
+
+	switch {
+	case aTimestamp < bTimestamp:
+		return -1
+	case aTimestamp > bTimestamp:
+		return 1
+	case aSequence < bSequence:
+		return -1
+	case aSequence > bSequence:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Splits a Redis stream message ID into its timestamp and sequence number, a missing sequence number is 0
+func SplitStreamMessageID(id string) (int64, int64) {
+	timestamp, sequence, _ := strings.Cut(id, "-")
+	return ParseInt64(timestamp), ParseInt64(sequence)
+}
+
+// Parses a Redis stream message ID, unlike SplitStreamMessageID it fails when either part is not an unsigned number
+func ParseStreamMessageID(id string) (int64, int64, error) {
+	timestampPart, sequencePart, hasSequence := strings.Cut(id, "-")
+	timestamp, err := strconv.ParseUint(timestampPart, 10, 63)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid stream message ID: %s", id)
+	}
+
+	var sequence uint64
+	if hasSequence {
+		sequence, err = strconv.ParseUint(sequencePart, 10, 63)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid stream message ID: %s", id)
+		}
+	}
+
+	return int64(timestamp), int64(sequence), nil
 }
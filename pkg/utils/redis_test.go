@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompareStreamMessageIDs(t *testing.T) {
+	tests := []struct {
+		a        string
+		b        string
+		expected int
+	}{
+		{"1-0", "1-0", 0},
+		{"1-0", "2-0", -1},
+		{"2-0", "1-5", 1},
+		{"1-2", "1-10", -1}, // Sequence numbers compare numerically
+		{"10-0", "9-0", 1},  // Timestamps compare numerically
+		{"5", "5-0", 0},     // Missing sequence number
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"_"+tt.b, func(t *testing.T) {
+			result := CompareStreamMessageIDs(tt.a, tt.b)
+			if result != tt.expected {
+				t.Errorf("CompareStreamMessageIDs(%q, %q) = %v; want %v", tt.a, tt.b, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseStreamMessageID(t *testing.T) {
+	tests := []struct {
+		id          string
+		timestamp   int64
+		sequence    int64
+		expectError bool
+	}{
+		{id: "1526919030474-55", timestamp: 1526919030474, sequence: 55},
+		{id: "1526919030474", timestamp: 1526919030474},
+		{id: "0-0"},
+		{id: "", expectError: true},
+		{id: "yesterday", expectError: true},
+		{id: "1-", expectError: true},
+		{id: "-1", expectError: true},
+		{id: "1-2-3", expectError: true},
+		{id: "$", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			timestamp, sequence, err := ParseStreamMessageID(tt.id)
+			if (err != nil) != tt.expectError {
+				t.Fatalf("ParseStreamMessageID(%q) error = %v; want error %v", tt.id, err, tt.expectError)
+			}
+			if timestamp != tt.timestamp || sequence != tt.sequence {
+				t.Errorf("ParseStreamMessageID(%q) = %d, %d; want %d, %d", tt.id, timestamp, sequence, tt.timestamp, tt.sequence)
+			}
+		})
+	}
+}
+
+func TestSerializeStreamMessageValues(t *testing.T) {
+	values := map[string]interface{}{
+		"payload":            string([]byte{0x00, 0xff, 0xfe, 0x80}),
+		string([]byte{0xc3}): "text",
+	}
+
+	result, err := DeserializeStreamMessageValues(SerializeStreamMessageValues(values))
+	if err != nil {
+		t.Fatalf("DeserializeStreamMessageValues() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(result, values) {
+		t.Errorf("DeserializeStreamMessageValues() = %q; want %q", result, values)
+	}
+}